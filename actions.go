@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// meetingAction is an action item or decision, tied to the line it came from.
+type meetingAction struct {
+	Text      string `json:"text"`
+	Owner     string `json:"owner,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Line      int    `json:"line"`
+}
+
+type meetingActions struct {
+	Title       string          `json:"title"`
+	Video       string          `json:"video"`
+	ActionItems []meetingAction `json:"action_items"`
+	Decisions   []meetingAction `json:"decisions"`
+}
+
+// extractActions asks the chat API for the action items and decisions in a
+// transcript, trusting only the line numbers it answers with for timestamps.
+func extractActions(inputFile string, transcriptItems []TranscriptItem) (meetingActions, error) {
+	actions := meetingActions{Video: filepath.Base(inputFile)}
+
+	var b strings.Builder
+	for index, transcriptItem := range transcriptItems {
+		fmt.Fprintf(&b, "%d: ", index)
+		if transcriptItem.Speaker != "" {
+			fmt.Fprintf(&b, "%s: ", transcriptItem.Speaker)
+		}
+		b.WriteString(transcriptItem.Text + "\n")
+	}
+
+	system := "You take minutes for meetings. You receive numbered transcript lines, some starting with the speaker's name, " +
+		"and reply with a JSON object with \"action_items\", the tasks someone agreed to do, and \"decisions\", what the group " +
+		"settled on. Each is an array of objects with \"text\", a short sentence, \"owner\", who will do it if anyone was named, " +
+		"and \"line\", the number of the line it was said on. Leave an array empty rather than guessing."
+	reply, err := chatCompletion(system, b.String())
+	if err != nil {
+		return actions, err
+	}
+
+	var result struct {
+		ActionItems []meetingAction `json:"action_items"`
+		Decisions   []meetingAction `json:"decisions"`
+	}
+	if err := json.Unmarshal([]byte(reply), &result); err != nil {
+		return actions, fmt.Errorf("could not read action items: %w", err)
+	}
+
+	attach := func(found []meetingAction) []meetingAction {
+		var kept []meetingAction
+		for _, action := range found {
+			if action.Line < 0 || action.Line >= len(transcriptItems) || strings.TrimSpace(action.Text) == "" {
+				continue
+			}
+			action.Timestamp = transcriptItems[action.Line].StartTime
+			kept = append(kept, action)
+		}
+		return kept
+	}
+	actions.ActionItems = attach(result.ActionItems)
+	actions.Decisions = attach(result.Decisions)
+	return actions, nil
+}
+
+func actionsMarkdown(actions meetingActions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", actions.Title)
+
+	section := func(heading string, found []meetingAction, checkbox string) {
+		fmt.Fprintf(&b, "## %s\n\n", heading)
+		if len(found) == 0 {
+			b.WriteString("None.\n\n")
+			return
+		}
+		for _, action := range found {
+			fmt.Fprintf(&b, "- %s%s", checkbox, action.Text)
+			if action.Owner != "" {
+				fmt.Fprintf(&b, " (%s)", action.Owner)
+			}
+			seconds, _ := parseTimeToSeconds(action.Timestamp)
+			fmt.Fprintf(&b, " `%s`\n", formatDuration(seconds))
+		}
+		b.WriteString("\n")
+	}
+	section("Action items", actions.ActionItems, "[ ] ")
+	section("Decisions", actions.Decisions, "")
+	return b.String()
+}
+
+func postActions(webhook string, actions meetingActions) error {
+	if err := requireOnline("action items to a webhook"); err != nil {
+		return err
+	}
+	body, err := json.Marshal(actions)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post action items: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// actionsFlags are the options of tsplice actions.
+type actionsFlags struct {
+	format   string
+	webhook  string
+	calendar string
+}
+
+func (f *actionsFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "md", "File format, md or json")
+	fs.StringVar(&f.webhook, "webhook", "", "URL to POST the action items to as JSON, instead of saving a file")
+	fs.StringVar(&f.calendar, "calendar", "", "ICS file to title the list after the meeting the video was recorded in")
+}
+
+func runActions(args []string) error {
+	fs := newCommandFlagSet("actions")
+	var flags actionsFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice actions [options] <input-file>")
+	}
+	if flags.format != "md" && flags.format != "json" {
+		return fmt.Errorf("--format must be md or json")
+	}
+	if flags.webhook != "" && !isURL(flags.webhook) {
+		return fmt.Errorf("--webhook must be a URL")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+
+	// Finding action items always needs the chat API
+	if err := setupAPIKey(); err != nil {
+		return err
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+	if err != nil {
+		return err
+	}
+	if len(transcriptItems) == 0 {
+		return fmt.Errorf("transcript is empty")
+	}
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	title := basename
+	if flags.calendar != "" {
+		event, ok, err := meetingFor(flags.calendar, inputFile)
+		if err != nil {
+			return err
+		}
+		if ok {
+			title = event.Summary + ", " + event.Start.Local().Format("January 2, 2006")
+		}
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Finding action items and decisions..."))
+	actions, err := extractActions(inputFile, transcriptItems)
+	if err != nil {
+		return err
+	}
+	actions.Title = title
+	summary := fmt.Sprintf("%d action items and %d decisions", len(actions.ActionItems), len(actions.Decisions))
+
+	if flags.webhook != "" {
+		if err := postActions(flags.webhook, actions); err != nil {
+			return err
+		}
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Posted "+summary+" to "+flags.webhook))
+		return nil
+	}
+
+	outputFile := filepath.Join(filepath.Dir(inputFile), basename+"_actions.md")
+	content := []byte(actionsMarkdown(actions))
+	if flags.format == "json" {
+		outputFile = filepath.Join(filepath.Dir(inputFile), basename+"_actions.json")
+		if content, err = json.MarshalIndent(actions, "", "  "); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(outputFile, content, 0644); err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved "+summary+" to "+outputFile))
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// exportStats writes analytics as JSON and/or CSV next to the input. An empty
+// format writes both.
+func exportStats(inputFile string, stats recordingStats, format string) ([]string, error) {
+	base := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "_stats"
+	var files []string
+
+	if format == "" || format == "json" {
+		content, err := json.MarshalIndent(struct {
+			File string `json:"file"`
+			recordingStats
+		}{File: filepath.Base(inputFile), recordingStats: stats}, "", "  ")
+		if err != nil {
+			return files, err
+		}
+		if err := os.WriteFile(base+".json", append(content, '\n'), 0644); err != nil {
+			return files, err
+		}
+		files = append(files, base+".json")
+	}
+
+	if format == "" || format == "csv" {
+		if err := writeStatsCSV(base+".csv", filepath.Base(inputFile), stats); err != nil {
+			return files, err
+		}
+		files = append(files, base+".csv")
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("unknown format '%s', expected json or csv", format)
+	}
+
+	return files, nil
+}
+
+// writeStatsCSV uses a long file,metric,label,value layout so that exports from
+// many recordings can simply be concatenated.
+func writeStatsCSV(path string, file string, stats recordingStats) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := csv.NewWriter(out)
+	number := func(value float64) string { return fmt.Sprintf("%.2f", value) }
+	rows := [][]string{
+		{"file", "metric", "label", "value"},
+		{file, "total_words", "", fmt.Sprintf("%d", stats.TotalWords)},
+		{file, "duration_seconds", "", number(stats.Duration)},
+		{file, "words_per_minute", "", number(stats.WordsPerMinute)},
+	}
+	for _, bucket := range stats.Rate {
+		rows = append(rows, []string{file, "rate_words_per_minute", fmt.Sprintf("%d", bucket.Minute), number(bucket.WordsPerMinute)})
+	}
+	for _, speaker := range stats.Speakers {
+		rows = append(rows,
+			[]string{file, "speaker_seconds", speaker.Speaker, number(speaker.Seconds)},
+			[]string{file, "speaker_share", speaker.Speaker, number(speaker.Share)},
+			[]string{file, "speaker_words", speaker.Speaker, fmt.Sprintf("%d", speaker.Words)},
+		)
+	}
+	for _, silence := range stats.Silences {
+		rows = append(rows, []string{file, "silence_seconds", formatTimestamp(silence.Start), number(silence.Duration)})
+	}
+	for filler, count := range stats.Fillers {
+		rows = append(rows, []string{file, "filler_count", filler, fmt.Sprintf("%d", count)})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// statsFlags are the options of tsplice stats.
+type statsFlags struct {
+	format string
+}
+
+func (f *statsFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "", "Export format, json or csv (default both)")
+}
+
+func runStats(args []string) error {
+	fs := newCommandFlagSet("stats")
+	var flags statsFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: tsplice stats [options] <input-file>...")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, inputFile := range positional {
+		transcriptItems, err := loadTranscript(vttPath(inputFile))
+		if err != nil {
+			return fmt.Errorf("no transcript found for %s, run tsplice on it first", inputFile)
+		}
+
+		stats := computeStats(toListItems(transcriptItems), cfg.Fillers)
+		files, err := exportStats(inputFile, stats, flags.format)
+		if err != nil {
+			return err
+		}
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Exported analytics to "+strings.Join(files, " and ")))
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Done."))
+	return nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	audiogramSize = 1080
+	// audiogramCaptionWords is how many words are shown on screen at once
+	audiogramCaptionWords = 4
+)
+
+type audiogramsDoneMsg struct {
+	files []string
+}
+
+type audiogramOptions struct {
+	// Image is the background, a frame of the source is used when it's empty
+	Image    string
+	Waveform bool
+	Colors   audiogramColors
+	// Bleeps are muted, as ranges on the source timeline
+	Bleeps []timeRange
+	// Speakers color and place each speaker's captions
+	Speakers speakerStyles
+}
+
+// audiogramColors take the same values as the list colors in the config, so
+// an ANSI color number or a hex code.
+type audiogramColors struct {
+	Waveform   string `toml:"waveform"`
+	Background string `toml:"background"`
+	Captions   string `toml:"captions"`
+}
+
+func exportAudiogramsCmd(inputFile string, items []list.Item, words []Word, opts audiogramOptions) tea.Cmd {
+	return func() tea.Msg {
+		files, err := exportAudiograms(inputFile, items, words, opts)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return audiogramsDoneMsg{files: files}
+	}
+}
+
+// exportAudiograms renders every selected segment as its own square video of
+// a still image or animated waveform, the segment's audio, and burned in
+// captions.
+func exportAudiograms(inputFile string, items []list.Item, words []Word, opts audiogramOptions) ([]string, error) {
+	segments := selectedSegments(items)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no segments selected")
+	}
+
+	workDir, err := tempDir("tsplice-audiogram-")
+	if err != nil {
+		return nil, err
+	}
+	defer removeTemp(workDir)
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	var files []string
+	for index, segment := range segments {
+		image := opts.Image
+		if image == "" && !opts.Waveform {
+			image = filepath.Join(workDir, fmt.Sprintf("frame%d.png", index))
+			if err := extractFrame(inputFile, segment.start, image); err != nil {
+				return files, err
+			}
+		}
+
+		captions := filepath.Join(workDir, fmt.Sprintf("captions%d.srt", index))
+		if err := os.WriteFile(captions, []byte(audiogramCaptions(words, segment, items, opts.Speakers)), 0644); err != nil {
+			return files, err
+		}
+
+		outputFile := filepath.Join(filepath.Dir(inputFile), fmt.Sprintf("%s_audiogram_%02d.mp4", basename, index+1))
+		if err := renderAudiogram(inputFile, image, captions, segment, opts, outputFile); err != nil {
+			return files, err
+		}
+		files = append(files, outputFile)
+	}
+
+	return files, nil
+}
+
+func extractFrame(inputFile string, at float64, outputFile string) error {
+	if err := execute.Run("ffmpeg", "-y", "-ss", fmt.Sprintf("%.3f", at), "-i", inputFile, "-frames:v", "1", outputFile); err != nil {
+		return fmt.Errorf("failed to extract audiogram background: %w", err)
+	}
+	return nil
+}
+
+func renderAudiogram(inputFile, image, captions string, s segment, opts audiogramOptions, outputFile string) error {
+	var args []string
+	if image != "" {
+		args = append(args, "-loop", "1", "-i", image)
+	} else {
+		args = append(args, "-f", "lavfi", "-i", fmt.Sprintf("color=c=%s:s=%[2]dx%[2]d:r=30", ffmpegColor(opts.Colors.Background), audiogramSize))
+	}
+	args = append(args, "-ss", fmt.Sprintf("%.3f", s.start), "-t", fmt.Sprintf("%.3f", s.end-s.start), "-i", inputFile)
+
+	background := fmt.Sprintf("[0:v]scale=%[1]d:%[1]d:force_original_aspect_ratio=increase,crop=%[1]d:%[1]d", audiogramSize)
+	subtitles := fmt.Sprintf("format=yuv420p,subtitles=%s:force_style='FontSize=22,Outline=2,Alignment=2,MarginV=60,PrimaryColour=%s'[v]",
+		escapeFilterPath(captions), assColor(opts.Colors.Captions))
+
+	// The input is already cut to the segment, so bleeps are mapped onto it
+	audio, mute := "1:a:0", rangeExpression(opts.Bleeps, []segment{s})
+
+	var filter string
+	if opts.Waveform {
+		waves := "[1:a:0]"
+		if mute != "" {
+			filter = fmt.Sprintf("[1:a:0]volume=volume=0:enable='%s',asplit[muted][wavesin];", mute)
+			audio, waves = "[muted]", "[wavesin]"
+		}
+		filter += fmt.Sprintf("%sshowwaves=s=%dx%d:mode=cline:rate=30:colors=%s,format=yuva420p[waves];%s[bg];[bg][waves]overlay=0:(H-h)/2:shortest=1,%s",
+			waves, audiogramSize, audiogramSize/3, ffmpegColor(opts.Colors.Waveform), background, subtitles)
+	} else {
+		filter = background + "," + subtitles
+		if mute != "" {
+			filter += fmt.Sprintf(";[1:a:0]volume=volume=0:enable='%s'[muted]", mute)
+			audio = "[muted]"
+		}
+	}
+
+	args = append(args, "-filter_complex", filter, "-map", "[v]", "-map", audio)
+	args = append(args, videoEncoderArgs(!opts.Waveform)...)
+	args = append(args,
+		"-c:a", "aac",
+		"-shortest",
+	)
+
+	return renderTo(outputFile, func(partial string) error {
+		if err := execute.Run("ffmpeg", append(append([]string{"-y"}, args...), partial)...); err != nil {
+			return fmt.Errorf("failed to render audiogram: %w", err)
+		}
+		return nil
+	})
+}
+
+// audiogramCaptions writes the words spoken during a segment as short SRT
+// cues, timed from the start of the segment. A cue never runs from one
+// speaker into the next, so each is styled as whoever is saying it.
+func audiogramCaptions(words []Word, s segment, items []list.Item, styles speakerStyles) string {
+	inside := wordsBetween(words, s.start, s.end)
+
+	var b strings.Builder
+	cue := 0
+	for index := 0; index < len(inside); {
+		speaker := speakerAt(items, inside[index].Start)
+		chunk := inside[index:min(index+audiogramCaptionWords, len(inside))]
+		for length := 1; length < len(chunk); length++ {
+			if speakerAt(items, chunk[length].Start) != speaker {
+				chunk = chunk[:length]
+				break
+			}
+		}
+		index += len(chunk)
+
+		var text []string
+		for _, word := range chunk {
+			text = append(text, word.Text)
+		}
+
+		cue++
+		start := max(chunk[0].Start-s.start, 0)
+		end := min(chunk[len(chunk)-1].End, s.end) - s.start
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", cue, srtTimestamp(start), srtTimestamp(end), styles.srtText(speaker, strings.Join(text, " ")))
+	}
+
+	return b.String()
+}
+
+// escapeFilterPath quotes a file path for use as a filter option.
+func escapeFilterPath(path string) string {
+	path = filepath.ToSlash(path)
+	return "'" + strings.NewReplacer(`:`, `\:`, `'`, `'\''`).Replace(path) + "'"
+}
+
+// ansiColors are the usual RGB values of the 16 basic terminal colors.
+var ansiColors = [16]string{
+	"000000", "cd0000", "00cd00", "cdcd00", "0000ee", "cd00cd", "00cdcd", "e5e5e5",
+	"7f7f7f", "ff0000", "00ff00", "ffff00", "5c5cff", "ff00ff", "00ffff", "ffffff",
+}
+
+// hexColor turns a config color, an ANSI color number or a hex code, into
+// RRGGBB.
+func hexColor(color string) string {
+	if hex, ok := strings.CutPrefix(color, "#"); ok && len(hex) == 6 {
+		return strings.ToLower(hex)
+	}
+
+	n, err := strconv.Atoi(color)
+	if err != nil || n < 0 || n > 255 {
+		return "ffffff"
+	}
+	switch {
+	case n < 16:
+		return ansiColors[n]
+	case n < 232:
+		// 6x6x6 color cube
+		levels := []int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+func ffmpegColor(color string) string {
+	return "0x" + hexColor(color)
+}
+
+// assColor writes a color the way subtitle styles expect it, &HBBGGRR.
+func assColor(color string) string {
+	hex := hexColor(color)
+	return "&H" + strings.ToUpper(hex[4:6]+hex[2:4]+hex[0:2])
+}
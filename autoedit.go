@@ -0,0 +1,129 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// soundDescription matches what transcribers write for sounds that aren't
+// speech, like [music], (silence), or ♪.
+var soundDescription = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)|♪`)
+
+// autoEditReason is why the auto-edit pass would take a line out: there's no
+// speech in it, or it's nothing but filler words. Lines to keep get "".
+func autoEditReason(text string, fillers []string) string {
+	var words []Word
+	for _, field := range strings.Fields(soundDescription.ReplaceAllString(text, " ")) {
+		if redactionToken(field) != "" {
+			words = append(words, Word{Text: field})
+		}
+	}
+	if len(words) == 0 {
+		return "no speech"
+	}
+
+	filler := make([]bool, len(words))
+	matchTerms(words, fillers, func(first, last int) {
+		for index := first; index <= last; index++ {
+			filler[index] = true
+		}
+	})
+	if !slices.Contains(filler, false) {
+		return "filler"
+	}
+	return ""
+}
+
+// startAutoEdit previews an auto-edit pass, deselecting every line it would
+// remove and marking why, without saving anything until it's applied. With
+// nothing selected yet the pass starts from the whole video, the way
+// tsplice tighten does.
+func (m model) startAutoEdit() (model, tea.Cmd) {
+	items := m.list.Items()
+	whole := !hasSelection(items)
+
+	proposed := make([]list.Item, len(items))
+	removals := 0
+	for index, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok {
+			proposed[index] = listItem
+			continue
+		}
+		if whole {
+			i.selected = true
+		}
+		if i.removal = autoEditReason(i.title, m.fillers); i.removal != "" {
+			i.selected = false
+			removals++
+		}
+		proposed[index] = i
+	}
+	if removals == 0 {
+		m.statuses = append(m.statuses, tr("Auto-edit found no filler or silent lines to remove."))
+		return m, nil
+	}
+
+	m.autoEditing = true
+	m.autoEditBefore = items
+	m.statuses = append(m.statuses, trf("Auto-edit would remove %d lines, marked ✂. Press enter on any to keep it.", removals))
+	return m, m.list.SetItems(proposed)
+}
+
+// updateAutoEdit takes the keys while an auto-edit is previewed: enter or
+// space vetoes a removal (or puts it back), F applies the pass, and esc
+// discards it. Moving around the list works as usual.
+func (m model) updateAutoEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", " ":
+		index := m.list.GlobalIndex()
+		if i, ok := m.list.SelectedItem().(item); ok && i.removal != "" {
+			i.selected = !i.selected
+			return m, m.list.SetItem(index, i)
+		}
+		return m, nil
+
+	case "F":
+		items := m.list.Items()
+		applied := make([]list.Item, len(items))
+		removed, kept := 0, 0
+		for index, listItem := range items {
+			if i, ok := listItem.(item); ok {
+				if i.removal != "" && i.selected {
+					kept++
+				} else if i.removal != "" {
+					removed++
+				}
+				i.removal = ""
+				listItem = i
+			}
+			applied[index] = listItem
+		}
+		// The whole pass is undone at once, back to before it was previewed
+		m = m.pushUndoItems(m.autoEditBefore)
+		m.autoEditing = false
+		m.autoEditBefore = nil
+		m = m.saveItems(applied)
+		m.statuses = append(m.statuses, trf("Auto-edit removed %d lines, %d kept.", removed, kept))
+		return m, m.list.SetItems(applied)
+
+	case "esc":
+		before := m.autoEditBefore
+		m.autoEditing = false
+		m.autoEditBefore = nil
+		m.statuses = append(m.statuses, tr("Auto-edit discarded."))
+		return m, m.list.SetItems(before)
+
+	case "/":
+		// Filtering would hide what's being reviewed
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
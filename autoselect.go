@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type autoSelectRule struct {
+	conditions conditionMatcher
+	selected   bool
+}
+
+type autoSelectRules []autoSelectRule
+
+func newAutoSelectRules(configs []autoSelectConfig) (autoSelectRules, error) {
+	var rules autoSelectRules
+	for _, c := range configs {
+		if c.Action != "select" && c.Action != "deselect" {
+			return nil, fmt.Errorf("auto_select action must be \"select\" or \"deselect\", got \"%s\"", c.Action)
+		}
+
+		conditions, err := newConditionMatcher(c.ruleConditions)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, autoSelectRule{conditions: conditions, selected: c.Action == "select"})
+	}
+	return rules, nil
+}
+
+// apply runs every rule in order over the items, so later rules win.
+func (rules autoSelectRules) apply(items []list.Item) []list.Item {
+	for index, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.conditions.matches(i) {
+				i.selected = rule.selected
+			}
+		}
+		items[index] = i
+	}
+	return items
+}
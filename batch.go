@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// outputSuffixes end the names of the videos tsplice makes from a source,
+// and outputPrefixes start the ones it makes from several. They're left out
+// of a batch since what's said in them is in their sources' transcripts
+// already.
+var (
+	outputSuffixes = regexp.MustCompile(`_(compiled|draft|tight|bilingual|clip_\d+|audiogram_\d+|dub_[A-Za-z-]+)$`)
+	outputPrefixes = []string{supercutPrefix, dayPrefix}
+)
+
+// isOutput is whether a video's name is one tsplice gives its outputs.
+func isOutput(name string) bool {
+	basename := strings.TrimSuffix(name, filepath.Ext(name))
+	for _, prefix := range outputPrefixes {
+		if strings.HasPrefix(basename, prefix) {
+			return true
+		}
+	}
+	return outputSuffixes.MatchString(basename)
+}
+
+// findVideos walks a folder for videos, leaving out hidden folders, tsplice's
+// own and partial outputs, and subfolders unless recursive is set.
+func findVideos(folder string, recursive bool) ([]string, error) {
+	var videos []string
+	err := filepath.WalkDir(folder, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			// A folder that can't be read shouldn't stop the rest being found
+			if entry != nil && entry.IsDir() && path != folder {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path != folder && (!recursive || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := entry.Name()
+		basename := strings.TrimSuffix(name, filepath.Ext(name))
+		// Partial files are renders still going, or ones that never finished
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(basename, ".partial") || !slices.Contains(validExtensions, strings.ToLower(filepath.Ext(name))) {
+			return nil
+		}
+		if isOutput(name) {
+			return nil
+		}
+		videos = append(videos, path)
+		return nil
+	})
+	return videos, err
+}
+
+// batchFlags are the options of tsplice batch.
+type batchFlags struct {
+	lang         string
+	prompt       string
+	noSubfolders bool
+}
+
+func (f *batchFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.lang, "lang", defaultLanguage, "Language for transcription (e.g. en, es, fr)")
+	fs.StringVar(&f.prompt, "prompt", defaultPrompt, "Optional prompt used to create a more accurate transcription of every video")
+	fs.BoolVar(&f.noSubfolders, "no-subfolders", false, "Only transcribe the videos directly in the folder")
+}
+
+func runBatch(args []string) error {
+	fs := newCommandFlagSet("batch")
+	var flags batchFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice batch [options] <folder>")
+	}
+
+	folder := positional[0]
+	if info, err := os.Stat(folder); err != nil || !info.IsDir() {
+		return fmt.Errorf("'%s' is not a folder", folder)
+	}
+
+	videos, err := findVideos(folder, !flags.noSubfolders)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, video := range videos {
+		if _, err := os.Stat(vttPath(video)); os.IsNotExist(err) {
+			pending = append(pending, video)
+		}
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Found %d videos, %d already transcribed", len(videos), len(videos)-len(pending))))
+	if len(pending) == 0 {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Nothing to transcribe."))
+		return nil
+	}
+
+	if err := checkProvider(); err != nil {
+		return err
+	}
+	if transcribesWithOpenAI() {
+		if err := setupAPIKey(); err != nil {
+			return err
+		}
+	}
+
+	transcriber := newTranscriber(flags.lang, flags.prompt)
+	var failed []string
+	for index, video := range pending {
+		name, err := filepath.Rel(folder, video)
+		if err != nil {
+			name = video
+		}
+		fmt.Println(BulletStyle.Render("│"))
+		fmt.Println(BulletStyle.Render("├") + TagStyle.Render(fmt.Sprintf("[%d/%d] ", index+1, len(pending))) + TextStyle.Render(name))
+
+		// A video that's open in the editor is left for it to transcribe
+		release, _, err := lockVideo(vttPath(video))
+		if err != nil {
+			fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render("Skipping, "+err.Error()))
+			failed = append(failed, name)
+			continue
+		}
+		transcriptItems, err := transcriptFor(video, transcriber)
+		release()
+		if err != nil {
+			fmt.Println(BulletStyle.Render("├") + ErrorStyle.Render("Could not transcribe it: "+err.Error()))
+			failed = append(failed, name)
+			continue
+		}
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Saved %s, %d lines", filepath.Base(vttPath(video)), len(transcriptItems))))
+	}
+
+	fmt.Println(BulletStyle.Render("│"))
+	if len(failed) > 0 {
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Not transcribed:"))
+		for _, name := range failed {
+			fmt.Println(BulletStyle.Render("├────") + DimTextStyle.Render(name))
+		}
+		return fmt.Errorf("%d of %d videos weren't transcribed, run tsplice batch again to retry them", len(failed), len(pending))
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Transcribed %d videos.", len(pending))))
+	return nil
+}
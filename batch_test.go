@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSameNamedVideosKeepTheirOwnTranscripts(t *testing.T) {
+	folder := t.TempDir()
+	for _, sub := range []string{"monday", "tuesday"} {
+		if err := os.MkdirAll(filepath.Join(folder, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(folder, sub, "talk.mp4"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	videos, err := findVideos(folder, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(videos) != 2 {
+		t.Fatalf("found %d videos, want 2", len(videos))
+	}
+
+	// Each video's transcript says which folder it's in
+	for _, video := range videos {
+		transcript := "WEBVTT\n\n00:00:00.000 --> 00:00:02.000\n" + filepath.Base(filepath.Dir(video)) + "\n"
+		if err := os.WriteFile(vttPath(video), []byte(transcript), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if vttPath(videos[0]) == vttPath(videos[1]) {
+		t.Fatalf("both videos share the transcript %s", vttPath(videos[0]))
+	}
+
+	for _, video := range videos {
+		if want := filepath.Join(filepath.Dir(video), "talk.vtt"); vttPath(video) != want {
+			t.Errorf("vttPath(%s) = %s, want %s", video, vttPath(video), want)
+		}
+		transcriptItems, err := transcriptFor(video, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transcriptItems) != 1 || transcriptItems[0].Text != filepath.Base(filepath.Dir(video)) {
+			t.Errorf("transcript for %s is %+v, want its own", video, transcriptItems)
+		}
+	}
+}
+
+func TestBatchLeavesOutTsplicesOwnOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		skipped bool
+	}{
+		{"talk.mp4", false},
+		{"talk_compiled.mp4", true},
+		{"talk_highlights.mp4", false},
+		{"talk_draft.mp4", true},
+		{"talk_tight.mp4", true},
+		{"talk_clip_03.mp4", true},
+		{"talk_clip_03_draft.mp4", true},
+		{"talk_audiogram_01.mp4", true},
+		{"talk_bilingual.mp4", true},
+		{"talk_dub_es.mp4", true},
+		{"talk_dub_pt-BR.mp4", true},
+		{supercutPath("Big news"), true},
+		{dayPrefix + "2025-06-29.mp4", true},
+		{"talk.partial.mp4", true},
+		{"supercut.mp4", false},
+	}
+
+	folder := t.TempDir()
+	for _, test := range tests {
+		if err := os.WriteFile(filepath.Join(folder, test.name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	videos, err := findVideos(folder, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		found := slices.Contains(videos, filepath.Join(folder, test.name))
+		if found == test.skipped {
+			t.Errorf("%s found is %v, want %v", test.name, found, !test.skipped)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type benchProvider struct {
+	transcribe    func(audioFile string) (string, transcriptDetails, error)
+	costPerMinute float64
+}
+
+var benchProviders = map[string]benchProvider{
+	"openai":     {transcribe: openAITranscriber{}.Transcribe, costPerMinute: 0.006},
+	"whisper":    {transcribe: whisperTranscriber{}.Transcribe},
+	"azure":      {transcribe: azureTranscriber{}.Transcribe, costPerMinute: 0.006},
+	"deepgram":   {transcribe: deepgramTranscriber{}.Transcribe, costPerMinute: 0.0043},
+	"assemblyai": {transcribe: assemblyAITranscriber{}.Transcribe, costPerMinute: 0.0062},
+}
+
+type benchResult struct {
+	provider string
+	elapsed  time.Duration
+	cost     float64
+	segments int
+	words    []string
+	err      error
+}
+
+// benchFlags are the options of tsplice bench.
+type benchFlags struct {
+	providers string
+	sample    int
+}
+
+func (f *benchFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.providers, "providers", "openai", "Comma-separated list of transcription backends to compare")
+	fs.IntVar(&f.sample, "sample", 60, "Seconds of audio to transcribe with each backend")
+}
+
+func runBench(args []string) error {
+	fs := newCommandFlagSet("bench")
+	var flags benchFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice bench [options] <input-file>")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+
+	names := strings.Split(flags.providers, ",")
+	for _, name := range names {
+		if strings.TrimSpace(name) == "openai" {
+			if err := setupAPIKey(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Extracting a %ds sample with ffmpeg...", flags.sample)))
+	audioFile, err := extractAudioSample(inputFile, flags.sample)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(audioFile)
+
+	duration, err := probeDuration(audioFile)
+	if err != nil {
+		return err
+	}
+
+	var results []benchResult
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		provider, ok := benchProviders[name]
+		if !ok {
+			results = append(results, benchResult{provider: name, err: fmt.Errorf("not configured")})
+			continue
+		}
+
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Transcribing with "+name+"..."))
+		started := time.Now()
+		vttContent, _, err := provider.transcribe(audioFile)
+		result := benchResult{provider: name, elapsed: time.Since(started), err: err}
+		if err == nil {
+			transcriptItems, _ := parseVTT(vttContent)
+			result.segments = len(transcriptItems)
+			result.cost = duration / 60 * provider.costPerMinute
+			for _, transcriptItem := range transcriptItems {
+				result.words = append(result.words, normalizeWords(transcriptItem.Text)...)
+			}
+		}
+		results = append(results, result)
+	}
+
+	printBenchResults(results)
+	return nil
+}
+
+func printBenchResults(results []benchResult) {
+	var baseline *benchResult
+	for i := range results {
+		if results[i].err == nil {
+			baseline = &results[i]
+			break
+		}
+	}
+
+	for _, result := range results {
+		fmt.Println(BulletStyle.Render("│"))
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render(result.provider))
+
+		if result.err != nil {
+			fmt.Println(BulletStyle.Render("├────") + ErrorStyle.Render("✗ "+result.err.Error()))
+			continue
+		}
+
+		difference := "baseline"
+		if baseline != nil && baseline.provider != result.provider {
+			distance := wordDistance(baseline.words, result.words)
+			difference = fmt.Sprintf("%.1f%% vs %s", 100*float64(distance)/float64(max(1, len(baseline.words))), baseline.provider)
+		}
+
+		rows := [][2]string{
+			{"time", fmt.Sprintf("%.1fs", result.elapsed.Seconds())},
+			{"cost", fmt.Sprintf("$%.4f", result.cost)},
+			{"segments", fmt.Sprintf("%d", result.segments)},
+			{"words", fmt.Sprintf("%d", len(result.words))},
+			{"difference", difference},
+		}
+		for _, row := range rows {
+			spaces := strings.Repeat(" ", 12-len(row[0]))
+			fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(row[0]) + DimTextStyle.Render(spaces+row[1]))
+		}
+	}
+
+	fmt.Println(BulletStyle.Render("│"))
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Benchmark finished."))
+}
+
+var wordRegex = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+func normalizeWords(text string) []string {
+	return wordRegex.FindAllString(strings.ToLower(text), -1)
+}
+
+// wordDistance returns the Levenshtein distance between two word sequences.
+func wordDistance(a, b []string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// botHighlights is how many transcript lines the highlight reel is cut from
+const botHighlights = 8
+
+// botJob is a video sent in from chat, answered on callback when it's done.
+type botJob struct {
+	url      string
+	callback string
+}
+
+type bot struct {
+	publicURL string
+	token     string
+	secret    string
+	jobs      chan botJob
+
+	mu    sync.Mutex
+	files map[string]string
+}
+
+// highlights asks the chat API for a summary and the most interesting lines.
+func highlights(transcriptItems []TranscriptItem) (string, []int, error) {
+	var b strings.Builder
+	for index, transcriptItem := range transcriptItems {
+		fmt.Fprintf(&b, "%d: %s\n", index, transcriptItem.Text)
+	}
+
+	system := fmt.Sprintf("You summarize video transcripts. You receive numbered transcript lines and reply with a JSON object "+
+		"with a \"summary\" of two or three sentences, and \"highlights\", an array of the numbers of up to %d lines "+
+		"that would make the best highlight reel, in order.", botHighlights)
+	reply, err := chatCompletion(system, b.String())
+	if err != nil {
+		return "", nil, err
+	}
+
+	var result struct {
+		Summary    string `json:"summary"`
+		Highlights []int  `json:"highlights"`
+	}
+	if err := json.Unmarshal([]byte(reply), &result); err != nil {
+		return "", nil, fmt.Errorf("could not read summary: %w", err)
+	}
+	return result.Summary, result.Highlights, nil
+}
+
+// process runs a video through the pipeline and returns the reply for chat.
+func (b *bot) process(job botJob) (string, error) {
+	inputFile, err := downloadVideo(job.url)
+	if err != nil {
+		return "", err
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+	if err != nil {
+		return "", err
+	}
+
+	summary, picks, err := highlights(transcriptItems)
+	if err != nil {
+		return "", err
+	}
+
+	var segments []segment
+	for _, index := range picks {
+		if index < 0 || index >= len(transcriptItems) {
+			continue
+		}
+		start, err := parseTimeToSeconds(transcriptItems[index].StartTime)
+		if err != nil {
+			continue
+		}
+		end, err := parseTimeToSeconds(transcriptItems[index].EndTime)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment{start: start, end: end})
+	}
+	if len(segments) == 0 {
+		return summary, nil
+	}
+
+	outputFile, err := compileSegments(inputFile, segments, compileOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	b.files[filepath.Base(outputFile)] = outputFile
+	b.mu.Unlock()
+
+	return summary + "\n\nHighlight reel: " + strings.TrimSuffix(b.publicURL, "/") + "/files/" + url.PathEscape(filepath.Base(outputFile)), nil
+}
+
+// work handles one video at a time, ffmpeg already uses every core.
+func (b *bot) work() {
+	for job := range b.jobs {
+		reply, err := b.process(job)
+		if err != nil {
+			reply = "Could not process " + job.url + ": " + err.Error()
+		}
+		if err := postReply(job.callback, reply); err != nil {
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Could not reply for "+job.url+": "+err.Error()))
+		}
+	}
+}
+
+// queue adds a job without making chat wait when the queue is full.
+func (b *bot) queue(job botJob) bool {
+	select {
+	case b.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// postReply sends the message the way both Slack response URLs and Discord
+// webhooks expect it.
+func postReply(callback string, text string) error {
+	if err := requireOnline("the summary to a chat webhook"); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"text": text, "content": text})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(callback, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// verifySlack checks the signature Slack puts on every request.
+func verifySlack(secret string, r *http.Request, body []byte) bool {
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(seconds, 0)).Abs() > 5*time.Minute {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Slack-Signature")))
+}
+
+// handleSlack takes a slash command whose text is the video URL.
+func (b *bot) handleSlack(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !verifySlack(b.secret, r, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	videoURL := strings.TrimSpace(r.FormValue("text"))
+	if !isURL(videoURL) {
+		writeJSON(w, http.StatusOK, map[string]string{"response_type": "ephemeral", "text": "Send a video URL, like /tsplice https://youtu.be/..."})
+		return
+	}
+
+	if !b.queue(botJob{url: videoURL, callback: r.FormValue("response_url")}) {
+		writeJSON(w, http.StatusOK, map[string]string{"response_type": "ephemeral", "text": "Too many videos queued, try again in a bit."})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"response_type": "ephemeral", "text": "Working on " + videoURL + ", the summary and highlight reel will be posted here."})
+}
+
+// handleWebhook takes {"url": ..., "callback": ...} from any other bot, such
+// as one for Discord that passes a channel webhook as the callback.
+func (b *bot) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if !hmac.Equal([]byte(r.Header.Get("Authorization")), []byte("Bearer "+b.token)) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		URL      string `json:"url"`
+		Callback string `json:"callback"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !isURL(req.URL) || !isURL(req.Callback) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "url and callback are required"})
+		return
+	}
+
+	if !b.queue(botJob{url: req.URL, callback: req.Callback}) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "too many videos queued"})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "queued"})
+}
+
+// handleFile only serves highlight reels the bot made itself.
+func (b *bot) handleFile(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	file, ok := b.files[r.PathValue("name")]
+	b.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, file)
+}
+
+// botFlags are the options of tsplice bot.
+type botFlags struct {
+	addr      string
+	publicURL string
+	token     string
+}
+
+func (f *botFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.addr, "addr", "127.0.0.1:8421", "Address to listen on, like :8421 for every interface")
+	fs.StringVar(&f.publicURL, "public-url", "", "URL the server is reachable at, used for download links")
+	fs.StringVar(&f.token, "token", "", "Bearer token required on /webhook requests")
+}
+
+func runBot(args []string) error {
+	fs := newCommandFlagSet("bot")
+	var flags botFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || flags.publicURL == "" {
+		return fmt.Errorf("usage: tsplice bot --public-url=<url> [options]")
+	}
+	// Without either, anyone who can reach the server could have it download
+	// and post wherever they like on the owner's API key
+	secret := os.Getenv("SLACK_SIGNING_SECRET")
+	if flags.token == "" && secret == "" {
+		return fmt.Errorf("set --token for /webhook or SLACK_SIGNING_SECRET for /slack, so only your bots can send videos")
+	}
+	if err := requireOnline("summaries to chat"); err != nil {
+		return err
+	}
+
+	if err := setupAPIKey(); err != nil {
+		return err
+	}
+
+	b := &bot{
+		publicURL: flags.publicURL,
+		token:     flags.token,
+		secret:    secret,
+		jobs:      make(chan botJob, 16),
+		files:     map[string]string{},
+	}
+	go b.work()
+
+	// Each route is only served when there's a way to check who's calling it
+	mux := http.NewServeMux()
+	var routes []string
+	if b.secret != "" {
+		mux.HandleFunc("POST /slack", b.handleSlack)
+		routes = append(routes, "/slack")
+	}
+	if b.token != "" {
+		mux.HandleFunc("POST /webhook", b.handleWebhook)
+		routes = append(routes, "/webhook")
+	}
+	mux.HandleFunc("GET /files/{name}", b.handleFile)
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Listening on "+flags.addr+" for "+strings.Join(routes, " and ")))
+	return http.ListenAndServe(flags.addr, mux)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// breathFrame is the length of each analyzed window, in seconds
+	breathFrame = 0.05
+	// breathWindow is how far into a segment from either end breaths are
+	// looked for, since mid-sentence breaths usually sound natural
+	breathWindow = 0.75
+	// Breaths are quieter than speech but louder than the room, with most of
+	// their energy above breathCutoff
+	breathMaxLevel = -30.0
+	breathMinLevel = -60.0
+	breathCutoff   = 2000
+	// breathHighShare is how far below the full level, in dB, the high
+	// frequencies can be for a frame to still sound like a breath
+	breathHighShare = 6.0
+	breathMinLength = 0.1
+	breathMaxLength = 0.8
+	// breathVolume is how loud a breath is left, rather than muting it and
+	// leaving an unnatural hole in the room tone
+	breathVolume = 0.1
+)
+
+// detectBreaths finds breaths at the start and end of each segment, returned
+// as ranges on the source timeline like bleeps.
+func detectBreaths(inputFile string, segments []segment) []timeRange {
+	dir, err := tempDir("tsplice-breaths-")
+	if err != nil {
+		return nil
+	}
+	defer removeTemp(dir)
+
+	var breaths []timeRange
+	for index, s := range segments {
+		full := filepath.Join(dir, fmt.Sprintf("segment_%d_full.txt", index))
+		high := filepath.Join(dir, fmt.Sprintf("segment_%d_high.txt", index))
+
+		// Both levels are measured per frame in one pass, the second after a
+		// highpass, so a frame can be compared against itself
+		levels := "asetnsamples=n=%d,astats=metadata=1:reset=1,ametadata=mode=print:key=lavfi.astats.Overall.RMS_level:file=%s"
+		samples := int(breathFrame * 16000)
+		err := execute.Run("ffmpeg", "-y",
+			"-ss", fmt.Sprintf("%.3f", s.start), "-t", fmt.Sprintf("%.3f", s.end-s.start),
+			"-i", inputFile,
+			"-filter_complex", fmt.Sprintf("[0:a:0]aresample=16000,asplit[full][high];[full]"+levels+"[fullout];[high]highpass=f=%d,"+levels+"[highout]",
+				samples, escapeFilterPath(full), breathCutoff, samples, escapeFilterPath(high)),
+			"-map", "[fullout]", "-f", "null", "-",
+			"-map", "[highout]", "-f", "null", "-",
+		)
+		if err != nil {
+			continue
+		}
+
+		fullLevels, err := readLevels(full, "lavfi.astats.Overall.RMS_level")
+		if err != nil {
+			continue
+		}
+		highLevels, err := readLevels(high, "lavfi.astats.Overall.RMS_level")
+		if err != nil {
+			continue
+		}
+
+		for _, breath := range findBreaths(fullLevels, highLevels, s.end-s.start) {
+			breaths = append(breaths, timeRange{Start: s.start + breath.Start, End: s.start + breath.End})
+		}
+	}
+
+	return breaths
+}
+
+// findBreaths looks for runs of breath-like frames touching either end of a
+// segment, in seconds from its start.
+func findBreaths(full, high []float64, duration float64) []timeRange {
+	breathy := func(frame int) bool {
+		if frame >= len(full) || frame >= len(high) {
+			return false
+		}
+		return full[frame] < breathMaxLevel && full[frame] > breathMinLevel && full[frame]-high[frame] < breathHighShare
+	}
+
+	// A breath usually sits just inside the boundary, after a moment of room
+	// tone, so a run counts if it starts or ends anywhere in the window
+	window := int(breathWindow / breathFrame)
+	var breaths []timeRange
+	for frame := 0; frame < len(full); {
+		if !breathy(frame) {
+			frame++
+			continue
+		}
+
+		end := frame
+		for breathy(end) {
+			end++
+		}
+
+		start, stop := float64(frame)*breathFrame, min(float64(end)*breathFrame, duration)
+		length := stop - start
+		nearEdge := frame < window || end > len(full)-window
+		if nearEdge && length >= breathMinLength && length <= breathMaxLength {
+			breaths = append(breaths, timeRange{Start: start, End: stop})
+		}
+		frame = end
+	}
+
+	return breaths
+}
+
+// readLevels parses the per-frame levels ametadata printed for key, counting
+// digital silence as the quietest level.
+func readLevels(metadataFile string, levelKey string) ([]float64, error) {
+	file, err := os.Open(metadataFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var levels []float64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || key != levelKey {
+			continue
+		}
+		level, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			level = -120
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, scanner.Err()
+}
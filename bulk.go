@@ -0,0 +1,103 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const padStep = 0.25
+
+// padItem grows (or with a negative amount, shrinks) a segment on both ends.
+func padItem(i item, amount float64) item {
+	start := max(0, i.start-amount)
+	end := i.end + amount
+	if end-start < 0.1 {
+		return i
+	}
+
+	i.start, i.end = start, end
+	return i
+}
+
+// visibleIndexes returns the indexes of every item that survives the current
+// filter, or all of them when nothing is filtered.
+func visibleIndexes(l list.Model) []int {
+	items := l.Items()
+	if l.FilterState() == list.Unfiltered {
+		indexes := make([]int, len(items))
+		for i := range items {
+			indexes[i] = i
+		}
+		return indexes
+	}
+
+	targets := make([]string, len(items))
+	for i, listItem := range items {
+		targets[i] = listItem.FilterValue()
+	}
+
+	var indexes []int
+	for _, rank := range l.Filter(l.FilterValue(), targets) {
+		indexes = append(indexes, rank.Index)
+	}
+	slices.Sort(indexes)
+	return indexes
+}
+
+func (m model) updateBulk(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.bulkPending = false
+
+	var apply func(i item) item
+	// done is the status once it's applied to this many lines
+	var done func(count int) string
+
+	switch key := msg.String(); key {
+	case "s":
+		apply = func(i item) item { i.selected = true; return i }
+		done = func(count int) string { return trf("Selected %d visible segments.", count) }
+	case "d":
+		apply = func(i item) item { i.selected = false; return i }
+		done = func(count int) string { return trf("Deselected %d visible segments.", count) }
+	case "+":
+		apply = func(i item) item { return padItem(i, padStep) }
+		done = func(count int) string { return trf("Padded %d visible segments.", count) }
+	case "-":
+		apply = func(i item) item { return padItem(i, -padStep) }
+		done = func(count int) string { return trf("Trimmed %d visible segments.", count) }
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		tagIndex := int(key[0] - '1')
+		if tagIndex >= len(m.tags) {
+			return m, nil
+		}
+		tag := m.tags[tagIndex]
+		apply = func(i item) item {
+			if !slices.Contains(i.tags, tag) {
+				i.tags = toggleTag(i.tags, tag)
+			}
+			return i
+		}
+		done = func(count int) string { return trf("Tagged #%s on %d visible segments.", tagSlug(tag), count) }
+	default:
+		return m, nil
+	}
+
+	indexes := visibleIndexes(m.list)
+	m.statuses = append(m.statuses, done(len(indexes)))
+	return m.applyTo(indexes, apply)
+}
+
+// applyTo changes the lines at the indexes as a single step to undo.
+func (m model) applyTo(indexes []int, apply func(i item) item) (tea.Model, tea.Cmd) {
+	m = m.pushUndo()
+	items := m.list.Items()
+	for _, index := range indexes {
+		if i, ok := items[index].(item); ok {
+			items[index] = apply(i)
+		}
+	}
+
+	m = m.saveItems(items)
+	return m, m.list.SetItems(items)
+}
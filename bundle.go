@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// defaultBundleName is what tsplice config export saves to without a file.
+const defaultBundleName = "tsplice-settings.toml"
+
+// machineSettings only make sense on the machine they were set on, like where
+// whisper.cpp is installed or the command reading someone's own API key, so
+// they're left out of bundles and kept when one is imported.
+var machineSettings = []string{"api_key_cmd", "output_dir", "whisper_bin", "whisper_model"}
+
+func runConfig(args []string) error {
+	usage := fmt.Errorf("usage: tsplice config <export|import> [options] [file]")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "export":
+		return runConfigExport(args[1:])
+	case "import":
+		return runConfigImport(args[1:])
+	}
+	return usage
+}
+
+// configExportFlags are the options of tsplice config export.
+type configExportFlags struct {
+	force bool
+}
+
+func (f *configExportFlags) define(fs *flag.FlagSet) {
+	fs.BoolVar(&f.force, "force", false, "Write over a bundle that's already there")
+}
+
+func runConfigExport(args []string) error {
+	fs := newCommandFlagSet("config")
+	var flags configExportFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: tsplice config export [options] [file]")
+	}
+	bundleFile := defaultBundleName
+	if len(positional) == 1 {
+		bundleFile = positional[0]
+	}
+	if _, err := os.Stat(bundleFile); err == nil && !flags.force {
+		return fmt.Errorf("%s already exists, pass --force to write over it", bundleFile)
+	}
+
+	settings, err := readSettings(configPath())
+	if err != nil {
+		return err
+	}
+	for _, key := range machineSettings {
+		delete(settings, key)
+	}
+	if len(settings) == 0 {
+		return fmt.Errorf("there's nothing in %s to export", configPath())
+	}
+
+	header := fmt.Sprintf("# tsplice settings, exported %s from tsplice %s.\n# Import them with: tsplice config import %s\n\n", time.Now().Format(time.DateOnly), VERSION, filepath.Base(bundleFile))
+	if err := writeSettings(bundleFile, header, settings); err != nil {
+		return err
+	}
+	fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render("Settings: "+strings.Join(slices.Sorted(maps.Keys(settings)), ", ")))
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved settings bundle to "+bundleFile))
+	return nil
+}
+
+// configImportFlags are the options of tsplice config import.
+type configImportFlags struct {
+	replace bool
+}
+
+func (f *configImportFlags) define(fs *flag.FlagSet) {
+	fs.BoolVar(&f.replace, "replace", false, "Drop the settings the bundle doesn't have, instead of keeping them")
+}
+
+func runConfigImport(args []string) error {
+	fs := newCommandFlagSet("config")
+	var flags configImportFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice config import [options] <file>")
+	}
+	bundleFile := positional[0]
+
+	bundle, err := readSettings(bundleFile)
+	if err != nil {
+		return err
+	}
+	if err := checkBundle(bundleFile); err != nil {
+		return err
+	}
+	if len(bundle) == 0 {
+		return fmt.Errorf("%s has no settings in it", bundleFile)
+	}
+	current, err := readSettings(configPath())
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]any{}
+	if !flags.replace {
+		maps.Copy(settings, current)
+	}
+	for _, key := range machineSettings {
+		delete(bundle, key)
+		if value, ok := current[key]; ok {
+			settings[key] = value
+		}
+	}
+	for key, value := range bundle {
+		settings[key] = mergeSetting(settings[key], value)
+	}
+
+	// Everything's checked before the config is touched, the same way a run
+	// would check it
+	var content bytes.Buffer
+	if err := toml.NewEncoder(&content).Encode(settings); err != nil {
+		return err
+	}
+	if _, err := checkSettings(content.String()); err != nil {
+		return fmt.Errorf("%s can't be imported: %w", bundleFile, err)
+	}
+
+	if previous, err := os.ReadFile(configPath()); err == nil {
+		backup := configPath() + ".bak"
+		if err := os.WriteFile(backup, previous, 0644); err != nil {
+			return err
+		}
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render("Kept the previous config as "+backup))
+	}
+	header := fmt.Sprintf("# Imported from %s on %s.\n\n", filepath.Base(bundleFile), time.Now().Format(time.DateOnly))
+	if err := writeSettings(configPath(), header, settings); err != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(bundle)) {
+		state := "set"
+		if _, ok := current[key]; ok {
+			state = "updated"
+		}
+		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(key) + DimTextStyle.Render("  "+state))
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Imported %d settings into %s", len(bundle), configPath())))
+	return nil
+}
+
+// readSettings reads a config or bundle as it's written, without the
+// defaults loadConfig fills in, so only what was set is carried over. A file
+// that isn't there has no settings.
+func readSettings(file string) (map[string]any, error) {
+	settings := map[string]any{}
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(content), &settings); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", file, err)
+	}
+	return settings, nil
+}
+
+// checkBundle fails on a bundle with settings tsplice doesn't know, which a
+// newer version may have exported, or any a run would refuse to start with.
+func checkBundle(bundleFile string) error {
+	content, err := os.ReadFile(bundleFile)
+	if err != nil {
+		return err
+	}
+	unknown, err := checkSettings(string(content))
+	if err != nil {
+		return fmt.Errorf("%s can't be imported: %w", bundleFile, err)
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%s has settings this version of tsplice doesn't know: %s", bundleFile, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// checkSettings fails on settings a run would refuse to start with, and lists
+// the ones it wouldn't read at all.
+func checkSettings(content string) (unknown []string, err error) {
+	cfg := defaultConfig()
+	md, err := toml.Decode(content, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range md.Undecoded() {
+		unknown = append(unknown, key.String())
+	}
+
+	if _, err := newColorRules(cfg.Colors); err != nil {
+		return nil, fmt.Errorf("invalid color rule: %w", err)
+	}
+	if _, err := newSpeakerStyles(cfg.CaptionSpeakers); err != nil {
+		return nil, fmt.Errorf("invalid caption_speakers style: %w", err)
+	}
+	if err := cfg.Captions.validate(); err != nil {
+		return nil, fmt.Errorf("captions %w", err)
+	}
+	if _, err := newAutoSelectRules(cfg.AutoSelect); err != nil {
+		return nil, fmt.Errorf("invalid auto_select rule: %w", err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Profiles)) {
+		if err := cfg.Profiles[name].validate(); err != nil {
+			return nil, fmt.Errorf("profile %s %w", name, err)
+		}
+	}
+	if err := (profile{CRF: cfg.CRF}).validate(); err != nil {
+		return nil, err
+	}
+	return unknown, nil
+}
+
+// mergeSetting is a bundle's setting merged into the one already set. Tables
+// like profiles and caption_speakers keep the entries the bundle doesn't
+// have, and everything else is replaced whole.
+func mergeSetting(current, bundled any) any {
+	currentTable, ok := current.(map[string]any)
+	bundledTable, bundledOK := bundled.(map[string]any)
+	if !ok || !bundledOK {
+		return bundled
+	}
+	merged := maps.Clone(currentTable)
+	maps.Copy(merged, bundledTable)
+	return merged
+}
+
+func writeSettings(file, header string, settings map[string]any) error {
+	var content bytes.Buffer
+	content.WriteString(header)
+	if err := toml.NewEncoder(&content).Encode(settings); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, content.Bytes(), 0644)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const (
+	// captionLineLength is how many characters fit on a burned in caption
+	// line before it wraps, kept short enough for vertical video
+	captionLineLength = 32
+	// captionCueLines is how many lines are on screen at once, longer lines
+	// are shown a part at a time
+	captionCueLines = 2
+)
+
+// captionConfig is how burned in captions look, from captions in the config.
+// Speakers styled under caption_speakers keep their color and position.
+type captionConfig struct {
+	// Font is a font file, or a family name for fontconfig to find
+	Font string `toml:"font" json:"font,omitempty"`
+	// Size is the font size on 1080p video, scaled to the shorter side of
+	// the frame so vertical video gets the same size
+	Size     int    `toml:"size" json:"size"`
+	Position string `toml:"position" json:"position"`
+	Color    string `toml:"color" json:"color"`
+	// Background draws a box behind the text, which is outlined without one
+	Background string `toml:"background" json:"background,omitempty"`
+
+	speakers speakerStyles
+}
+
+func (c captionConfig) validate() error {
+	if c.Size <= 0 {
+		return fmt.Errorf("size has to be more than 0")
+	}
+	if !slices.Contains(captionPositions, strings.ToLower(c.Position)) {
+		return fmt.Errorf("position is %q, it can be top, middle, or bottom", c.Position)
+	}
+	if !validColor(c.Color) {
+		return fmt.Errorf("color is %q, use an ANSI color number or a hex code like #ffaa00", c.Color)
+	}
+	if c.Background != "" && !validColor(c.Background) {
+		return fmt.Errorf("background is %q, use an ANSI color number or a hex code like #ffaa00", c.Background)
+	}
+	return nil
+}
+
+// burnedCaption is part of a selected line drawn over the video, on the
+// source timeline.
+type burnedCaption struct {
+	Lines   []string `json:"lines"`
+	Speaker string   `json:"speaker,omitempty"`
+	Start   float64  `json:"start"`
+	End     float64  `json:"end"`
+}
+
+// captionsFor wraps each selected line's text, with the redactions taken out,
+// into captions of a couple of lines each. A line too long for one caption
+// is split over its time by how much of the text each part has.
+func captionsFor(items []list.Item, redactions []string) []burnedCaption {
+	var captions []burnedCaption
+	for _, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok || !i.selected {
+			continue
+		}
+		lines := wrapCaption(redactText(i.title, redactions))
+
+		total := 0
+		for _, line := range lines {
+			total += len([]rune(line))
+		}
+		start := i.start
+		for from := 0; from < len(lines); from += captionCueLines {
+			cue := lines[from:min(from+captionCueLines, len(lines))]
+			length := 0
+			for _, line := range cue {
+				length += len([]rune(line))
+			}
+			end := start + (i.end-i.start)*float64(length)/float64(total)
+			if from+captionCueLines >= len(lines) {
+				end = i.end
+			}
+			captions = append(captions, burnedCaption{Lines: cue, Speaker: i.speaker, Start: start, End: end})
+			start = end
+		}
+	}
+	return captions
+}
+
+// wrapCaption breaks text into lines of whole words, with a word too long
+// for a line getting one of its own.
+func wrapCaption(text string) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > captionLineLength {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawCaptions draws captions over the source before it's cut, like the
+// lower thirds, with their times moved back by offset for inputs that were
+// seeked into. Each line is drawn on its own so it's centered. It ends in a
+// comma, ready to go in front of the select filter.
+func drawCaptions(captions []burnedCaption, c captionConfig, offset float64) string {
+	font := ""
+	if c.Font != "" {
+		if strings.ContainsAny(c.Font, `/\`) {
+			font = "fontfile=" + escapeFilterPath(c.Font) + ":"
+		} else {
+			font = "font=" + escapeFilterText(c.Font) + ":"
+		}
+	}
+	border := "borderw=3:bordercolor=black"
+	if c.Background != "" {
+		border = fmt.Sprintf("box=1:boxcolor=%s@0.6:boxborderw=12", ffmpegColor(c.Background))
+	}
+
+	var b strings.Builder
+	for _, caption := range captions {
+		color, position := c.Color, strings.ToLower(c.Position)
+		if style, ok := c.speakers.of(caption.Speaker); ok {
+			if style.Color != "" {
+				color = style.Color
+			}
+			if style.Position != "" {
+				position = style.Position
+			}
+		}
+
+		count := len(caption.Lines)
+		for index, line := range caption.Lines {
+			// Lines are spaced a quarter of their height apart, the block
+			// kept clear of the frame's edge
+			var y string
+			switch position {
+			case "top":
+				y = fmt.Sprintf("h*0.08+%d*lh*1.25", index)
+			case "middle":
+				y = fmt.Sprintf("(h-%d*lh*1.25)/2+%d*lh*1.25", count, index)
+			default:
+				y = fmt.Sprintf("h*0.92-%d*lh*1.25", count-index)
+			}
+			fmt.Fprintf(&b, "drawtext=%sexpansion=none:text=%s:fontcolor=%s:fontsize='min(w,h)*%d/1080':x=(w-text_w)/2:y='%s':%s:enable='between(t,%.3f,%.3f)',",
+				font, escapeFilterText(line), ffmpegColor(color), c.Size, y, border, caption.Start-offset, caption.End-offset)
+		}
+	}
+	return b.String()
+}
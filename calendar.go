@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// calendarEarlyStart is how long before a meeting's start a recording can
+// begin and still be matched to it.
+const calendarEarlyStart = 10 * time.Minute
+
+// calendarEvent is a meeting from an ICS file.
+type calendarEvent struct {
+	Summary   string
+	Start     time.Time
+	End       time.Time
+	Attendees []string
+}
+
+// unfoldICS joins the continuation lines ICS wraps long properties onto.
+func unfoldICS(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// icsProperty splits "NAME;PARAM=value:content" into its parts.
+func icsProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, part := range parts[1:] {
+		if key, v, ok := strings.Cut(part, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// parseICSTime reads a date-time in UTC, in a TZID, or in local time. All-day
+// dates are reported as not ok, since they'd match any recording that day.
+func parseICSTime(value string, params map[string]string) (time.Time, bool) {
+	if params["VALUE"] == "DATE" {
+		return time.Time{}, false
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, err == nil
+	}
+
+	location := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	return t, err == nil
+}
+
+func parseICS(content string) []calendarEvent {
+	var events []calendarEvent
+	var current *calendarEvent
+	for _, line := range unfoldICS(content) {
+		name, params, value := icsProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &calendarEvent{}
+		case name == "END" && value == "VEVENT" && current != nil:
+			if !current.Start.IsZero() {
+				if current.End.IsZero() {
+					current.End = current.Start.Add(time.Hour)
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case name == "SUMMARY":
+			current.Summary = unescapeICS(value)
+		case name == "DTSTART":
+			current.Start, _ = parseICSTime(value, params)
+		case name == "DTEND":
+			current.End, _ = parseICSTime(value, params)
+		case name == "ATTENDEE":
+			attendee := params["CN"]
+			if attendee == "" {
+				attendee = strings.TrimPrefix(strings.TrimPrefix(value, "mailto:"), "MAILTO:")
+			}
+			if attendee != "" {
+				current.Attendees = append(current.Attendees, attendee)
+			}
+		}
+	}
+	return events
+}
+
+func loadCalendar(file string) ([]calendarEvent, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	events := parseICS(string(content))
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no events found in '%s'", file)
+	}
+	return events, nil
+}
+
+// matchEvent returns the meeting that was going on when the recording
+// started, preferring the one that started closest to it.
+func matchEvent(events []calendarEvent, recorded time.Time) (calendarEvent, bool) {
+	var match calendarEvent
+	found := false
+	for _, event := range events {
+		if recorded.Before(event.Start.Add(-calendarEarlyStart)) || !recorded.Before(event.End) {
+			continue
+		}
+		if !found || event.Start.Sub(recorded).Abs() < match.Start.Sub(recorded).Abs() {
+			match = event
+			found = true
+		}
+	}
+	return match, found
+}
+
+// recordingTime is when a video started recording, from its creation_time
+// tag, or else worked back from when the file was last written.
+func recordingTime(inputFile string) (time.Time, error) {
+	output, err := execute.Output("ffprobe", "-v", "error", "-show_entries", "format=duration:format_tags=creation_time", "-of", "json", inputFile)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to probe video: %w", err)
+	}
+
+	var probe struct {
+		Format struct {
+			Duration string            `json:"duration"`
+			Tags     map[string]string `json:"tags"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if created, err := time.Parse(time.RFC3339Nano, probe.Format.Tags["creation_time"]); err == nil && created.Year() > 1970 {
+		return created, nil
+	}
+
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var duration float64
+	fmt.Sscanf(probe.Format.Duration, "%f", &duration)
+	return info.ModTime().Add(-time.Duration(duration * float64(time.Second))), nil
+}
+
+// meetingFor finds the calendar event a recording was made during.
+func meetingFor(calendarFile string, inputFile string) (calendarEvent, bool, error) {
+	events, err := loadCalendar(calendarFile)
+	if err != nil {
+		return calendarEvent{}, false, err
+	}
+	recorded, err := recordingTime(inputFile)
+	if err != nil {
+		return calendarEvent{}, false, err
+	}
+	event, ok := matchEvent(events, recorded)
+	return event, ok, nil
+}
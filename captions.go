@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func formatSRT(transcriptItems []TranscriptItem) string {
+	var b strings.Builder
+	for index, transcriptItem := range transcriptItems {
+		start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+		end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", index+1, srtTimestamp(start), srtTimestamp(end), transcriptItem.Text)
+	}
+	return b.String()
+}
+
+func srtTimestamp(seconds float64) string {
+	return strings.Replace(formatTimestamp(seconds), ".", ",", 1)
+}
+
+// selectedTranscript returns the selected lines as transcript items, keeping
+// their timestamps in the source.
+func selectedTranscript(items []list.Item) []TranscriptItem {
+	var transcriptItems []TranscriptItem
+	for _, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok || !i.selected {
+			continue
+		}
+		transcriptItems = append(transcriptItems, TranscriptItem{
+			StartTime:  formatTimestamp(i.start),
+			EndTime:    formatTimestamp(i.end),
+			Text:       i.title,
+			Speaker:    i.speaker,
+			Confidence: i.confidence,
+		})
+	}
+	return transcriptItems
+}
+
+// exportSelectionSubtitles writes VTT and SRT files with only the selected
+// lines at their original timestamps, for cutting the video elsewhere.
+func exportSelectionSubtitles(inputFile string, items []list.Item, redactions []string, styles speakerStyles) ([]string, error) {
+	transcriptItems := redactItems(selectedTranscript(items), redactions)
+	if len(transcriptItems) == 0 {
+		return nil, fmt.Errorf("no segments selected")
+	}
+
+	base := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "_selection"
+	files := []string{base + ".vtt", base + ".srt"}
+	contents := []string{formatStyledVTT(transcriptItems, styles), formatStyledSRT(transcriptItems, styles)}
+
+	for index, file := range files {
+		if err := os.WriteFile(file, []byte(contents[index]), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
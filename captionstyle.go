@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// speakerStyle is how a speaker's captions look when they're burned in or
+// exported, set per speaker under caption_speakers in the config.
+type speakerStyle struct {
+	// Color takes the same values as the list colors, an ANSI color number
+	// or a hex code
+	Color string `toml:"color"`
+	// Position is top, middle, or bottom, where captions usually go
+	Position string `toml:"position"`
+}
+
+type speakerStyles map[string]speakerStyle
+
+var captionPositions = []string{"", "top", "middle", "bottom"}
+
+func newSpeakerStyles(configs map[string]speakerStyle) (speakerStyles, error) {
+	styles := speakerStyles{}
+	for speaker, style := range configs {
+		style.Position = strings.ToLower(style.Position)
+		if !slices.Contains(captionPositions, style.Position) {
+			return nil, fmt.Errorf("%s has position %q, it can be top, middle, or bottom", speaker, style.Position)
+		}
+		if style.Color != "" && !validColor(style.Color) {
+			return nil, fmt.Errorf("%s has color %q, use an ANSI color number or a hex code like #ffaa00", speaker, style.Color)
+		}
+		styles[speaker] = style
+	}
+	return styles, nil
+}
+
+func validColor(color string) bool {
+	if hex, ok := strings.CutPrefix(color, "#"); ok {
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return len(hex) == 6 && err == nil
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// of finds a speaker's style, ignoring case so names don't have to be typed
+// exactly as they were given.
+func (styles speakerStyles) of(speaker string) (speakerStyle, bool) {
+	if speaker == "" {
+		return speakerStyle{}, false
+	}
+	if style, ok := styles[speaker]; ok {
+		return style, true
+	}
+	for name, style := range styles {
+		if strings.EqualFold(name, speaker) {
+			return style, true
+		}
+	}
+	return speakerStyle{}, false
+}
+
+// srtText styles a cue with the tags libass and most players read in SRT, a
+// font color and an alignment override.
+func (styles speakerStyles) srtText(speaker, text string) string {
+	style, ok := styles.of(speaker)
+	if !ok {
+		return text
+	}
+	if style.Color != "" {
+		text = `<font color="#` + hexColor(style.Color) + `">` + text + "</font>"
+	}
+	switch style.Position {
+	case "top":
+		text = `{\an8}` + text
+	case "middle":
+		text = `{\an5}` + text
+	}
+	return text
+}
+
+// vttSettings places a speaker's cues with WebVTT cue settings.
+func (styles speakerStyles) vttSettings(speaker string) string {
+	style, _ := styles.of(speaker)
+	switch style.Position {
+	case "top":
+		return " line:0"
+	case "middle":
+		return " line:50%"
+	}
+	return ""
+}
+
+// formatStyledVTT writes captions with a STYLE block coloring each speaker's
+// voice, and their cues placed where they're set to go. Without any
+// styles it's the same as formatVTT.
+func formatStyledVTT(transcriptItems []TranscriptItem, styles speakerStyles) string {
+	if len(styles) == 0 {
+		return formatVTT(transcriptItems)
+	}
+
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+
+	var speakers []string
+	for _, transcriptItem := range transcriptItems {
+		if style, ok := styles.of(transcriptItem.Speaker); ok && style.Color != "" && !slices.Contains(speakers, transcriptItem.Speaker) {
+			speakers = append(speakers, transcriptItem.Speaker)
+			voice := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(transcriptItem.Speaker)
+			fmt.Fprintf(&b, "STYLE\n::cue(v[voice=\"%s\"]) {\n  color: #%s;\n}\n\n", voice, hexColor(style.Color))
+		}
+	}
+
+	for _, transcriptItem := range transcriptItems {
+		text := transcriptItem.Text
+		if transcriptItem.Speaker != "" {
+			text = "<v " + transcriptItem.Speaker + ">" + text
+		}
+		fmt.Fprintf(&b, "%s --> %s%s\n%s\n\n", transcriptItem.StartTime, transcriptItem.EndTime, styles.vttSettings(transcriptItem.Speaker), text)
+	}
+	return b.String()
+}
+
+// formatStyledSRT writes captions with each speaker's color and position.
+// Without any styles it's the same as formatSRT.
+func formatStyledSRT(transcriptItems []TranscriptItem, styles speakerStyles) string {
+	styled := make([]TranscriptItem, len(transcriptItems))
+	for index, transcriptItem := range transcriptItems {
+		transcriptItem.Text = styles.srtText(transcriptItem.Speaker, transcriptItem.Text)
+		styled[index] = transcriptItem
+	}
+	return formatSRT(styled)
+}
+
+// speakerAt is who's speaking at a point in the source, going by the line it
+// falls in.
+func speakerAt(items []list.Item, at float64) string {
+	for _, listItem := range items {
+		if i, ok := listItem.(item); ok && at >= i.start && at < i.end {
+			return i.speaker
+		}
+	}
+	return ""
+}
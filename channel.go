@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// channelPan picks one side of a stereo track for the left and right
+// speaker channel options.
+var channelPan = map[string]string{
+	"left":  "pan=mono|c0=c0",
+	"right": "pan=mono|c0=c1",
+}
+
+// pickChannels decides, for every segment, whether the left or right channel
+// of the first audio track is louder, for interviews recorded with one
+// speaker per channel. Segments that can't be measured are left out, and
+// keep both channels.
+func pickChannels(inputFile string, segments []segment) ([]timeRange, []timeRange) {
+	probe, err := probeStreams(inputFile)
+	if err != nil || firstAudioChannels(probe) != 2 {
+		return nil, nil
+	}
+
+	dir, err := tempDir("tsplice-channels-")
+	if err != nil {
+		return nil, nil
+	}
+	defer removeTemp(dir)
+
+	var left, right []timeRange
+	for index, s := range segments {
+		leftFile := filepath.Join(dir, fmt.Sprintf("segment_%d_left.txt", index))
+		rightFile := filepath.Join(dir, fmt.Sprintf("segment_%d_right.txt", index))
+
+		// Without a reset the last frame holds the level across the whole segment
+		err := execute.Run("ffmpeg", "-y",
+			"-ss", fmt.Sprintf("%.3f", s.start), "-t", fmt.Sprintf("%.3f", s.end-s.start),
+			"-i", inputFile,
+			"-map", "0:a:0",
+			"-af", fmt.Sprintf("astats=metadata=1,ametadata=mode=print:key=lavfi.astats.1.RMS_level:file=%s,ametadata=mode=print:key=lavfi.astats.2.RMS_level:file=%s",
+				escapeFilterPath(leftFile), escapeFilterPath(rightFile)),
+			"-f", "null", "-",
+		)
+		if err != nil {
+			continue
+		}
+
+		leftLevels, err := readLevels(leftFile, "lavfi.astats.1.RMS_level")
+		if err != nil || len(leftLevels) == 0 {
+			continue
+		}
+		rightLevels, err := readLevels(rightFile, "lavfi.astats.2.RMS_level")
+		if err != nil || len(rightLevels) == 0 {
+			continue
+		}
+
+		r := timeRange{Start: s.start, End: s.end}
+		if leftLevels[len(leftLevels)-1] >= rightLevels[len(rightLevels)-1] {
+			left = append(left, r)
+		} else {
+			right = append(right, r)
+		}
+	}
+
+	return left, right
+}
+
+func firstAudioChannels(probe probeResult) int {
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "audio" {
+			return stream.Channels
+		}
+	}
+	return 0
+}
+
+// channelFilters keeps only the picked channel of each span of the compiled
+// track, muting the other side before folding both down to mono. The chain
+// carries on from the returned label.
+func channelFilters(chain string, index int, edits audioEdits) ([]string, string) {
+	mute := func(expression string) string {
+		if expression == "" {
+			return "anull"
+		}
+		return fmt.Sprintf("volume=volume=0:enable='%s'", expression)
+	}
+
+	return []string{
+		fmt.Sprintf("%s,channelsplit=channel_layout=stereo[a%dl][a%dr]", chain, index, index),
+		fmt.Sprintf("[a%dl]%s[a%dlm]", index, mute(edits.rightChannel), index),
+		fmt.Sprintf("[a%dr]%s[a%drm]", index, mute(edits.leftChannel), index),
+		fmt.Sprintf("[a%dlm][a%drm]amix=inputs=2:duration=first:normalize=0[a%dpicked]", index, index, index),
+	}, fmt.Sprintf("[a%dpicked]anull", index)
+}
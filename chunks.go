@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// openAIUploadLimit is the largest file OpenAI's transcription API takes
+	openAIUploadLimit = 25 << 20
+	// chunkOverlap is how many seconds each chunk shares with the next, so
+	// a word said right on a split is whole in one of them
+	chunkOverlap = 5.0
+	// chunkWorkers is how many chunks are uploaded at once
+	chunkWorkers = 4
+	// smallestUploadLimit is as small as chunks get for a server that turns
+	// down bigger uploads
+	smallestUploadLimit = 1 << 20
+)
+
+// uploadLimit is the largest file uploaded in one request, above which the
+// audio is sent in chunks. It starts at OpenAI's limit and is lowered from
+// the error screen for servers that take less.
+var uploadLimit int64 = openAIUploadLimit
+
+// lowerUploadLimit halves the chunk size for the transcription to be retried
+// with.
+func lowerUploadLimit() {
+	uploadLimit = max(uploadLimit/2, smallestUploadLimit)
+}
+
+// audioChunk is a piece of a long recording, starting offset seconds in,
+// with the first overlap seconds of it also at the end of the chunk before.
+type audioChunk struct {
+	file    string
+	offset  float64
+	overlap float64
+}
+
+// splitAudio cuts an audio file into overlapping chunks that each fit under
+// limit bytes, going by its average bitrate. Chunks are copied rather than
+// encoded again, so they split on the nearest MP3 frame.
+func splitAudio(audioFile string, limit int64, dir string) ([]audioChunk, error) {
+	info, err := os.Stat(audioFile)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := probeDuration(audioFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// A tenth is left spare for parts that come out denser than the average
+	bytesPerSecond := float64(info.Size()) / duration
+	length := float64(limit) * 0.9 / bytesPerSecond
+	if length <= chunkOverlap*2 {
+		return nil, fmt.Errorf("the audio is too dense to split into chunks under %d MB", limit>>20)
+	}
+
+	var chunks []audioChunk
+	for offset := 0.0; offset < duration; offset += length - chunkOverlap {
+		chunk := audioChunk{file: filepath.Join(dir, fmt.Sprintf("chunk_%03d%s", len(chunks), filepath.Ext(audioFile))), offset: offset}
+		if len(chunks) > 0 {
+			chunk.overlap = chunkOverlap
+		}
+		err := execute.Run("ffmpeg", "-y", "-ss", fmt.Sprintf("%.3f", offset), "-t", fmt.Sprintf("%.3f", length), "-i", audioFile, "-c", "copy", chunk.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split audio: %w", err)
+		}
+		chunks = append(chunks, chunk)
+		if offset+length >= duration {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+// transcribeChunks splits a recording that's too big to upload in one go,
+// transcribes the chunks a few at a time, and joins them back into one
+// transcription on the recording's timeline.
+func (t openAITranscriber) transcribeChunks(audioFile string) (openAITranscription, error) {
+	dir, err := tempDir("tsplice-chunks-")
+	if err != nil {
+		return openAITranscription{}, err
+	}
+	defer removeTemp(dir)
+
+	chunks, err := splitAudio(audioFile, uploadLimit, dir)
+	if err != nil {
+		return openAITranscription{}, err
+	}
+
+	transcriptions := make([]openAITranscription, len(chunks))
+	errs := make([]error, len(chunks))
+	workers := make(chan struct{}, chunkWorkers)
+	var wg sync.WaitGroup
+	for index, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			transcriptions[index], errs[index] = t.request(chunk.file)
+		}()
+	}
+	wg.Wait()
+
+	for index, err := range errs {
+		if err != nil {
+			return openAITranscription{}, fmt.Errorf("chunk %d of %d: %w", index+1, len(chunks), err)
+		}
+	}
+	return mergeChunks(chunks, transcriptions), nil
+}
+
+// mergeChunks moves each chunk's segments and words onto the recording's
+// timeline. Where two chunks overlap, the earlier one's segments are kept
+// up to the middle of the overlap and the later one picks up after the last
+// of them ends, so nothing is said twice.
+func mergeChunks(chunks []audioChunk, transcriptions []openAITranscription) openAITranscription {
+	var merged openAITranscription
+	if len(transcriptions) > 0 {
+		merged.Language = transcriptions[0].Language
+	}
+
+	covered := 0.0
+	for index, transcription := range transcriptions {
+		offset := chunks[index].offset
+		// Past the middle of the overlap, the next chunk has more context
+		boundary := -1.0
+		if index+1 < len(chunks) {
+			boundary = chunks[index+1].offset + chunks[index+1].overlap/2
+		}
+
+		end := covered
+		for _, s := range transcription.Segments {
+			s.Start += offset
+			s.End += offset
+			if s.Start < covered || (boundary >= 0 && s.Start >= boundary) {
+				continue
+			}
+			merged.Segments = append(merged.Segments, s)
+			end = max(end, s.End)
+		}
+		for _, w := range transcription.Words {
+			w.Start += offset
+			w.End += offset
+			if w.Start < covered || (boundary >= 0 && w.Start >= end) {
+				continue
+			}
+			merged.Words = append(merged.Words, w)
+		}
+		covered = end
+	}
+	return merged
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// chunkTranscription is what a chunk came back as, its segments and words in
+// "text start end" form on the chunk's own timeline.
+type chunkTranscription struct {
+	segments []string
+	words    []string
+}
+
+func (c chunkTranscription) openAI(t *testing.T) openAITranscription {
+	t.Helper()
+	type timed struct {
+		Text  string  `json:"text"`
+		Word  string  `json:"word"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	}
+	parse := func(spans []string) []timed {
+		var parsed []timed
+		for _, span := range spans {
+			var s timed
+			if _, err := fmt.Sscan(span, &s.Text, &s.Start, &s.End); err != nil {
+				t.Fatalf("bad span %q: %v", span, err)
+			}
+			s.Word = s.Text
+			parsed = append(parsed, s)
+		}
+		return parsed
+	}
+
+	content, err := json.Marshal(map[string]any{"segments": parse(c.segments), "words": parse(c.words)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transcription openAITranscription
+	if err := json.Unmarshal(content, &transcription); err != nil {
+		t.Fatal(err)
+	}
+	return transcription
+}
+
+func TestMergeChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlap float64
+		chunks  [2]chunkTranscription
+		// Segments and words on the recording's timeline, as text
+		segments []string
+		words    []string
+	}{
+		{
+			// The second chunk starts at 100s and 102.5s is the middle of
+			// the overlap, which the earlier chunk's last segment runs past
+			name:    "segment straddling the overlap",
+			overlap: chunkOverlap,
+			chunks: [2]chunkTranscription{
+				{segments: []string{"before 95 100", "straddles 102 104"}, words: []string{"before 95 100", "straddles 102 104"}},
+				{segments: []string{"straddles 2 4", "after 4 6"}, words: []string{"straddles 2 4", "after 4 6"}},
+			},
+			segments: []string{"before", "straddles", "after"},
+			words:    []string{"before", "straddles", "after"},
+		},
+		{
+			name:    "duplicates inside the overlap",
+			overlap: chunkOverlap,
+			chunks: [2]chunkTranscription{
+				{segments: []string{"first 99 100.5", "twice 100.5 102", "late 103 104.5"}, words: []string{"first 99 100.5", "twice 100.5 102", "late 103 104.5"}},
+				{segments: []string{"first 0 0.5", "twice 0.5 2", "late 3 4.5", "last 4.5 6"}, words: []string{"first 0 0.5", "twice 0.5 2", "late 3 4.5", "last 4.5 6"}},
+			},
+			segments: []string{"first", "twice", "late", "last"},
+			words:    []string{"first", "twice", "late", "last"},
+		},
+		{
+			name:    "no overlap",
+			overlap: 0,
+			chunks: [2]chunkTranscription{
+				{segments: []string{"end 98 100"}, words: []string{"end 98 100"}},
+				{segments: []string{"start 0 2"}, words: []string{"start 0 2"}},
+			},
+			segments: []string{"end", "start"},
+			words:    []string{"end", "start"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chunks := []audioChunk{{offset: 0}, {offset: 100, overlap: test.overlap}}
+			transcriptions := []openAITranscription{test.chunks[0].openAI(t), test.chunks[1].openAI(t)}
+			merged := mergeChunks(chunks, transcriptions)
+
+			var segments, words []string
+			previous := 0.0
+			for _, s := range merged.Segments {
+				if s.Start < previous {
+					t.Errorf("%s starts at %.1fs, before the segment ahead of it ends", s.Text, s.Start)
+				}
+				previous = s.End
+				segments = append(segments, s.Text)
+			}
+			for _, w := range merged.Words {
+				words = append(words, w.Word)
+			}
+			if !slices.Equal(segments, test.segments) {
+				t.Errorf("segments are %v, want %v", segments, test.segments)
+			}
+			if !slices.Equal(words, test.words) {
+				t.Errorf("words are %v, want %v", words, test.words)
+			}
+		})
+	}
+}
+
+func TestMergeChunksMovesTheLaterChunkOntoTheTimeline(t *testing.T) {
+	chunks := []audioChunk{{offset: 0}, {offset: 100, overlap: chunkOverlap}}
+	transcriptions := []openAITranscription{
+		chunkTranscription{segments: []string{"one 0 2"}}.openAI(t),
+		chunkTranscription{segments: []string{"two 10 12"}}.openAI(t),
+	}
+	merged := mergeChunks(chunks, transcriptions)
+	if len(merged.Segments) != 2 || merged.Segments[1].Start != 110 || merged.Segments[1].End != 112 {
+		t.Errorf("segments are %+v, want the second at 110s to 112s", merged.Segments)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// compileClips saves each segment as a numbered clip of its own, like
+// _clip_01.mp4, instead of joining them, for cutting shorts out of a long
+// recording. Each clip is compiled with the same options a whole selection
+// would be.
+func compileClips(inputFile string, segments []segment, opts compileOptions) tea.Msg {
+	var clips, warnings []string
+	for index, s := range segments {
+		clipOpts := opts
+		clipOpts.Clip = index + 1
+		clip, err := compileSegments(inputFile, []segment{s}, clipOpts)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("clip %d: %w", index+1, err)}
+		}
+		clips = append(clips, clip)
+
+		if opts.Verify {
+			clipWarnings, err := verifyOutput(clip, expectedDuration([]segment{s}, opts))
+			if err != nil {
+				clipWarnings = []string{err.Error()}
+			}
+			for _, warning := range clipWarnings {
+				warnings = append(warnings, filepath.Base(clip)+": "+warning)
+			}
+		}
+	}
+	return videoCompilationDoneMsg{outputFile: clips[0], clips: clips, warnings: warnings}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+type colorRule struct {
+	conditions conditionMatcher
+	style      lipgloss.Style
+}
+
+type colorRules []colorRule
+
+func newColorRules(configs []colorRuleConfig) (colorRules, error) {
+	var rules colorRules
+	for _, c := range configs {
+		conditions, err := newConditionMatcher(c.ruleConditions)
+		if err != nil {
+			return nil, err
+		}
+
+		style := lipgloss.NewStyle().Bold(c.Bold)
+		if c.Color != "" {
+			style = style.Foreground(lipgloss.Color(c.Color))
+		}
+		rules = append(rules, colorRule{conditions: conditions, style: style})
+	}
+	return rules, nil
+}
+
+// match returns the style of the first rule matching the item.
+func (rules colorRules) match(i item) (lipgloss.Style, bool) {
+	for _, rule := range rules {
+		if rule.conditions.matches(i) {
+			return rule.style, true
+		}
+	}
+	return lipgloss.Style{}, false
+}
@@ -11,6 +11,15 @@ var (
 	TimestampStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).PaddingLeft(2)
 	ItemStyle         = lipgloss.NewStyle().PaddingLeft(2)
 	SelectedItemStyle = lipgloss.NewStyle().PaddingLeft(0).Foreground(lipgloss.Color("3"))
+	RangeItemStyle    = lipgloss.NewStyle().PaddingLeft(0).Foreground(lipgloss.Color("6"))
 	ErrorStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 	SuccessStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	TagStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+
+	KaraokeCurrentStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("3"))
+
+	SummaryPaneStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).BorderForeground(lipgloss.Color("8")).PaddingLeft(1).MarginLeft(2)
+
+	ZenTextStyle         = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	ZenSelectedTextStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
 )
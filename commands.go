@@ -0,0 +1,353 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	// description and examples are the long help, shown by tsplice help and
+	// in the man page
+	description string
+	examples    []string
+	// flags defines the command's options on a flag set, the same ones run
+	// parses, so they can be listed without running it
+	flags func(fs *flag.FlagSet)
+	run   func(args []string) error
+}
+
+var commands []command
+
+// Registered in init since subcommands look up their own usage from this table
+func init() {
+	commands = []command{
+		{
+			name:        "actions",
+			usage:       "tsplice actions [options] <input-file>",
+			summary:     "pull action items and decisions out of a meeting",
+			description: "Sends the transcript to OpenAI's chat API to pull out the meeting's action items, with who's taking them on, and the decisions made. Each one links back to when it was said. They're saved as a markdown checklist next to the video, as JSON with --format=json, or posted to a --webhook.",
+			examples: []string{
+				`tsplice actions ./team_sync.mp4`,
+				`tsplice actions --webhook=https://hooks.example.com/tasks ./team_sync.mp4`,
+			},
+			flags: new(actionsFlags).define,
+			run:   runActions,
+		},
+		{
+			name:        "batch",
+			usage:       "tsplice batch [options] <folder>",
+			summary:     "transcribe every video in a folder without opening the editor",
+			description: "Walks a folder and its subfolders and saves a transcript next to every video that doesn't have one yet, each with the same provider, --lang, and --prompt. A video that fails doesn't stop the rest, and running it again picks up only what's left.",
+			examples: []string{
+				`tsplice batch --lang=en ./Lectures`,
+				`tsplice batch --no-subfolders ./Recordings`,
+			},
+			flags: new(batchFlags).define,
+			run:   runBatch,
+		},
+		{
+			name:        "bench",
+			usage:       "tsplice bench [options] <input-file>",
+			summary:     "compare transcription backends on a sample of the input",
+			description: "Transcribes a short sample of the video with each backend and reports how long it took, what it cost, and how much the transcripts differ, to pick one for a project.",
+			examples: []string{
+				`tsplice bench --providers=openai,deepgram,whisper --sample=60 ./talk.mp4`,
+			},
+			flags: new(benchFlags).define,
+			run:   runBench,
+		},
+		{
+			name:        "bot",
+			usage:       "tsplice bot --public-url=<url> [options]",
+			summary:     "take videos from a Slack or Discord bot and reply with highlights",
+			description: "Runs a server that Slack slash commands, or any bot posting to /webhook, send videos to. Each one is downloaded, transcribed, summarized, and cut into a highlight reel, and the reply links to it at --public-url. /slack needs SLACK_SIGNING_SECRET set and /webhook needs --token, and it won't start without one of them.",
+			examples: []string{
+				`tsplice bot --public-url=https://clips.example.com --token=hunter2`,
+			},
+			flags: new(botFlags).define,
+			run:   runBot,
+		},
+		{
+			name:        "clean",
+			usage:       "tsplice clean [options]",
+			summary:     "remove the workspaces old runs left their intermediates in",
+			description: "Removes the workspaces in the cache folder that runs kept their extracted audio, chunks, and logs in, once they haven't been used for a week. Transcripts and project files are kept next to the video, not in a workspace, so they're never removed. Workspaces of a tsplice that's still running are always left alone.",
+			examples: []string{
+				`tsplice clean --dry-run`,
+				`tsplice clean --older-than=24h`,
+			},
+			flags: new(cleanFlags).define,
+			run:   runClean,
+		},
+		{
+			name:        "config",
+			usage:       "tsplice config <export|import> [options] [file]",
+			summary:     "share settings and profiles as a bundle, or import one",
+			description: "export saves the config as a settings bundle, leaving out the settings that only make sense on this machine, and import merges one into the config after checking it, keeping the old one as config.toml.bak.",
+			examples: []string{
+				`tsplice config export ./team/tsplice-settings.toml`,
+				`tsplice config import ./team/tsplice-settings.toml`,
+			},
+			flags: func(fs *flag.FlagSet) {
+				new(configExportFlags).define(fs)
+				new(configImportFlags).define(fs)
+			},
+			run: runConfig,
+		},
+		{
+			name:        "cut",
+			usage:       "tsplice cut --match=<regex> [options] <input-file>",
+			summary:     "compile the lines matching a pattern without opening the editor",
+			description: "Keeps every line whose text matches a regular expression and compiles them, the same as selecting those lines in the editor, for scripts and CI. The video is transcribed first if it hasn't been, and nothing matching is an error.",
+			examples: []string{
+				`tsplice cut --match="introduction|summary" ./talk.mp4`,
+				`tsplice cut --match="(?i)sponsor" --dry-run ./talk.mp4`,
+			},
+			flags: new(cutFlags).define,
+			run:   runCut,
+		},
+		{
+			name:        "dub",
+			usage:       "tsplice dub --to=<language> [options] <input-file>",
+			summary:     "experimental: translate and re-voice the video with text to speech",
+			description: "Translates the transcript, voices each line with text to speech, and mixes it over the original audio, which is ducked while the new voice plays. The original audio is kept as a second track.",
+			examples: []string{
+				`tsplice dub --to=es ./talk.mp4`,
+				`tsplice dub --to=es --captions=./talk_selection.vtt ./talk.mp4`,
+			},
+			flags: new(dubFlags).define,
+			run:   runDub,
+		},
+		{
+			name:        "feed",
+			usage:       "tsplice feed [options] <rss-url>",
+			summary:     "pick a recent podcast episode from a feed and open it",
+			description: "Lists the recent episodes of a podcast's RSS feed, downloads the one picked, and opens it in the editor with the options given before feed. Audio-only episodes get their artwork as a still so they can be compiled.",
+			examples: []string{
+				`tsplice feed https://example.com/podcast.rss`,
+				`tsplice --lang=en feed --episode=1 https://example.com/podcast.rss`,
+			},
+			flags: new(feedFlags).define,
+			run:   runFeed,
+		},
+		{
+			name:        "help",
+			usage:       "tsplice help [command]",
+			summary:     "show a command's options and examples",
+			description: "Shows what a command does, every option it takes, and examples of it, or the options for opening and compiling a video without one.",
+			examples: []string{
+				`tsplice help cut`,
+				`tsplice help compile`,
+			},
+			run: runHelp,
+		},
+		{
+			name:        "html",
+			usage:       "tsplice html [options] <input-file>",
+			summary:     "export a searchable transcript page that seeks the video",
+			description: "Writes a standalone page with the video and its transcript, searchable, where clicking a line seeks to it. --url points the page at the published video instead of the local file.",
+			examples: []string{
+				`tsplice html ./keynote.mp4`,
+				`tsplice html --url="https://example.com/talks/keynote.mp4" ./keynote.mp4`,
+			},
+			flags: new(htmlFlags).define,
+			run:   runHTML,
+		},
+		{
+			name:        "man",
+			usage:       "tsplice man [file]",
+			summary:     "save a man page documenting every option and command",
+			description: "Saves a man page with every option, command, and environment variable, generated from the same definitions as the help, to tsplice.1 or the file given. Save it where man looks for pages to read it with man tsplice.",
+			examples: []string{
+				`tsplice man ~/.local/share/man/man1/tsplice.1`,
+				`tsplice man && man ./tsplice.1`,
+			},
+			run: runMan,
+		},
+		{
+			name:        "notes",
+			usage:       "tsplice notes [options] <input-file>",
+			summary:     "export an illustrated transcript with screenshots",
+			description: "Turns the transcript into an illustrated document, a screenshot at the start of each section followed by what was said in it, grouped by speaker. It's saved to a _notes folder next to the video.",
+			examples: []string{
+				`tsplice notes --format=html ./team_sync.mp4`,
+				`tsplice notes --calendar=./work.ics ./team_sync.mp4`,
+			},
+			flags: new(notesFlags).define,
+			run:   runNotes,
+		},
+		{
+			name:        "reproduce",
+			usage:       "tsplice reproduce [options] <manifest.json>",
+			summary:     "re-run a previous compile from its manifest",
+			description: "Renders a compile again from the manifest --manifest saved with it, with the exact same segments and options, after checking the source is the one it was made from.",
+			examples: []string{
+				`tsplice reproduce ./talk_compiled.manifest.json`,
+			},
+			flags: new(reproduceFlags).define,
+			run:   runReproduce,
+		},
+		{
+			name:        "search",
+			usage:       "tsplice search [options] <query> [folder]",
+			summary:     "search every transcript in a folder and open a match",
+			description: "Looks through every transcript indexed under a folder, the current one by default, for lines with every word of the query, and opens the video of the match picked at that line. --semantic ranks lines by meaning and --supercut compiles the matches.",
+			examples: []string{
+				`tsplice search "kubernetes" ~/videos`,
+				`tsplice search --semantic "the part about pricing" ~/videos`,
+			},
+			flags: new(searchFlags).define,
+			run:   runSearch,
+		},
+		{
+			name:        "selftest",
+			usage:       "tsplice selftest [options]",
+			summary:     "run the media pipeline end to end against generated fixtures",
+			description: "Generates short test videos and runs them through extraction, parsing, and compiling, to check this machine's ffmpeg can do everything tsplice asks of it.",
+			examples: []string{
+				`tsplice selftest`,
+				`tsplice selftest --keep`,
+			},
+			flags: new(selftestFlags).define,
+			run:   runSelftest,
+		},
+		{
+			name:        "serve",
+			usage:       "tsplice serve [options] <input-file>",
+			summary:     "share a video's selection with others editing it in a browser",
+			description: "Shares the transcript on a web page where everyone who opens it can select lines at the same time. The selection is saved next to the transcript and loaded the next time the video is opened. It only listens on this machine unless --addr says otherwise, which needs a --token for everyone to open the page with.",
+			examples: []string{
+				`tsplice serve ./talk.mp4`,
+				`tsplice serve --addr=:9000 --token=hunter2 ./talk.mp4`,
+			},
+			flags: new(serveFlags).define,
+			run:   runServe,
+		},
+		{
+			name:        "stats",
+			usage:       "tsplice stats [options] <input-file>...",
+			summary:     "export speaking analytics for transcribed videos",
+			description: "Exports the word count, speaking rate, talk time per speaker, longest silences, and filler words of transcribed videos, as JSON and CSV next to each one.",
+			examples: []string{
+				`tsplice stats ./talk.mp4`,
+				`tsplice stats --format=csv ./Movies/*.mp4`,
+			},
+			flags: new(statsFlags).define,
+			run:   runStats,
+		},
+		{
+			name:        "supercut",
+			usage:       "tsplice supercut [options] <phrase> <input-file>...",
+			summary:     "compile every time a word or phrase is said back to back",
+			description: "Finds every time a word or phrase is said in the videos, from their word timestamps, and compiles those moments back to back. Videos without a transcript are transcribed first.",
+			examples: []string{
+				`tsplice supercut "literally" ./talk.mp4`,
+				`tsplice supercut --output=basically.mp4 "basically" ./day1.mp4 ./day2.mp4`,
+			},
+			flags: new(supercutFlags).define,
+			run:   runSupercut,
+		},
+		{
+			name:        "tighten",
+			usage:       "tsplice tighten [options] <input-file>",
+			summary:     "cut the pauses and filler words out of the whole video",
+			description: "Cuts every pause longer than --max-gap and every filler word out of the whole video, keeping --pad seconds around the speech at each cut, and saves it as _tight.mp4.",
+			examples: []string{
+				`tsplice tighten ./talk.mp4`,
+				`tsplice tighten --max-gap=0.3 --keep-fillers ./talk.mp4`,
+			},
+			flags: new(tightenFlags).define,
+			run:   runTighten,
+		},
+		{
+			name:        "translate",
+			usage:       "tsplice translate --to=<language> [options] <input-file>",
+			summary:     "translate the captions and export original and bilingual tracks",
+			description: "Translates every line with OpenAI's chat API and saves the captions in that language next to the transcript, along with bilingual ones showing both. --burn also renders the video with the bilingual captions drawn on.",
+			examples: []string{
+				`tsplice translate --to=es ./talk.mp4`,
+				`tsplice translate --to=de --burn ./talk.mp4`,
+			},
+			flags: new(translateFlags).define,
+			run:   runTranslate,
+		},
+		{
+			name:        "watch",
+			usage:       "tsplice watch [options] <obs-output-folder>",
+			summary:     "transcribe OBS replays as they're saved and join them by day",
+			description: "Picks up every replay buffer OBS saves to its recording folder, transcribes it, and joins it onto that day's replays video with a matching transcript.",
+			examples: []string{
+				`tsplice watch ./Videos/OBS`,
+				`tsplice watch --existing ./Videos/OBS`,
+			},
+			flags: new(watchFlags).define,
+			run:   runWatch,
+		},
+	}
+}
+
+// openInEditor opens a video like any other, keeping the options given before
+// the subcommand. Its transcript is found next to it, wherever it's run from.
+func openInEditor(videoFile string, flags ...string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var globalFlags []string
+	flag.Visit(func(f *flag.Flag) {
+		globalFlags = append(globalFlags, "--"+f.Name+"="+f.Value.String())
+	})
+	cmd := exec.Command(executable, append(append(globalFlags, flags...), videoFile)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		// It already printed its own error
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// parseCommandFlags parses flags for a subcommand while allowing them to
+// appear before or after positional arguments.
+func parseCommandFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func newCommandFlagSet(cmd string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.Usage = func() {
+		c, _ := findCommand(cmd)
+		var flags []*flag.Flag
+		fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+		printCommandHelp(c, flags)
+	}
+	return fs
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// mediaFormat is what every piece of a concat has to agree on.
+type mediaFormat struct {
+	width, height int
+	frameRate     string
+	pixFmt        string
+	sampleRate    int
+	channelLayout string
+}
+
+// formatOf reads the format of the first video and audio stream, falling back
+// to common defaults for anything the probe didn't report.
+func formatOf(probe probeResult) mediaFormat {
+	format := mediaFormat{width: 1920, height: 1080, frameRate: "30", pixFmt: "yuv420p", sampleRate: 48000, channelLayout: "stereo"}
+
+	videoFound, audioFound := false, false
+	for _, stream := range probe.Streams {
+		switch {
+		case stream.CodecType == "video" && !videoFound:
+			videoFound = true
+			if stream.Width > 0 && stream.Height > 0 {
+				format.width, format.height = stream.Width, stream.Height
+			}
+			if stream.RFrameRate != "" && stream.RFrameRate != "0/0" {
+				format.frameRate = stream.RFrameRate
+			}
+			if stream.PixFmt != "" {
+				format.pixFmt = stream.PixFmt
+			}
+
+		case stream.CodecType == "audio" && !audioFound:
+			audioFound = true
+			if rate, err := strconv.Atoi(stream.SampleRate); err == nil && rate > 0 {
+				format.sampleRate = rate
+			}
+			if stream.ChannelLayout != "" {
+				format.channelLayout = stream.ChannelLayout
+			}
+		}
+	}
+
+	return format
+}
+
+func (f mediaFormat) normalizeVideo(in, out string) string {
+	return fmt.Sprintf("%sscale=%[2]d:%[3]d:force_original_aspect_ratio=decrease,pad=%[2]d:%[3]d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%s,format=%s%s",
+		in, f.width, f.height, f.frameRate, f.pixFmt, out)
+}
+
+func (f mediaFormat) normalizeAudio(in, out string) string {
+	return fmt.Sprintf("%saresample=%d,aformat=sample_fmts=fltp:channel_layouts=%s%s", in, f.sampleRate, f.channelLayout, out)
+}
+
+func (f mediaFormat) silence(duration float64, out string) string {
+	return fmt.Sprintf("anullsrc=r=%d:cl=%s,atrim=duration=%.3f%s", f.sampleRate, f.channelLayout, duration, out)
+}
+
+// attachClips puts the intro and outro clips around the compiled selection,
+// whose filter graph outputs are [v] and [a0], [a1] and so on. Every piece is
+// first normalized to the resolution, frame rate, pixel format, and audio
+// format of the source, so concat neither fails nor drifts out of sync. It
+// returns the clip inputs, the extra filters, the output maps, and how much
+// the clips add to the duration.
+func attachClips(probe probeResult, firstInput int, audioStreams int, opts compileOptions) ([]string, []string, []string, float64, error) {
+	format := formatOf(probe)
+	if opts.SampleRate > 0 {
+		format.sampleRate = opts.SampleRate
+	}
+	if opts.ConstantFrameRate != "" {
+		format.frameRate = opts.ConstantFrameRate
+	}
+
+	var inputArgs, filters, concatInputs []string
+	var added float64
+	pieces := 0
+
+	addClip := func(clip string) error {
+		clipProbe, err := probeStreams(clip)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", clip, err)
+		}
+		duration, err := probeDuration(clip)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", clip, err)
+		}
+		if countStreams(clipProbe, "video") == 0 {
+			return fmt.Errorf("%s has no video stream", clip)
+		}
+
+		input := firstInput + len(inputArgs)/2
+		inputArgs = append(inputArgs, "-i", clip)
+		added += duration
+
+		label := fmt.Sprintf("[p%dv]", pieces)
+		filters = append(filters, format.normalizeVideo(fmt.Sprintf("[%d:v:0]", input), label))
+		concatInputs = append(concatInputs, label)
+
+		// Tracks the clip doesn't have are filled with silence
+		clipAudio := countStreams(clipProbe, "audio")
+		for track := range audioStreams {
+			label := fmt.Sprintf("[p%da%d]", pieces, track)
+			if track < clipAudio {
+				filters = append(filters, format.normalizeAudio(fmt.Sprintf("[%d:a:%d]", input, track), label))
+			} else {
+				filters = append(filters, format.silence(duration, label))
+			}
+			concatInputs = append(concatInputs, label)
+		}
+
+		pieces++
+		return nil
+	}
+
+	if opts.Intro != "" {
+		if err := addClip(opts.Intro); err != nil {
+			return nil, nil, nil, 0, err
+		}
+	}
+
+	label := fmt.Sprintf("[p%dv]", pieces)
+	filters = append(filters, format.normalizeVideo("[v]", label))
+	concatInputs = append(concatInputs, label)
+	for track := range audioStreams {
+		label := fmt.Sprintf("[p%da%d]", pieces, track)
+		filters = append(filters, format.normalizeAudio(fmt.Sprintf("[a%d]", track), label))
+		concatInputs = append(concatInputs, label)
+	}
+	pieces++
+
+	if opts.Outro != "" {
+		if err := addClip(opts.Outro); err != nil {
+			return nil, nil, nil, 0, err
+		}
+	}
+
+	maps := []string{"-map", "[cv]"}
+	outputs := "[cv]"
+	for track := range audioStreams {
+		outputs += fmt.Sprintf("[ca%d]", track)
+		maps = append(maps, "-map", fmt.Sprintf("[ca%d]", track))
+	}
+	filters = append(filters, fmt.Sprintf("%sconcat=n=%d:v=1:a=%d%s", strings.Join(concatInputs, ""), pieces, audioStreams, outputs))
+
+	return inputArgs, filters, maps, added, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ruleConditions are the segment checks shared by every config-defined rule.
+// A rule only matches when all of the conditions it sets hold.
+type ruleConditions struct {
+	Tag           string  `toml:"tag"`
+	Keyword       string  `toml:"keyword"`
+	Match         string  `toml:"match"`
+	Speaker       string  `toml:"speaker"`
+	MinDuration   float64 `toml:"min_duration"`
+	MaxDuration   float64 `toml:"max_duration"`
+	MaxConfidence float64 `toml:"max_confidence"`
+}
+
+type conditionMatcher struct {
+	ruleConditions
+	pattern *regexp.Regexp
+}
+
+func newConditionMatcher(c ruleConditions) (conditionMatcher, error) {
+	matcher := conditionMatcher{ruleConditions: c}
+	matcher.Keyword = strings.ToLower(c.Keyword)
+	if c.Match != "" {
+		pattern, err := regexp.Compile("(?i)" + c.Match)
+		if err != nil {
+			return matcher, err
+		}
+		matcher.pattern = pattern
+	}
+	return matcher, nil
+}
+
+func (c conditionMatcher) empty() bool {
+	return c.ruleConditions == ruleConditions{}
+}
+
+func (c conditionMatcher) matches(i item) bool {
+	if c.empty() {
+		return false
+	}
+	if c.Tag != "" && !slices.Contains(i.tags, c.Tag) {
+		return false
+	}
+	if c.Keyword != "" && !strings.Contains(strings.ToLower(i.title), c.Keyword) {
+		return false
+	}
+	if c.pattern != nil && !c.pattern.MatchString(i.title) {
+		return false
+	}
+	if c.Speaker != "" && c.Speaker != i.speaker {
+		return false
+	}
+	if c.MinDuration != 0 || c.MaxDuration != 0 {
+		duration := i.end - i.start
+		if c.MinDuration != 0 && duration < c.MinDuration {
+			return false
+		}
+		if c.MaxDuration != 0 && duration > c.MaxDuration {
+			return false
+		}
+	}
+	// Items without confidence info never match a confidence rule
+	if c.MaxConfidence != 0 && (i.confidence == 0 || i.confidence > c.MaxConfidence) {
+		return false
+	}
+	return true
+}
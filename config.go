@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+type config struct {
+	Tags       []string           `toml:"tags"`
+	Colors     []colorRuleConfig  `toml:"colors"`
+	Fillers    []string           `toml:"fillers"`
+	AutoSelect []autoSelectConfig `toml:"auto_select"`
+	Bleep      []string           `toml:"bleep"`
+	Audiogram  audiogramColors    `toml:"audiogram"`
+	LowerThird lowerThirdConfig   `toml:"lower_third"`
+	Captions   captionConfig      `toml:"captions"`
+	// CaptionSpeakers styles each speaker's captions, by name
+	CaptionSpeakers map[string]speakerStyle `toml:"caption_speakers"`
+	QuietGain       float64                 `toml:"quiet_gain"`
+	// Offline and STTCommand mirror --offline and --stt-command,
+	// so the mode can be turned on once for every run
+	Offline    bool   `toml:"offline"`
+	STTCommand string `toml:"stt_command"`
+	// Provider, Local, and WhisperModel mirror --provider, --local, and
+	// --whisper-model
+	Provider     string `toml:"provider"`
+	Local        bool   `toml:"local"`
+	WhisperModel string `toml:"whisper_model"`
+	WhisperBin   string `toml:"whisper_bin"`
+	Encrypt      bool   `toml:"encrypt"`
+	APIKeyCmd    string `toml:"api_key_cmd"`
+	// Embed and EmbedCommand mirror --embed and --embed-command
+	Embed        bool   `toml:"embed"`
+	EmbedCommand string `toml:"embed_command"`
+	// Profile mirrors --profile, picking from the built-in profiles and
+	// Profiles
+	Profile  string             `toml:"profile"`
+	Profiles map[string]profile `toml:"profiles"`
+	// UILang mirrors --ui-lang
+	UILang string `toml:"ui_lang"`
+	// Lang and Prompt mirror --lang and --prompt, for subcommands that
+	// transcribe too
+	Lang   string `toml:"lang"`
+	Prompt string `toml:"prompt"`
+	// OutputDir and PreviewPlayer mirror --output-dir and --player
+	OutputDir     string `toml:"output_dir"`
+	PreviewPlayer string `toml:"preview_player"`
+	// VideoCodec, AudioCodec, and CRF are how compiles encode when the
+	// profile doesn't say
+	VideoCodec string `toml:"video_codec"`
+	AudioCodec string `toml:"audio_codec"`
+	CRF        int    `toml:"crf"`
+	// APIBase and Model mirror --api-base and --model
+	APIBase string `toml:"api_base"`
+	Model   string `toml:"model"`
+	// CrashReports mirrors --crash-reports
+	CrashReports bool `toml:"crash_reports"`
+}
+
+type colorRuleConfig struct {
+	ruleConditions
+	Color string `toml:"color"`
+	Bold  bool   `toml:"bold"`
+}
+
+type autoSelectConfig struct {
+	ruleConditions
+	Action string `toml:"action"`
+}
+
+func defaultConfig() config {
+	return config{
+		Tags:    []string{"hook", "b-roll needed", "cut"},
+		Fillers: []string{"um", "uh", "erm", "ah", "like", "you know", "i mean", "basically", "actually", "literally", "sort of", "kind of"},
+		// Match the highlight and text colors of the list
+		Audiogram:  audiogramColors{Waveform: "3", Background: "0", Captions: "15"},
+		LowerThird: lowerThirdConfig{Duration: 4, Color: "15", Background: "0"},
+		Captions:   captionConfig{Size: 54, Position: "bottom", Color: "15"},
+		QuietGain:  defaultQuietGain,
+	}
+}
+
+func configPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "tsplice", "config.toml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "tsplice", "config.toml")
+	}
+	return filepath.Join(home, ".config", "tsplice", "config.toml")
+}
+
+// loadConfig reads the config file if there is one, keeping defaults for
+// anything it doesn't set.
+func loadConfig() (config, error) {
+	cfg := defaultConfig()
+
+	if _, err := os.Stat(configPath()); os.IsNotExist(err) {
+		return cfg, nil
+	}
+
+	if _, err := toml.DecodeFile(configPath(), &cfg); err != nil {
+		return cfg, err
+	}
+
+	// Only keys 1-9 are available for tagging
+	if len(cfg.Tags) > 9 {
+		cfg.Tags = cfg.Tags[:9]
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// crashReports is set with --crash-reports or crash_reports = true in the
+// config. A panic then leaves a report in the cache folder and offers to
+// open an issue with it filled in. tsplice never sends it anywhere itself.
+var crashReports bool
+
+const (
+	issuesURL = "https://github.com/aschmelyun/tsplice/issues/new"
+	// crashHistoryLength is how many of the last commands a report has
+	crashHistoryLength = 10
+	// issueBodyLimit keeps the issue's link short enough for browsers to
+	// open, the report's file has everything
+	issueBodyLimit = 4000
+)
+
+// crashHistory is the last commands tsplice ran, remembered for a report.
+var crashHistory struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+// rememberCommand keeps a command for a crash report, with its paths left
+// out already in case it's the only thing being kept.
+func rememberCommand(name string, args []string) {
+	if !crashReports {
+		return
+	}
+	crashHistory.mu.Lock()
+	defer crashHistory.mu.Unlock()
+	crashHistory.commands = append(crashHistory.commands, redactCommand(name, args))
+	if len(crashHistory.commands) > crashHistoryLength {
+		crashHistory.commands = crashHistory.commands[1:]
+	}
+}
+
+// redactCommand is a command line with its files' folders and names taken
+// out, keeping the extension since it's often what matters.
+func redactCommand(name string, args []string) string {
+	redacted := []string{filepath.Base(name)}
+	for _, arg := range args {
+		if flag, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(flag, "-") {
+			redacted = append(redacted, flag+"="+redactPath(value))
+			continue
+		}
+		redacted = append(redacted, redactPath(arg))
+	}
+	return strings.Join(redacted, " ")
+}
+
+// redactPath hides an argument that's a file or folder on this machine.
+// Filters and expressions that happen to have a slash are left alone, since
+// their folder doesn't exist.
+func redactPath(arg string) string {
+	if arg == "" || strings.HasPrefix(arg, "-") {
+		return arg
+	}
+	_, err := os.Stat(arg)
+	isPath := err == nil || filepath.IsAbs(arg)
+	if !isPath && strings.ContainsAny(arg, `/\`) {
+		_, err := os.Stat(filepath.Dir(arg))
+		isPath = err == nil
+	}
+	if !isPath {
+		return arg
+	}
+	return "<path>" + filepath.Ext(arg)
+}
+
+// redactHome takes the home folder, and with it the user's name, out of
+// whatever made it into a report without being redacted, like a panic's
+// message or the paths in a stack.
+func redactHome(text string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || len(home) < 2 {
+		return text
+	}
+	return strings.ReplaceAll(text, home, "~")
+}
+
+// caughtPanic is the first panic in the editor, noted by crashGuard so it
+// can be reported once Bubble Tea is done and the terminal is back.
+var caughtPanic struct {
+	once  sync.Once
+	value any
+	stack []byte
+}
+
+// notePanic notes a panic in the editor and lets it carry on to Bubble Tea,
+// which restores the terminal.
+func notePanic() {
+	if r := recover(); r != nil {
+		stack := debug.Stack()
+		caughtPanic.once.Do(func() {
+			caughtPanic.value, caughtPanic.stack = r, stack
+		})
+		panic(r)
+	}
+}
+
+// crashGuard wraps the editor's model to note where it panics, in Update,
+// View, or any command it returns.
+type crashGuard struct {
+	tea.Model
+}
+
+func (g crashGuard) Init() tea.Cmd {
+	return guardCmd(g.Model.Init())
+}
+
+func (g crashGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer notePanic()
+	m, cmd := g.Model.Update(msg)
+	return crashGuard{m}, guardCmd(cmd)
+}
+
+func (g crashGuard) View() string {
+	defer notePanic()
+	return g.Model.View()
+}
+
+// guardCmd notes a panic in a command, and in each command of a batch,
+// which Bubble Tea runs on their own.
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		defer notePanic()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for index := range batch {
+				batch[index] = guardCmd(batch[index])
+			}
+		}
+		return msg
+	}
+}
+
+// reportEditorCrash reports the panic that closed the editor, if one did.
+func reportEditorCrash() bool {
+	if caughtPanic.value == nil {
+		return false
+	}
+	reportCrash(caughtPanic.value, caughtPanic.stack)
+	return true
+}
+
+// catchCrash reports a panic outside the editor, deferred in main. Without
+// --crash-reports the panic goes on as it always would.
+func catchCrash() {
+	if !crashReports {
+		return
+	}
+	if r := recover(); r != nil {
+		stack := debug.Stack()
+		fmt.Println()
+		shutdown()
+		reportCrash(r, stack)
+		os.Exit(2)
+	}
+}
+
+// reportCrash writes a report for a panic and offers to open an issue with
+// it, which is left for the user to read over and submit.
+func reportCrash(value any, stack []byte) {
+	report := crashReport(value, stack)
+	file, err := writeCrashReport(report)
+	if err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("tsplice crashed and could not save a report: "+err.Error()))
+		return
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("tsplice crashed, a report was saved to "+file))
+	if offline || !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Open an issue with it at "+issuesURL))
+		return
+	}
+	if !confirm("Open a GitHub issue with the report filled in, to look over before submitting?") {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Nothing was sent."))
+		return
+	}
+	link := issueURL(value, report, file)
+	if err := openURL(link); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Could not open a browser, open an issue at "+issuesURL))
+		return
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Opened the issue in your browser, nothing is sent until you submit it."))
+}
+
+// crashReport is the report for a panic, with the versions involved and the
+// last commands run before it.
+func crashReport(value any, stack []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "panic: %v\n\n", value)
+	fmt.Fprintf(&b, "tsplice %s, %s, %s/%s\n", VERSION, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "%s\n", ffmpegVersion())
+	fmt.Fprintf(&b, "run as: %s\n\n", redactCommand("tsplice", os.Args[1:]))
+
+	crashHistory.mu.Lock()
+	commands := crashHistory.commands
+	crashHistory.mu.Unlock()
+	b.WriteString("last commands:\n")
+	if len(commands) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, command := range commands {
+		b.WriteString("  " + command + "\n")
+	}
+
+	b.WriteString("\n")
+	b.Write(stack)
+	return redactHome(b.String())
+}
+
+// ffmpegVersion is the first line of ffmpeg -version. It's run directly,
+// since tsplice is already shutting down and starts nothing more through
+// execute.
+func ffmpegVersion() string {
+	out, err := exec.Command("ffmpeg", "-version").Output()
+	if err != nil {
+		return "ffmpeg not found"
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimSpace(line)
+}
+
+// writeCrashReport saves a report in the cache folder, named for when it
+// happened.
+func writeCrashReport(report string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "crashes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, "crash-"+time.Now().Format("20060102-150405")+".txt")
+	return file, os.WriteFile(file, []byte(report), 0644)
+}
+
+// issueURL opens a new issue with the report filled in, cut short where it
+// would make the link too long for a browser.
+func issueURL(value any, report, file string) string {
+	title := truncate(fmt.Sprintf("Crash: %v", value), 80)
+	if len(report) > issueBodyLimit {
+		report = report[:issueBodyLimit] + "\n... (cut short, the rest is in " + filepath.Base(file) + ")"
+	}
+	body := "<!-- Look the report over before submitting, and say what you were doing when tsplice crashed. -->\n\n```\n" + report + "\n```\n"
+	return issuesURL + "?" + url.Values{"title": {redactHome(title)}, "body": {body}}.Encode()
+}
+
+// openURL opens a link in the default browser.
+func openURL(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	return cmd.Start()
+}
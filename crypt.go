@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+const (
+	// encryptedMagic starts every encrypted file, so they're read back
+	// without having to be told which ones are
+	encryptedMagic = "tsplice-encrypted-v1\n"
+	saltSize       = 16
+	// keyIterations follows OWASP's current recommendation for PBKDF2-SHA256
+	keyIterations = 600000
+)
+
+// encryptAtRest is set with --encrypt or encrypt = true in the config, and
+// turns on by itself once an encrypted file is read, so edits stay encrypted.
+var encryptAtRest bool
+
+var vault struct {
+	mu         sync.Mutex
+	passphrase string
+	// Deriving a key is slow on purpose, so each salt is only done once and
+	// every file written this session shares one
+	keys        map[string][]byte
+	sessionSalt []byte
+}
+
+// unlock asks for the passphrase the first time it's needed, unless it's in
+// TSPLICE_PASSPHRASE. New passphrases are asked for twice.
+func unlock(creating bool) (string, error) {
+	if vault.passphrase != "" {
+		return vault.passphrase, nil
+	}
+	if passphrase := os.Getenv("TSPLICE_PASSPHRASE"); passphrase != "" {
+		vault.passphrase = passphrase
+		return passphrase, nil
+	}
+
+	read := func(prompt string) (string, error) {
+		fmt.Print(BulletStyle.Render("├") + TextStyle.Render(prompt))
+		bytePassphrase, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("could not read passphrase: %w", err)
+		}
+		return strings.TrimSpace(string(bytePassphrase)), nil
+	}
+
+	passphrase, err := read("Passphrase for encrypted transcripts: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("a passphrase is required for encrypted transcripts")
+	}
+	if creating {
+		again, err := read("Enter it again: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases don't match")
+		}
+	}
+
+	vault.passphrase = passphrase
+	return passphrase, nil
+}
+
+func keyFor(salt []byte, creating bool) ([]byte, error) {
+	vault.mu.Lock()
+	defer vault.mu.Unlock()
+
+	if key, ok := vault.keys[string(salt)]; ok {
+		return key, nil
+	}
+	passphrase, err := unlock(creating)
+	if err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, keyIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	if vault.keys == nil {
+		vault.keys = map[string][]byte{}
+	}
+	vault.keys[string(salt)] = key
+	return key, nil
+}
+
+func isEncrypted(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(encryptedMagic))
+}
+
+func isEncryptedFile(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && isEncrypted(content)
+}
+
+// readProjectFile reads a transcript or project file, decrypting it if it
+// was saved encrypted.
+func readProjectFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil || !isEncrypted(content) {
+		return content, err
+	}
+
+	encryptAtRest = true
+	content = content[len(encryptedMagic):]
+	if len(content) < saltSize {
+		return nil, fmt.Errorf("%s is damaged", path)
+	}
+	salt, content := content[:saltSize], content[saltSize:]
+
+	key, err := keyFor(salt, false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%s is damaged", path)
+	}
+	nonce, content := content[:gcm.NonceSize()], content[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, content, []byte(encryptedMagic))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt %s, is the passphrase right?", path)
+	}
+	return plaintext, nil
+}
+
+// writeProjectFile saves a transcript or project file, encrypted when
+// encryption at rest is on.
+func writeProjectFile(path string, content []byte) error {
+	if isSource(path) {
+		return fmt.Errorf("refusing to write over the original video %s", path)
+	}
+	if !encryptAtRest {
+		return os.WriteFile(path, content, 0644)
+	}
+
+	vault.mu.Lock()
+	if vault.sessionSalt == nil {
+		vault.sessionSalt = make([]byte, saltSize)
+		rand.Read(vault.sessionSalt)
+	}
+	salt := vault.sessionSalt
+	vault.mu.Unlock()
+
+	key, err := keyFor(salt, true)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+
+	var b bytes.Buffer
+	b.WriteString(encryptedMagic)
+	b.Write(salt)
+	b.Write(nonce)
+	b.Write(gcm.Seal(nil, nonce, content, []byte(encryptedMagic)))
+	return os.WriteFile(path, b.Bytes(), 0600)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// verifyPassphrase checks the passphrase against the video's files that are
+// already encrypted, so a mistyped one isn't used to encrypt the rest.
+func verifyPassphrase(inputFile string) error {
+	for _, file := range projectFiles(inputFile) {
+		if isEncryptedFile(file) {
+			_, err := readProjectFile(file)
+			return err
+		}
+	}
+	return nil
+}
+
+// projectFiles are everything saved about a video alongside its transcript.
+func projectFiles(inputFile string) []string {
+	vttFile := vttPath(inputFile)
+	return []string{vttFile, detailsPath(vttFile), journalPath(vttFile), sharedPath(inputFile), meetingChatPath(vttFile)}
+}
+
+// encryptProject encrypts a video's files that were saved before encryption
+// was turned on, returning how many there were.
+func encryptProject(inputFile string) (int, error) {
+	encrypted := 0
+	for _, file := range projectFiles(inputFile) {
+		content, err := os.ReadFile(file)
+		if err != nil || isEncrypted(content) {
+			continue
+		}
+		if err := writeProjectFile(file, content); err != nil {
+			return encrypted, err
+		}
+		encrypted++
+	}
+
+	// The store can't be encrypted, and everything but the selection is
+	// already in the files above. Neither can the proxy or local copy, which
+	// are made again from the source if encryption is turned off
+	database := storePath(vttPath(inputFile))
+	for _, file := range []string{database, database + "-wal", database + "-shm"} {
+		os.Remove(file)
+	}
+	for _, cached := range []func(string) (string, error){proxyPath, localCopyPath} {
+		if file, err := cached(inputFile); err == nil {
+			os.Remove(file)
+		}
+	}
+	return encrypted, nil
+}
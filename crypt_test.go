@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useVault starts a session with nothing unlocked, the passphrase set as if
+// it were in TSPLICE_PASSPHRASE, and encryption at rest on or off.
+func useVault(t *testing.T, passphrase string, encrypting bool) {
+	t.Helper()
+	reset := func() {
+		vault.passphrase, vault.keys, vault.sessionSalt = "", nil, nil
+		encryptAtRest = false
+	}
+	reset()
+	t.Cleanup(reset)
+	t.Setenv("TSPLICE_PASSPHRASE", passphrase)
+	encryptAtRest = encrypting
+}
+
+func TestProjectFileRoundTrip(t *testing.T) {
+	useVault(t, "correct horse", true)
+	vttFile := filepath.Join(t.TempDir(), "talk.vtt")
+
+	if err := writeProjectFile(vttFile, []byte(testVTT)); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(vttFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(saved) || bytes.Contains(saved, []byte("First line")) {
+		t.Fatal("transcript was saved in plaintext")
+	}
+
+	// A new session derives the key again from the passphrase
+	useVault(t, "correct horse", false)
+	content, err := readProjectFile(vttFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != testVTT {
+		t.Errorf("read back %q, want the transcript", content)
+	}
+	if !encryptAtRest {
+		t.Error("reading an encrypted file didn't turn encryption on")
+	}
+}
+
+func TestWrongPassphraseIsAnErrorAndNothingIsOverwritten(t *testing.T) {
+	useVault(t, "correct horse", true)
+	inputFile := filepath.Join(t.TempDir(), "talk.mp4")
+	vttFile := vttPath(inputFile)
+	if err := writeProjectFile(vttFile, []byte(testVTT)); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(vttFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useVault(t, "battery staple", true)
+	if _, err := readProjectFile(vttFile); err == nil {
+		t.Fatal("read the transcript with the wrong passphrase")
+	}
+	if err := verifyPassphrase(inputFile); err == nil {
+		t.Error("the wrong passphrase was verified")
+	}
+
+	fake := useFakeExecutor(t)
+	if _, err := transcriptFor(inputFile, fakeTranscriber{vttContent: testVTT}); err == nil {
+		t.Error("transcriptFor didn't return the decryption error")
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("transcribed again instead, ran %v", fake.calls)
+	}
+	if after, _ := os.ReadFile(vttFile); !bytes.Equal(after, saved) {
+		t.Error("the transcript was written over")
+	}
+}
+
+func TestPlaintextProjectFilesPassThrough(t *testing.T) {
+	useVault(t, "", false)
+	vttFile := filepath.Join(t.TempDir(), "talk.vtt")
+
+	if err := writeProjectFile(vttFile, []byte(testVTT)); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := os.ReadFile(vttFile); string(saved) != testVTT {
+		t.Errorf("saved %q, want the transcript as is", saved)
+	}
+	content, err := readProjectFile(vttFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != testVTT {
+		t.Errorf("read back %q, want the transcript", content)
+	}
+	if encryptAtRest {
+		t.Error("reading a plaintext file turned encryption on")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// cutFlags are the options of tsplice cut.
+type cutFlags struct {
+	match       string
+	draft       bool
+	split       bool
+	streamCopy  bool
+	dryRun      bool
+	lang        string
+	prompt      string
+	profileName string
+}
+
+func (f *cutFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.match, "match", "", "Regular expression picking the lines to keep, e.g. \"introduction|summary\"")
+	fs.BoolVar(&f.draft, "draft", false, "Render a quick low resolution draft instead of the final video")
+	fs.BoolVar(&f.split, "split", false, "Save each matching line as its own numbered clip instead of one video")
+	fs.BoolVar(&f.streamCopy, "copy", false, "Cut at keyframes without re-encoding, fast and lossless but not frame accurate")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "List the matching lines without compiling anything")
+	fs.StringVar(&f.lang, "lang", defaultLanguage, "Language used if the video has to be transcribed first")
+	fs.StringVar(&f.prompt, "prompt", defaultPrompt, "Prompt used if the video has to be transcribed first")
+	fs.StringVar(&f.profileName, "profile", activeProfile, "Profile to trim silence and encode with")
+}
+
+func runCut(args []string) error {
+	fs := newCommandFlagSet("cut")
+	var flags cutFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || flags.match == "" {
+		return fmt.Errorf("usage: tsplice cut --match=<regex> [options] <input-file>")
+	}
+	pattern, err := regexp.Compile(flags.match)
+	if err != nil {
+		return fmt.Errorf("--match isn't a valid regular expression: %w", err)
+	}
+	p, err := findProfile(flags.profileName)
+	if err != nil {
+		return err
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+	if _, err := os.Stat(vttPath(inputFile)); os.IsNotExist(err) && transcribesWithOpenAI() {
+		if err := setupAPIKey(); err != nil {
+			return err
+		}
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, newTranscriber(flags.lang, flags.prompt))
+	if err != nil {
+		return err
+	}
+	items := matchItems(toListItems(transcriptItems), pattern)
+	segments := selectedSegments(items)
+	if len(segments) == 0 {
+		return fmt.Errorf("no lines in %s match %q", filepath.Base(inputFile), flags.match)
+	}
+
+	var kept float64
+	for _, listItem := range items {
+		if i, ok := listItem.(item); ok && i.selected {
+			kept += i.end - i.start
+			fmt.Println(BulletStyle.Render("├────") + DimTextStyle.Render(formatDuration(i.start)+"  ") + TextStyle.Render(i.title))
+		}
+	}
+	summary := fmt.Sprintf("%d of %d lines match, %s in all", len(segments), len(items), formatDuration(kept))
+	if flags.dryRun {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render(summary+"."))
+		return nil
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(summary))
+
+	opts := compileOptions{Draft: flags.draft, StreamCopy: flags.streamCopy, Split: flags.split}
+	p.apply(&opts)
+	opts.TrimSilence = p.TrimSilence
+	opts.SilenceThreshold = p.SilenceThreshold
+	if err := checkStreamCopy(opts); err != nil {
+		return err
+	}
+	if p.VideoCodec != "" || p.AudioCodec != "" {
+		if _, err := probeEncoders(); err != nil {
+			return err
+		}
+		if err := p.checkEncoders(); err != nil {
+			return err
+		}
+	}
+
+	// The same work the editor does once c is pressed, without the editor
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Compiling %d segments with ffmpeg...", len(segments))))
+	switch msg := compileVideoCmd(inputFile, items, opts)().(type) {
+	case errorMsg:
+		return msg.err
+	case videoCompilationDoneMsg:
+		for _, warning := range msg.warnings {
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Warning: "+warning))
+		}
+		if len(msg.clips) > 0 {
+			for _, clip := range msg.clips[:len(msg.clips)-1] {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Saved clip to "+clip))
+			}
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved clip to "+msg.clips[len(msg.clips)-1]))
+			return nil
+		}
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved output to "+msg.outputFile))
+	}
+	return nil
+}
+
+// matchItems selects the lines whose text the pattern matches, and only
+// those.
+func matchItems(items []list.Item, pattern *regexp.Regexp) []list.Item {
+	matched := make([]list.Item, len(items))
+	for index, listItem := range items {
+		if i, ok := listItem.(item); ok {
+			i.selected = pattern.MatchString(i.title)
+			listItem = i
+		}
+		matched[index] = listItem
+	}
+	return matched
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const diffWindowSize = 6
+
+// alignTranscripts pairs segments from a previous and a new transcription by
+// overlapping time ranges. Segments without a counterpart get a row of their own.
+func alignTranscripts(oldItems, newItems []TranscriptItem) []diffRow {
+	var rows []diffRow
+	i, j := 0, 0
+
+	for i < len(oldItems) || j < len(newItems) {
+		if i >= len(oldItems) {
+			n := newItems[j]
+			rows = append(rows, diffRow{new: &n})
+			j++
+			continue
+		}
+		if j >= len(newItems) {
+			o := oldItems[i]
+			rows = append(rows, diffRow{old: &o})
+			i++
+			continue
+		}
+
+		o, n := oldItems[i], newItems[j]
+		oStart, _ := parseTimeToSeconds(o.StartTime)
+		oEnd, _ := parseTimeToSeconds(o.EndTime)
+		nStart, _ := parseTimeToSeconds(n.StartTime)
+		nEnd, _ := parseTimeToSeconds(n.EndTime)
+
+		overlap := min(oEnd, nEnd) - max(oStart, nStart)
+		if overlap > 0 {
+			rows = append(rows, diffRow{old: &o, new: &n})
+			i++
+			j++
+		} else if oStart < nStart {
+			rows = append(rows, diffRow{old: &o})
+			i++
+		} else {
+			rows = append(rows, diffRow{new: &n})
+			j++
+		}
+	}
+
+	return rows
+}
+
+// resolveDiff builds the final transcript from the version chosen for each row.
+func resolveDiff(rows []diffRow) []TranscriptItem {
+	var transcriptItems []TranscriptItem
+	for _, row := range rows {
+		chosen := row.new
+		if row.keepOld {
+			chosen = row.old
+		}
+		if chosen != nil {
+			transcriptItems = append(transcriptItems, *chosen)
+		}
+	}
+	return transcriptItems
+}
+
+func (r diffRow) changed() bool {
+	if r.old == nil || r.new == nil {
+		return true
+	}
+	return *r.old != *r.new
+}
+
+func (m model) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.diffCursor > 0 {
+			m.diffCursor--
+		}
+
+	case "down", "j":
+		if m.diffCursor < len(m.diffRows)-1 {
+			m.diffCursor++
+		}
+
+	case "left", "o":
+		m.diffRows[m.diffCursor].keepOld = true
+
+	case "right", "n":
+		m.diffRows[m.diffCursor].keepOld = false
+
+	case "tab", " ":
+		m.diffRows[m.diffCursor].keepOld = !m.diffRows[m.diffCursor].keepOld
+
+	case "O", "N":
+		for i := range m.diffRows {
+			m.diffRows[i].keepOld = msg.String() == "O"
+		}
+
+	case "enter":
+		transcriptItems := resolveDiff(m.diffRows)
+		if err := writeProjectFile(m.vttFile, []byte(formatVTT(transcriptItems))); err != nil {
+			return m, func() tea.Msg { return errorMsg{err: err} }
+		}
+		if err := saveDetails(m.vttFile, m.details); err != nil {
+			return m, func() tea.Msg { return errorMsg{err: err} }
+		}
+
+		m.statuses = append(m.statuses, tr("Transcript changes saved locally."))
+		m.diffing = false
+		m.diffRows = nil
+		m.transcriptItems = transcriptItems
+		m = m.syncStore(transcriptItems)
+		m.list = m.newTranscriptList(transcriptItems)
+		m, cmd := m.findRedactions(transcriptItems)
+		return m, tea.Batch(cmd, m.embedCmd())
+	}
+
+	return m, nil
+}
+
+func (m model) diffView() string {
+	var b strings.Builder
+
+	changes := 0
+	for _, row := range m.diffRows {
+		if row.changed() {
+			changes++
+		}
+	}
+	b.WriteString("  " + trf("Comparing with previous transcript (%d of %d segments changed)", changes, len(m.diffRows)) + "\n\n")
+
+	start := max(0, m.diffCursor-diffWindowSize/2)
+	end := min(len(m.diffRows), start+diffWindowSize)
+
+	for index := start; index < end; index++ {
+		row := m.diffRows[index]
+
+		cursor := "  "
+		if index == m.diffCursor {
+			cursor = SelectedItemStyle.Render("> ")
+		}
+
+		b.WriteString(cursor + renderDiffSide("old", row.old, row.keepOld, row.changed()) + "\n")
+		b.WriteString("  " + renderDiffSide("new", row.new, !row.keepOld, row.changed()) + "\n")
+	}
+
+	b.WriteString("\n" + DimTextStyle.Render("  "+tr("↑/↓ move • o/n keep old/new • tab toggle • O/N keep all • enter save")) + "\n")
+
+	return b.String()
+}
+
+func renderDiffSide(label string, transcriptItem *TranscriptItem, kept bool, changed bool) string {
+	checkbox := "☐"
+	if kept {
+		checkbox = "◼"
+	}
+
+	if transcriptItem == nil {
+		return DimTextStyle.Render(fmt.Sprintf("%s %s %s", checkbox, tr(label), tr("(no segment)")))
+	}
+
+	line := fmt.Sprintf("%s %s %s - %s  %s", checkbox, tr(label), transcriptItem.StartTime, transcriptItem.EndTime, transcriptItem.Text)
+	switch {
+	case !changed:
+		return DimTextStyle.Render(line)
+	case label == "old":
+		return ErrorStyle.Render(line)
+	default:
+		return SuccessStyle.Render(line)
+	}
+}
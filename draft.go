@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// draftHeight is how tall --draft compiles are, enough to review the cuts.
+const draftHeight = 540
+
+// draftManifestPath is where a video's last draft recorded its cut list.
+func draftManifestPath(inputFile string, opts compileOptions) string {
+	return manifestPath(filepath.Join(compiledDir(inputFile), compiledBasename(inputFile, opts)+"_draft.mp4"))
+}
+
+// renderFinal compiles the same segments as the last draft at full quality,
+// with the draft's options so nothing about the cut changes between them.
+func renderFinal(inputFile string, opts compileOptions) (string, error) {
+	draftFile := draftManifestPath(inputFile, opts)
+	content, err := os.ReadFile(draftFile)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no draft of %s found, compile one with --draft first", filepath.Base(inputFile))
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read draft manifest: %w", err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return "", fmt.Errorf("could not parse draft manifest: %w", err)
+	}
+
+	// Hashing the source again would take as long as the draft did
+	if info, err := os.Stat(inputFile); err != nil || info.Size() != m.Source.Size {
+		return "", fmt.Errorf("%s has changed since the draft, compile a new one with --draft", filepath.Base(inputFile))
+	}
+
+	m.Options.Draft = false
+	m.Options.LocalCopy = opts.LocalCopy
+	return compileFromManifest(inputFile, m)
+}
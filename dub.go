@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	speechModel = "tts-1"
+	// duckVolume is how loud the original audio stays under a dubbed line
+	duckVolume = 0.15
+	// maxTempo is how much a line can be sped up to fit its cue before it
+	// becomes hard to follow, anything longer just runs over
+	maxTempo = 2.0
+)
+
+// speechSynthesizer renders a line of text to an audio file.
+type speechSynthesizer interface {
+	Synthesize(text string, outputFile string) error
+}
+
+type openAISpeech struct {
+	voice string
+}
+
+func (s openAISpeech) Synthesize(text string, outputFile string) error {
+	if err := requireOnline("the transcript to OpenAI for speech"); err != nil {
+		return err
+	}
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"model":           speechModel,
+		"voice":           s.voice,
+		"input":           text,
+		"response_format": "wav",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", "https://api.openai.com/v1/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return fmt.Errorf("failed to save speech: %w", err)
+	}
+	return nil
+}
+
+// commandSpeech runs a local TTS program, such as piper, that reads the text
+// on stdin and writes audio to the path appended as its last argument.
+type commandSpeech struct {
+	args []string
+}
+
+func (s commandSpeech) Synthesize(text string, outputFile string) error {
+	cmd := exec.Command(s.args[0], append(s.args[1:], outputFile)...)
+	cmd.Stdin = strings.NewReader(text)
+	var out strings.Builder
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := runChild(cmd); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", s.args[0], err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// dubbedLine is a synthesized line and where it goes in the video.
+type dubbedLine struct {
+	file  string
+	start float64
+	end   float64
+	tempo float64
+}
+
+// synthesizeLines renders every line into dir, working out how much each one
+// needs to be sped up to fit inside its cue.
+func synthesizeLines(synthesizer speechSynthesizer, transcriptItems []TranscriptItem, dir string) ([]dubbedLine, error) {
+	var lines []dubbedLine
+	for index, transcriptItem := range transcriptItems {
+		if strings.TrimSpace(transcriptItem.Text) == "" {
+			continue
+		}
+
+		start, err := parseTimeToSeconds(transcriptItem.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeToSeconds(transcriptItem.EndTime)
+		if err != nil {
+			return nil, err
+		}
+
+		file := filepath.Join(dir, fmt.Sprintf("line_%04d.wav", index+1))
+		if err := synthesizer.Synthesize(transcriptItem.Text, file); err != nil {
+			return nil, fmt.Errorf("failed to synthesize line %d: %w", index+1, err)
+		}
+
+		tempo := 1.0
+		if duration, err := probeDuration(file); err == nil && end > start && duration > end-start {
+			tempo = min(duration/(end-start), maxTempo)
+		}
+
+		lines = append(lines, dubbedLine{file: file, start: start, end: end, tempo: tempo})
+	}
+	return lines, nil
+}
+
+// muxDub lays the dubbed lines over the original audio, ducked while each line
+// plays, and keeps the original as a second track.
+func muxDub(inputFile string, lines []dubbedLine, language string, outputFile string) error {
+	args := []string{"-y", "-i", inputFile}
+	var filters, mix, ducked []string
+
+	for index, line := range lines {
+		args = append(args, "-i", line.file)
+		filters = append(filters, fmt.Sprintf("[%d:a]atempo=%.3f,adelay=%d:all=1[d%d]", index+1, line.tempo, int(line.start*1000), index))
+		mix = append(mix, fmt.Sprintf("[d%d]", index))
+		ducked = append(ducked, fmt.Sprintf("between(t,%.3f,%.3f)", line.start, line.end))
+	}
+
+	filters = append([]string{fmt.Sprintf("[0:a:0]volume=%.2f:enable='%s'[bg]", duckVolume, strings.Join(ducked, "+"))}, filters...)
+	filters = append(filters, fmt.Sprintf("[bg]%samix=inputs=%d:duration=first:normalize=0[a]", strings.Join(mix, ""), len(lines)+1))
+
+	args = append(args,
+		"-filter_complex", strings.Join(filters, ";"),
+		"-map", "0:v:0", "-map", "[a]", "-map", "0:a:0",
+		"-c:v", "copy", "-c:a", "aac",
+		"-metadata:s:a:0", "language="+language,
+		"-metadata:s:a:0", "title=Dubbed",
+		"-metadata:s:a:1", "title=Original",
+	)
+
+	return renderTo(outputFile, func(partial string) error {
+		if err := execute.Run("ffmpeg", append(args, partial)...); err != nil {
+			return fmt.Errorf("failed to mix dubbed audio: %w", err)
+		}
+		return nil
+	})
+}
+
+// dubFlags are the options of tsplice dub.
+type dubFlags struct {
+	language   string
+	provider   string
+	voice      string
+	ttsCommand string
+	captions   string
+}
+
+func (f *dubFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.language, "to", "", "Language code to dub the video into (e.g. es, de, ja)")
+	fs.StringVar(&f.provider, "provider", "openai", "Text to speech provider, openai or command")
+	fs.StringVar(&f.voice, "voice", "alloy", "Voice to use with the openai provider")
+	fs.StringVar(&f.ttsCommand, "tts-command", "", "Program for the command provider, given the text on stdin and the output file as its last argument")
+	fs.StringVar(&f.captions, "captions", "", "Only dub these lines, e.g. a _selection.vtt exported with C")
+}
+
+func runDub(args []string) error {
+	fs := newCommandFlagSet("dub")
+	var flags dubFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || flags.language == "" {
+		return fmt.Errorf("usage: tsplice dub --to=<language> [options] <input-file>")
+	}
+
+	var synthesizer speechSynthesizer
+	switch flags.provider {
+	case "openai":
+		synthesizer = openAISpeech{voice: flags.voice}
+	case "command":
+		if strings.TrimSpace(flags.ttsCommand) == "" {
+			return fmt.Errorf("the command provider needs --tts-command")
+		}
+		synthesizer = commandSpeech{args: strings.Fields(flags.ttsCommand)}
+	default:
+		return fmt.Errorf("unknown provider %q, use openai or command", flags.provider)
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+
+	if err := setupAPIKey(); err != nil {
+		return err
+	}
+
+	var transcriptItems []TranscriptItem
+	if flags.captions != "" {
+		transcriptItems, err = loadTranscript(flags.captions)
+	} else {
+		transcriptItems, err = transcriptFor(inputFile, defaultTranscriber())
+	}
+	if err != nil {
+		return err
+	}
+	if len(transcriptItems) == 0 {
+		return fmt.Errorf("no lines to dub")
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Translating %d lines...", len(transcriptItems))))
+	translated, err := translateTranscript(transcriptItems, flags.language)
+	if err != nil {
+		return err
+	}
+
+	dir, err := tempDir("tsplice-dub-")
+	if err != nil {
+		return err
+	}
+	defer removeTemp(dir)
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Synthesizing speech with "+flags.provider+"..."))
+	lines, err := synthesizeLines(synthesizer, translated, dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Mixing dubbed audio with ffmpeg..."))
+	outputFile := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "_dub_" + flags.language + ".mp4"
+	if err := muxDub(inputFile, lines, flags.language, outputFile); err != nil {
+		return err
+	}
+
+	sped := 0
+	for _, line := range lines {
+		if line.tempo > 1 {
+			sped++
+		}
+	}
+	if sped > 0 {
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render(fmt.Sprintf("Sped up %d lines to fit their captions.", sped)))
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved output to "+outputFile))
+	return nil
+}
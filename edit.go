@@ -0,0 +1,171 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m model) startEditing() model {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return m
+	}
+	input := textinput.New()
+	input.Prompt = ""
+	input.Width = max(20, m.list.Width()-8)
+	input.SetValue(i.title)
+	input.CursorEnd()
+	input.Focus()
+
+	m.textInput = input
+	m.editingText = true
+	m.list.SetDelegate(m.newItemDelegate())
+	return m
+}
+
+func (m model) stopEditing() model {
+	m.editingText = false
+	m.list.SetDelegate(m.newItemDelegate())
+	return m
+}
+
+// updateEditing types into the line being edited, for fixing the names and
+// words the transcription got wrong, which is saved to the transcript on enter.
+func (m model) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m.stopEditing(), nil
+
+	case "enter":
+		m = m.stopEditing()
+		index := m.list.GlobalIndex()
+		i, ok := m.list.SelectedItem().(item)
+		text := strings.Join(strings.Fields(m.textInput.Value()), " ")
+		if !ok || text == "" || text == i.title {
+			return m, nil
+		}
+
+		m = m.pushUndo()
+		i.title = text
+		items := m.list.Items()
+		items[index] = i
+		m = m.saveText(items)
+		return m, tea.Batch(m.list.SetItem(index, i), m.embedCmd())
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.list.SetDelegate(m.newItemDelegate())
+	return m, cmd
+}
+
+// editingView is the text input while a line is being edited, or where it's
+// split while it's being split.
+func (m model) editingView() string {
+	if m.splitting {
+		return m.splitView()
+	}
+	if !m.editingText {
+		return ""
+	}
+	return m.textInput.View()
+}
+
+// saveText writes the text of any lines that were edited back to the
+// transcript, so compiles, exports, and later runs all get the corrected
+// text.
+func (m model) saveText(items []list.Item) model {
+	transcriptItems := slices.Clone(m.transcriptItems)
+	for index, listItem := range items {
+		if i, ok := listItem.(item); ok && index < len(transcriptItems) {
+			transcriptItems[index].Text = i.title
+		}
+	}
+	m, _ = m.saveTranscript(transcriptItems)
+	return m
+}
+
+// saveTranscript writes the transcript when its lines changed, reporting
+// whether it was saved. The words of a line with new text get it too, while
+// lines that were merged or split keep the words they had.
+func (m model) saveTranscript(transcriptItems []TranscriptItem) (model, bool) {
+	previous := map[[2]string]string{}
+	for _, transcriptItem := range m.transcriptItems {
+		previous[[2]string{transcriptItem.StartTime, transcriptItem.EndTime}] = transcriptItem.Text
+	}
+	words := m.details.Words
+	changed := len(transcriptItems) != len(m.transcriptItems)
+	for _, transcriptItem := range transcriptItems {
+		text, ok := previous[[2]string{transcriptItem.StartTime, transcriptItem.EndTime}]
+		if !ok {
+			changed = true
+		} else if text != transcriptItem.Text {
+			start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+			end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+			words = retextWords(words, start, end, transcriptItem)
+			changed = true
+		}
+	}
+	if !changed {
+		return m, true
+	}
+
+	if err := writeProjectFile(m.vttFile, []byte(formatVTT(transcriptItems))); err != nil {
+		m.statuses = append(m.statuses, trf("Could not save the transcript: %s", err.Error()))
+		return m, false
+	}
+	details := m.details
+	details.Words = words
+	if len(details.Confidence) > 0 {
+		// Confidence goes by when a line starts, which is new for split lines
+		details.Confidence = maps.Clone(details.Confidence)
+		for _, transcriptItem := range transcriptItems {
+			if transcriptItem.Confidence > 0 {
+				details.Confidence[transcriptItem.StartTime] = transcriptItem.Confidence
+			}
+		}
+	}
+	if len(details.Words) > 0 || len(details.Confidence) > 0 {
+		if err := saveDetails(m.vttFile, details); err != nil {
+			m.statuses = append(m.statuses, trf("Could not save the word timings: %s", err.Error()))
+		}
+		m.details = details
+	}
+	m.transcriptItems = transcriptItems
+	return m.syncStore(transcriptItems), true
+}
+
+// retextWords gives the words of a line its edited text. When there are as
+// many words as before, like a misspelled name fixed, each keeps its timing,
+// and otherwise they're spread over the line again the way transcripts
+// without word timings are.
+func retextWords(words []Word, start, end float64, transcriptItem TranscriptItem) []Word {
+	first := len(words)
+	last := first
+	for index, word := range words {
+		if word.Start >= start && word.Start < end {
+			first = min(first, index)
+			last = index + 1
+		}
+	}
+	if first == len(words) {
+		return words
+	}
+
+	fields := strings.Fields(transcriptItem.Text)
+	var replaced []Word
+	if len(fields) == last-first {
+		replaced = slices.Clone(words[first:last])
+		for index := range replaced {
+			replaced[index].Text = fields[index]
+		}
+	} else {
+		replaced = estimateWords([]TranscriptItem{transcriptItem})
+	}
+	return slices.Concat(words[:first], replaced, words[last:])
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	embeddingModel = "text-embedding-3-small"
+	// embeddingBatch is how many lines are embedded per request, and saved
+	// at a time so an interrupted run keeps what it got through
+	embeddingBatch = 200
+)
+
+// embedCommand is the local embedding model set with --embed-command or
+// embed_command in the config, used instead of OpenAI.
+var embedCommand string
+
+// Embedder turns lines of text into vectors for semantic search. Vectors are
+// only compared with others from the same Name.
+type Embedder interface {
+	Name() string
+	Embed(texts []string) ([][]float32, error)
+}
+
+type openAIEmbedder struct{}
+
+func (openAIEmbedder) Name() string {
+	return "openai:" + embeddingModel
+}
+
+func (openAIEmbedder) Embed(texts []string) ([][]float32, error) {
+	if err := requireOnline("the transcript to OpenAI"); err != nil {
+		return nil, err
+	}
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+	}
+
+	body, err := json.Marshal(map[string]any{"model": embeddingModel, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", "https://api.openai.com/v1/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embeddings struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&embeddings); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, data := range embeddings.Data {
+		if data.Index >= 0 && data.Index < len(vectors) {
+			vectors[data.Index] = data.Embedding
+		}
+	}
+	for _, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("API didn't return an embedding for every line")
+		}
+	}
+	return vectors, nil
+}
+
+// commandEmbedder runs a local program that reads one line of text per line
+// on stdin, and prints a JSON array of numbers for each on its own line.
+type commandEmbedder struct {
+	args []string
+}
+
+func (e commandEmbedder) Name() string {
+	return "command:" + strings.Join(e.args, " ")
+}
+
+func (e commandEmbedder) Embed(texts []string) ([][]float32, error) {
+	var input strings.Builder
+	for _, text := range texts {
+		input.WriteString(strings.Join(strings.Fields(text), " ") + "\n")
+	}
+
+	cmd := exec.Command(e.args[0], e.args[1:]...)
+	cmd.Stdin = strings.NewReader(input.String())
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := outputChild(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", e.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	var vectors [][]float32
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var vector []float32
+		if err := json.Unmarshal(scanner.Bytes(), &vector); err != nil {
+			return nil, fmt.Errorf("%s printed something other than a JSON array: %w", e.args[0], err)
+		}
+		vectors = append(vectors, vector)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("%s printed %d embeddings for %d lines", e.args[0], len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// defaultEmbedder is the local command if one is set, otherwise OpenAI.
+func defaultEmbedder() Embedder {
+	if args := strings.Fields(embedCommand); len(args) > 0 {
+		return commandEmbedder{args: args}
+	}
+	return openAIEmbedder{}
+}
+
+// encodeVector normalizes a vector before it's saved, so comparing two is
+// only a dot product.
+func encodeVector(vector []float32) []byte {
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		norm = 1
+	}
+
+	b := make([]byte, 4*len(vector))
+	for index, value := range vector {
+		binary.LittleEndian.PutUint32(b[4*index:], math.Float32bits(float32(float64(value)/norm)))
+	}
+	return b
+}
+
+func decodeVector(b []byte) []float32 {
+	vector := make([]float32, len(b)/4)
+	for index := range vector {
+		vector[index] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*index:]))
+	}
+	return vector
+}
+
+// similarity is the cosine similarity of two normalized vectors.
+func similarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for index := range a {
+		dot += float64(a[index]) * float64(b[index])
+	}
+	return dot
+}
+
+type embeddingsDoneMsg struct {
+	count int
+	err   error
+}
+
+// embedCmd embeds the lines of the transcript that haven't been yet, in the
+// background while the editor is open.
+func (m model) embedCmd() tea.Cmd {
+	if m.embedder == nil || m.store == nil {
+		return nil
+	}
+	s, embedder := m.store, m.embedder
+	return func() tea.Msg {
+		count, err := s.embed(embedder)
+		return embeddingsDoneMsg{count: count, err: err}
+	}
+}
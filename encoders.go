@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// h264Encoders are tried in order for H.264 output. libx264 comes first since
+// every option works with it, then the hardware encoders ffmpeg is often
+// built with instead.
+var h264Encoders = []string{"libx264", "h264_videotoolbox", "h264_nvenc", "h264_qsv", "h264_amf", "h264_mf"}
+
+// twoPassEncoders can do the two-pass encodes --max-size needs, which none of
+// the hardware ones can.
+var twoPassEncoders = []string{"libx264", "mpeg4"}
+
+// crfEncoders take a constant quality with -crf.
+var crfEncoders = []string{"libx264", "libx265", "libvpx-vp9", "libaom-av1", "libsvtav1"}
+
+// videoEncoder is what every output is encoded with, picked by probeEncoders.
+var videoEncoder = "libx264"
+
+// encoders is what the local ffmpeg can encode with, nil until it's probed.
+var encoders map[string]bool
+
+// parseEncoders reads the names out of ffmpeg -encoders, whose list starts
+// after a line of dashes with rows like " V....D libx264  libx264 H.264".
+func parseEncoders(output string) map[string]bool {
+	available := map[string]bool{}
+	_, list, found := strings.Cut(output, "------")
+	if !found {
+		return available
+	}
+	for _, line := range strings.Split(list, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && len(fields[0]) == 6 {
+			available[fields[1]] = true
+		}
+	}
+	return available
+}
+
+// probeEncoders picks the video encoder at startup, so a missing one is found
+// before a long compile rather than at the end of it. It returns a status
+// when it had to fall back from libx264.
+func probeEncoders() (string, error) {
+	output, err := execute.Output("ffmpeg", "-hide_banner", "-encoders")
+	if err != nil {
+		return "", fmt.Errorf("failed to list ffmpeg's encoders: %w", err)
+	}
+	encoders = parseEncoders(string(output))
+
+	for _, encoder := range h264Encoders {
+		if encoders[encoder] {
+			videoEncoder = encoder
+			if encoder == "libx264" {
+				return "", nil
+			}
+			return fmt.Sprintf("ffmpeg wasn't built with libx264, encoding with %s instead.", encoder), nil
+		}
+	}
+	if encoders["mpeg4"] {
+		videoEncoder = "mpeg4"
+		return "ffmpeg has no H.264 encoder, encoding MPEG-4 Part 2 instead, which some players and sites won't take.", nil
+	}
+	return "", fmt.Errorf("ffmpeg has no H.264 or MPEG-4 encoder to compile with")
+}
+
+// hasEncoder reports whether ffmpeg can encode with any of the names,
+// assuming it can when it hasn't been probed.
+func hasEncoder(names ...string) bool {
+	if encoders == nil {
+		return true
+	}
+	for _, name := range names {
+		if encoders[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// twoPassEncoder is the encoder for --max-size, libx264 when there is one.
+func twoPassEncoder() string {
+	for _, encoder := range twoPassEncoders {
+		if hasEncoder(encoder) {
+			return encoder
+		}
+	}
+	return ""
+}
+
+// draftEncoderArgs encodes as fast as the encoder goes, since drafts are only
+// watched through once for review.
+func draftEncoderArgs() []string {
+	args := videoEncoderArgs(false)
+	if videoEncoder == "libx264" {
+		return append(args, "-preset", "ultrafast", "-crf", "32")
+	}
+	return append(args, "-b:v", "1M")
+}
+
+// videoEncoderArgs picks the encoder for an output, with libx264's tuning for
+// mostly still pictures when asked for and it's available.
+func videoEncoderArgs(stillImage bool) []string {
+	args := []string{"-c:v", videoEncoder}
+	if stillImage && videoEncoder == "libx264" {
+		args = append(args, "-tune", "stillimage")
+	}
+	return args
+}
+
+// compileEncoderArgs is the video encoder for a full quality compile, with
+// the codec and quality a profile picks when it's an encoder that takes one.
+func compileEncoderArgs(opts compileOptions) []string {
+	args := videoEncoderArgs(false)
+	if opts.VideoCodec != "" {
+		args = []string{"-c:v", opts.VideoCodec}
+	}
+	if opts.CRF > 0 && slices.Contains(crfEncoders, args[1]) {
+		args = append(args, "-crf", strconv.Itoa(opts.CRF))
+	}
+	return args
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zalando/go-keyring"
+)
+
+// remedy is what the error screen can do about a failure with one key.
+type remedy int
+
+const (
+	noRemedy remedy = iota
+	// retryRemedy runs what failed again, for failures that pass
+	retryRemedy
+	// keyRemedy asks for a new API key and transcribes again with it
+	keyRemedy
+	// chunkRemedy transcribes again in smaller chunks
+	chunkRemedy
+)
+
+// failure is an error sorted into what usually causes it, for the error
+// screen to say what to try.
+type failure struct {
+	title  string
+	detail string
+	hint   string
+	remedy remedy
+	// log is the tool log, when what a tool printed says more
+	log string
+}
+
+// ffmpegMissing matches what ffmpeg says when it was built without a filter,
+// encoder, or option something asked for.
+var ffmpegMissing = []struct {
+	pattern *regexp.Regexp
+	title   string
+}{
+	{regexp.MustCompile(`No such filter: '([^']+)'`), "This ffmpeg doesn't have the %s filter"},
+	{regexp.MustCompile(`Unknown encoder '([^']+)'`), "This ffmpeg doesn't have the %s encoder"},
+	{regexp.MustCompile(`Unrecognized option '([^']+)'`), "This ffmpeg doesn't know the %s option"},
+}
+
+// classifyFailure sorts an error from the editor into the common failures,
+// falling back to a retry when it's none of them.
+func classifyFailure(err error, transcriber Transcriber) failure {
+	f := failure{detail: err.Error()}
+
+	var apiErr *apiError
+	var execErr *exec.Error
+	var exitErr *exec.ExitError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.status == 401 || apiErr.status == 403):
+		f.title = tr("The transcription service turned down the API key")
+		f.hint = tr("Check that the key is right and still active, or enter a new one.")
+		f.remedy = keyRemedy
+		if apiKeyCommand != "" {
+			f.hint = tr("Check the key api_key_cmd prints, it's read again on the next run.")
+			f.remedy = noRemedy
+		}
+
+	case errors.As(err, &apiErr) && apiErr.status == 413:
+		f.title = tr("The audio is too big for the transcription service")
+		f.hint = tr("Transcribe with --local, or a provider that takes bigger files.")
+		if _, ok := transcriber.(openAITranscriber); ok && uploadLimit > smallestUploadLimit {
+			f.hint = tr("It can be sent in smaller chunks, which are joined back into one transcript.")
+			f.remedy = chunkRemedy
+		}
+
+	case errors.As(err, &apiErr) && apiErr.status == 429 && strings.Contains(apiErr.body, "insufficient_quota"):
+		f.title = tr("The transcription account is out of credit")
+		f.hint = tr("Add credit to the account, or transcribe with --local.")
+
+	case errors.As(err, &apiErr) && apiErr.status == 429:
+		f.title = tr("The transcription service is limiting requests")
+		f.hint = tr("Wait a moment, then try again.")
+		f.remedy = retryRemedy
+
+	case errors.As(err, &apiErr) && apiErr.status >= 500:
+		f.title = tr("The transcription service had a problem")
+		f.hint = tr("It's usually brief, try again in a moment.")
+		f.remedy = retryRemedy
+
+	case errors.As(err, &netErr):
+		f.title = tr("Could not reach the transcription service")
+		f.hint = tr("Check the internet connection, then try again.")
+		if apiBase != openAIBase {
+			f.hint = trf("Check that %s is running, then try again.", transcriptionServer())
+		}
+		f.remedy = retryRemedy
+
+	case errors.As(err, &execErr) && errors.Is(err, exec.ErrNotFound):
+		f.title = trf("%s isn't installed", execErr.Name)
+		f.hint = trf("Install %s, or put it on the PATH, then run tsplice again.", execErr.Name)
+
+	case errors.Is(err, syscall.ENOSPC):
+		f.title = tr("The disk is full")
+		f.hint = tr("Free up some space, then try again.")
+		f.remedy = retryRemedy
+
+	case strings.Contains(err.Error(), "offline mode is on"):
+		f.title = tr("Offline mode kept this on the machine")
+		f.hint = tr("Transcribe with --local or --stt-command, or run without --offline.")
+
+	case errors.As(err, &exitErr):
+		f.log, _ = toolLogTail()
+		if title, ok := missingFromFFmpeg(); ok {
+			f.title = title
+			f.hint = tr("Upgrade to a recent full build of ffmpeg, like the ones linked from ffmpeg.org/download.html.")
+			break
+		}
+		f.title = tr("A tool tsplice ran failed")
+		f.hint = tr("What it printed is in the log, try again once it's sorted out.")
+		f.remedy = retryRemedy
+
+	default:
+		f.title = tr("Something went wrong")
+		f.remedy = retryRemedy
+	}
+	return f
+}
+
+// missingFromFFmpeg looks through the end of the tool log for ffmpeg saying
+// it was built without something.
+func missingFromFFmpeg() (string, bool) {
+	_, tail := toolLogTail()
+	best, title := -1, ""
+	for _, missing := range ffmpegMissing {
+		for _, match := range missing.pattern.FindAllStringSubmatchIndex(tail, -1) {
+			// The last one logged is the tool that just failed
+			if match[0] > best {
+				best, title = match[0], trf(missing.title, tail[match[2]:match[3]])
+			}
+		}
+	}
+	return title, best >= 0
+}
+
+// fail shows the error screen for an error, with what can be done about it
+// from where the editor was.
+func (m model) fail(err error) model {
+	f := classifyFailure(err, m.transcriber)
+	switch f.remedy {
+	case keyRemedy, chunkRemedy:
+		if m.retryAudio == "" || transcribesLocally() {
+			f.remedy = noRemedy
+		}
+	case retryRemedy:
+		if m.retryAudio == "" && m.retryCompile == nil {
+			f.remedy = noRemedy
+		}
+	}
+	m.failure = &f
+	m.loading = false
+	m.progress = nil
+	return m
+}
+
+// updateFailure takes the error screen's keys: the remedy's, esc back to the
+// editor when there's a transcript to go back to, and q to quit.
+func (m model) updateFailure(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.enteringKey {
+		switch msg.String() {
+		case "esc":
+			m.enteringKey = false
+			return m, nil
+		case "enter":
+			m.enteringKey = false
+			key := strings.TrimSpace(m.keyInput.Value())
+			if key == "" {
+				return m, nil
+			}
+			if err := saveTranscriptionKey(key); err != nil {
+				m.failure.hint = trf("Could not save the key: %s", err.Error())
+				return m, nil
+			}
+			return m.retryFailed()
+		}
+		var cmd tea.Cmd
+		m.keyInput, cmd = m.keyInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "r":
+		if m.failure.remedy != noRemedy {
+			return m.retryFailed()
+		}
+	case "k":
+		if m.failure.remedy == keyRemedy {
+			input := textinput.New()
+			input.Prompt = ""
+			input.Placeholder = tr("API key")
+			input.EchoMode = textinput.EchoPassword
+			input.Focus()
+			m.keyInput = input
+			m.enteringKey = true
+		}
+	case "c":
+		if m.failure.remedy == chunkRemedy {
+			lowerUploadLimit()
+			return m.retryFailed()
+		}
+	case "esc":
+		if len(m.transcriptItems) > 0 {
+			m.failure = nil
+			m.retryAudio, m.retryCompile = "", nil
+		}
+	}
+	return m, nil
+}
+
+// retryFailed runs again what the error screen came up for, the
+// transcription or the compile.
+func (m model) retryFailed() (tea.Model, tea.Cmd) {
+	m.failure = nil
+	if m.retryCompile != nil {
+		return m.startCompile(*m.retryCompile)
+	}
+	m.loading = true
+	m.loadingMsg = transcribingMessage(m.transcriber)
+	return m, tea.Batch(
+		m.spinner.Tick,
+		transcribeAudioCmd(m.transcriber, m.retryAudio, m.vttFile, len(m.previousItems) == 0),
+	)
+}
+
+// saveTranscriptionKey sets the key entered on the error screen for the
+// provider that turned down the last one. OpenAI's is saved to the keyring
+// like it is when first asked for, the rest only last the session.
+func saveTranscriptionKey(key string) error {
+	switch {
+	case apiBase != openAIBase:
+		return os.Setenv("TSPLICE_API_KEY", key)
+	case transcribesWithOpenAI():
+		if err := keyring.Set("tsplice", getSystemUser(), key); err != nil {
+			return err
+		}
+		return os.Setenv("OPENAI_API_KEY", key)
+	}
+	return os.Setenv(providerKeys[transcriptionProvider], key)
+}
+
+func (m model) failureView() string {
+	f := m.failure
+	width := helpWidth
+	if listWidth := m.list.Width(); listWidth > 0 {
+		width = max(40, min(width, listWidth-4))
+	}
+	wrap := lipgloss.NewStyle().Width(width)
+
+	var b strings.Builder
+	b.WriteString("  " + ErrorStyle.Render("✗ "+f.title) + "\n\n")
+	b.WriteString(lipgloss.NewStyle().PaddingLeft(4).Render(wrap.Render(DimTextStyle.Render(f.detail))) + "\n\n")
+	if f.hint != "" {
+		b.WriteString(lipgloss.NewStyle().PaddingLeft(4).Render(wrap.Render(TextStyle.Render(f.hint))) + "\n")
+	}
+	if f.log != "" {
+		b.WriteString("    " + DimTextStyle.Render(trf("Log: %s", f.log)) + "\n")
+	}
+	if m.enteringKey {
+		b.WriteString("\n    " + m.keyInput.View() + "\n")
+		b.WriteString("\n" + DimTextStyle.Render("  "+tr("enter save and transcribe again • esc cancel")) + "\n")
+		return b.String()
+	}
+
+	var keys []string
+	switch f.remedy {
+	case keyRemedy:
+		keys = append(keys, tr("k enter a new key"), tr("r try again"))
+	case chunkRemedy:
+		keys = append(keys, tr("c send in smaller chunks"), tr("r try again"))
+	case retryRemedy:
+		keys = append(keys, tr("r try again"))
+	}
+	if len(m.transcriptItems) > 0 {
+		keys = append(keys, tr("esc back to the editor"))
+	}
+	keys = append(keys, tr("q quit"))
+	b.WriteString("\n" + DimTextStyle.Render("  "+strings.Join(keys, " • ")) + "\n")
+	return b.String()
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Executor runs the external tools the pipeline depends on, so they can be
+// swapped out for a fake when exercising the pipeline without them.
+type Executor interface {
+	Run(name string, args ...string) error
+	Output(name string, args ...string) ([]byte, error)
+}
+
+// Transcriber turns an audio file into VTT content and the details that VTT
+// can't hold.
+type Transcriber interface {
+	Transcribe(audioFile string) (string, transcriptDetails, error)
+	// WithLanguage returns a copy that transcribes in the given language,
+	// where "auto" leaves it up to detection.
+	WithLanguage(language string) Transcriber
+}
+
+// execute is used for every call to ffmpeg, ffprobe, and yt-dlp. Previews with
+// mpv are interactive and always run directly.
+var execute Executor = systemExecutor{}
+
+// systemExecutor runs the real tools, checking first that ffmpeg won't write
+// over what it reads.
+type systemExecutor struct{}
+
+func (systemExecutor) Run(name string, args ...string) error {
+	if name == "ffmpeg" {
+		if err := checkFFmpegArgs(args); err != nil {
+			return err
+		}
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = toolLog(name, args)
+	return runChild(cmd)
+}
+
+func (systemExecutor) Output(name string, args ...string) ([]byte, error) {
+	if name == "ffmpeg" {
+		if err := checkFFmpegArgs(args); err != nil {
+			return nil, err
+		}
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = toolLog(name, args)
+	return outputChild(cmd)
+}
+
+// sttCommand is the local transcriber set with --stt-command or
+// stt_command in the config, used everywhere instead of OpenAI.
+var sttCommand string
+
+// commandTranscriber runs a local program with the audio file appended as
+// its last argument, reading the VTT it prints.
+type commandTranscriber struct {
+	args []string
+}
+
+func (t commandTranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	defer recordTiming("local transcription", time.Now())
+
+	cmd := exec.Command(t.args[0], append(t.args[1:], audioFile)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := outputChild(cmd)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("%s failed: %w: %s", t.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), transcriptDetails{}, nil
+}
+
+// WithLanguage keeps the command as is, its language is part of its arguments.
+func (t commandTranscriber) WithLanguage(language string) Transcriber {
+	return t
+}
+
+// transcribingMessage is shown while the transcriber works, saying where
+// the audio is going.
+func transcribingMessage(t Transcriber) string {
+	switch t := t.(type) {
+	case commandTranscriber:
+		return trf("Transcribing locally with %s...", t.args[0])
+	case whisperTranscriber:
+		return trf("Transcribing locally with %s...", "whisper.cpp")
+	case azureTranscriber:
+		return trf("Transcribing with %s...", "Azure Speech")
+	case deepgramTranscriber:
+		return trf("Transcribing with %s...", "Deepgram")
+	case assemblyAITranscriber:
+		return trf("Transcribing with %s...", "AssemblyAI")
+	}
+	if apiBase != openAIBase {
+		return trf("Transcribing with %s...", transcriptionServer())
+	}
+	return trf("Transcribing with %s...", "OpenAI Whisper")
+}
+
+// newTranscriber is the transcriber for --provider, in a language and with a
+// prompt where the provider takes them.
+func newTranscriber(language, prompt string) Transcriber {
+	switch transcriptionProvider {
+	case "whisper":
+		return whisperTranscriber{language: language, prompt: prompt}
+	case "command":
+		return commandTranscriber{args: strings.Fields(sttCommand)}
+	case "azure":
+		return azureTranscriber{language: language}
+	case "deepgram":
+		return deepgramTranscriber{language: language, prompt: prompt}
+	case "assemblyai":
+		return assemblyAITranscriber{language: language, prompt: prompt}
+	}
+	return openAITranscriber{language: language, prompt: prompt}
+}
+
+// defaultLanguage and defaultPrompt are --lang and --prompt, or lang and
+// prompt in the config, which subcommands transcribe with unless they're
+// given their own.
+var (
+	defaultLanguage = "auto"
+	defaultPrompt   string
+)
+
+// defaultTranscriber is the transcriber for --provider, in the default
+// language, which is detected unless one was set.
+func defaultTranscriber() Transcriber {
+	return newTranscriber(defaultLanguage, defaultPrompt)
+}
+
+// setProvider settles which provider transcribes, where --local and
+// --stt-command pick whisper.cpp and the command unless a provider was set.
+func setProvider(provider string) error {
+	switch {
+	case provider == "" && localWhisper:
+		provider = "whisper"
+	case provider == "" && sttCommand != "":
+		provider = "command"
+	case provider == "":
+		provider = "openai"
+	}
+	if !slices.Contains(transcriptionProviders, provider) {
+		return fmt.Errorf("--provider must be one of %s", strings.Join(transcriptionProviders, ", "))
+	}
+	if localWhisper && provider != "whisper" {
+		return fmt.Errorf("--local transcribes with whisper.cpp, it can't be used with --provider=%s", provider)
+	}
+	if provider == "command" && strings.TrimSpace(sttCommand) == "" {
+		return fmt.Errorf("--provider=command needs --stt-command or stt_command in %s", configPath())
+	}
+	transcriptionProvider = provider
+	localWhisper = provider == "whisper"
+	return nil
+}
+
+// transcribesLocally is whether transcribing stays on this machine.
+func transcribesLocally() bool {
+	return transcriptionProvider == "whisper" || transcriptionProvider == "command"
+}
+
+// transcribesWithOpenAI is whether transcribing needs the OpenAI API key, the
+// only provider's key that's asked for when it's missing. Servers set with
+// --api-base read theirs from TSPLICE_API_KEY instead.
+func transcribesWithOpenAI() bool {
+	return (transcriptionProvider == "" || transcriptionProvider == "openai") && apiBase == openAIBase
+}
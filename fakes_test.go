@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fakeExecutor records every command instead of running it. Run leaves an
+// empty file at ffmpeg's output, its last argument, so renderTo has a
+// partial file to move into place. Output returns whatever was set for the
+// command name, or an error if nothing was.
+type fakeExecutor struct {
+	calls   [][]string
+	outputs map[string][]byte
+	err     error
+}
+
+func (f *fakeExecutor) Run(name string, args ...string) error {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if f.err != nil {
+		return f.err
+	}
+	if name == "ffmpeg" && len(args) > 0 {
+		return writeFakeOutput(args[len(args)-1])
+	}
+	return nil
+}
+
+// writeFakeOutput creates the file ffmpeg would have written, unless the
+// output goes nowhere, like a first pass's.
+func writeFakeOutput(output string) error {
+	if output == "-" || output == os.DevNull || strings.HasPrefix(output, "pipe:") {
+		return nil
+	}
+	return os.WriteFile(output, nil, 0644)
+}
+
+func (f *fakeExecutor) Output(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if f.err != nil {
+		return nil, f.err
+	}
+	out, ok := f.outputs[name]
+	if !ok {
+		return nil, fmt.Errorf("no output set for %s", name)
+	}
+	return out, nil
+}
+
+// ran reports whether a recorded call to name had an argument containing s.
+func (f *fakeExecutor) ran(name string, s string) bool {
+	for _, call := range f.calls {
+		if call[0] != name {
+			continue
+		}
+		for _, arg := range call[1:] {
+			if strings.Contains(arg, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fakeTranscriber returns a fixed transcript for any audio.
+type fakeTranscriber struct {
+	vttContent string
+	details    transcriptDetails
+	err        error
+}
+
+func (f fakeTranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	return f.vttContent, f.details, f.err
+}
+
+func (f fakeTranscriber) WithLanguage(language string) Transcriber {
+	f.details.Language = language
+	return f
+}
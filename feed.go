@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type feedImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type feedDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Image feedImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Items []episode `xml:"item"`
+	} `xml:"channel"`
+}
+
+type episode struct {
+	Title     string    `xml:"title"`
+	PubDate   string    `xml:"pubDate"`
+	Duration  string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Image     feedImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	Enclosure struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+func fetchFeed(feedURL string) (feedDocument, error) {
+	var doc feedDocument
+
+	resp, err := http.Get(feedURL)
+	if err != nil {
+		return doc, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return doc, fmt.Errorf("failed to fetch feed: status %d", resp.StatusCode)
+	}
+
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return doc, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	return doc, nil
+}
+
+func downloadFile(fileURL string, outputFile string) error {
+	resp, err := http.Get(fileURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: status %d", fileURL, resp.StatusCode)
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	return nil
+}
+
+// enclosureExt is the extension of the episode's file, from its URL.
+func enclosureExt(enclosureURL string) string {
+	if u, err := url.Parse(enclosureURL); err == nil {
+		return strings.ToLower(path.Ext(u.Path))
+	}
+	return ""
+}
+
+// episodeVideo downloads an episode and, since the rest of tsplice works on
+// video, turns audio-only episodes into one with the artwork as a still.
+func episodeVideo(e episode, artwork string) (string, error) {
+	basename := tagSlug(e.Title)
+	if basename == "" {
+		basename = "episode"
+	}
+
+	ext := enclosureExt(e.Enclosure.URL)
+	if slices.Contains(validExtensions, ext) {
+		videoFile := basename + ext
+		return videoFile, downloadFile(e.Enclosure.URL, videoFile)
+	}
+
+	audioFile := basename + ext
+	if err := downloadFile(e.Enclosure.URL, audioFile); err != nil {
+		return "", err
+	}
+	defer os.Remove(audioFile)
+
+	args := []string{"-y"}
+	if artwork != "" {
+		imageFile := basename + "_artwork" + enclosureExt(artwork)
+		if err := downloadFile(artwork, imageFile); err == nil {
+			defer os.Remove(imageFile)
+			args = append(args, "-loop", "1", "-framerate", "1", "-i", imageFile)
+		} else {
+			artwork = ""
+		}
+	}
+	if artwork == "" {
+		args = append(args, "-f", "lavfi", "-i", "color=c=black:s=1280x720:r=1")
+	}
+
+	videoFile := basename + ".mp4"
+	args = append(args, "-i", audioFile,
+		"-map", "0:v", "-map", "1:a",
+		"-vf", "scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,format=yuv420p",
+	)
+	args = append(args, videoEncoderArgs(true)...)
+	args = append(args, "-c:a", "aac", "-shortest")
+	err := renderTo(videoFile, func(partial string) error {
+		if err := execute.Run("ffmpeg", append(args, partial)...); err != nil {
+			return fmt.Errorf("failed to convert episode to video: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return videoFile, nil
+}
+
+// feedFlags are the options of tsplice feed.
+type feedFlags struct {
+	limit  int
+	number int
+}
+
+func (f *feedFlags) define(fs *flag.FlagSet) {
+	fs.IntVar(&f.limit, "limit", 10, "How many recent episodes to list")
+	fs.IntVar(&f.number, "episode", 0, "Episode to open from the list, instead of asking")
+}
+
+func runFeed(args []string) error {
+	fs := newCommandFlagSet("feed")
+	var flags feedFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || !isURL(positional[0]) {
+		return fmt.Errorf("usage: tsplice feed [options] <rss-url>")
+	}
+
+	doc, err := fetchFeed(positional[0])
+	if err != nil {
+		return err
+	}
+
+	var episodes []episode
+	for _, e := range doc.Channel.Items {
+		if e.Enclosure.URL != "" {
+			episodes = append(episodes, e)
+		}
+	}
+	episodes = episodes[:min(flags.limit, len(episodes))]
+	if len(episodes) == 0 {
+		return fmt.Errorf("no episodes with media found in the feed")
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(doc.Channel.Title))
+	for index, e := range episodes {
+		details := e.PubDate
+		if e.Duration != "" {
+			details += ", " + e.Duration
+		}
+		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(fmt.Sprintf("%2d. %s", index+1, e.Title)) + DimTextStyle.Render("  "+details))
+	}
+
+	choice := flags.number
+	if choice == 0 {
+		fmt.Print(BulletStyle.Render("├") + TextStyle.Render("Episode to open: "))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		choice, _ = strconv.Atoi(strings.TrimSpace(answer))
+	}
+	if choice < 1 || choice > len(episodes) {
+		return fmt.Errorf("pick an episode between 1 and %d", len(episodes))
+	}
+	chosen := episodes[choice-1]
+
+	artwork := chosen.Image.Href
+	if artwork == "" {
+		artwork = doc.Channel.Image.Href
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Downloading "+chosen.Title+"..."))
+	videoFile, err := episodeVideo(chosen, artwork)
+	if err != nil {
+		return err
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Saved episode to "+videoFile))
+
+	return openInEditor(videoFile)
+}
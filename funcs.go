@@ -1,24 +1,31 @@
 package main
 
 import (
-	"bytes"
+	"bufio"
+	"errors"
 	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
 )
 
-func extractAudioCmd(inputFile string, gate bool) tea.Cmd {
+func extractAudioCmd(inputFile string, gate bool, channel string, progress *renderProgress) tea.Cmd {
 	return func() tea.Msg {
-		audioFile, err := extractAudio(inputFile, gate)
+		audioFile, err := extractAudio(inputFile, gate, channel, progress)
 		if err != nil {
 			return errorMsg{err: err}
 		}
@@ -26,157 +33,184 @@ func extractAudioCmd(inputFile string, gate bool) tea.Cmd {
 	}
 }
 
-func transcribeAudioCmd(audioFile string) tea.Cmd {
+func transcribeAudioCmd(transcriber Transcriber, audioFile string, vttFile string, save bool) tea.Cmd {
 	return func() tea.Msg {
-		vttContent, err := transcribeWithOpenAI(audioFile)
+		vttContent, details, err := transcriber.Transcribe(audioFile)
 		if err != nil {
 			return errorMsg{err: err}
 		}
 
+		parsing := time.Now()
 		transcriptItems, err := parseVTT(vttContent)
 		if err != nil {
 			return errorMsg{err: err}
 		}
+		transcriptItems = applyDetails(transcriptItems, details)
+		recordTiming("parse", parsing)
 
-		basename := strings.TrimSuffix(filepath.Base(audioFile), filepath.Ext(audioFile))
-		vttFile := basename + ".vtt"
-		if err := os.WriteFile(vttFile, []byte(vttContent), 0644); err != nil {
-			return errorMsg{err: err}
+		if save {
+			if err := writeProjectFile(vttFile, []byte(vttContent)); err != nil {
+				return errorMsg{err: err}
+			}
+			if err := saveDetails(vttFile, details); err != nil {
+				return errorMsg{err: err}
+			}
 		}
 
 		os.Remove(audioFile)
 
-		return transcriptionDoneMsg{vttContent: vttContent, transcriptItems: transcriptItems}
+		return transcriptionDoneMsg{vttContent: vttContent, transcriptItems: transcriptItems, details: details}
 	}
 }
 
-func extractAudio(inputFile string, gate bool) (string, error) {
+func extractAudio(inputFile string, gate bool, channel string, progress *renderProgress) (string, error) {
+	defer recordTiming("extract audio", time.Now())
+
 	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
-	audioFile := basename + ".mp3"
+	audioFile := intermediatePath(basename + ".mp3")
 
 	args := []string{"-y", "-i", inputFile}
 
-	if gate {
-		args = append(args, "-af", "silenceremove=stop_periods=-1:stop_duration=10:stop_threshold=-50dB")
-	}
-
-	args = append(args, audioFile)
-	cmd := exec.Command("ffmpeg", args...)
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to extract audio: %w", err)
-	}
-
-	return audioFile, nil
-}
-
-func transcribeWithOpenAI(audioFile string) (string, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+	// Automatic picks happen per segment, which needs the transcript first
+	var filters []string
+	if pan, ok := channelPan[channel]; ok {
+		filters = append(filters, pan)
 	}
-
-	file, err := os.Open(audioFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to open audio file: %w", err)
-	}
-	defer file.Close()
-
-	var b bytes.Buffer
-	writer := multipart.NewWriter(&b)
-
-	part, err := writer.CreateFormFile("file", filepath.Base(audioFile))
-	if err != nil {
-		return "", fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	if _, err := io.Copy(part, file); err != nil {
-		return "", fmt.Errorf("failed to copy file: %w", err)
-	}
-
-	writer.WriteField("model", "whisper-1")
-	writer.WriteField("response_format", "vtt")
-
-	if err := writer.Close(); err != nil {
-		return "", fmt.Errorf("failed to close writer: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/audio/transcriptions", &b)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	if gate {
+		filters = append(filters, "silenceremove=stop_periods=-1:stop_duration=10:stop_threshold=-50dB")
 	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+	if len(filters) > 0 {
+		args = append(args, "-af", strings.Join(filters, ","))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	if progress != nil {
+		if duration, err := probeDuration(inputFile); err == nil {
+			progress.expect(duration)
+		}
 	}
-
-	body, err := io.ReadAll(resp.Body)
+	// Audio that was only partly extracted would be transcribed as if it
+	// were all of it, so it's left under another name until it's done
+	err := renderTo(audioFile, func(partial string) error {
+		if err := runFFmpeg(progress, append(args, partial)...); err != nil {
+			return fmt.Errorf("failed to extract audio: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", err
 	}
 
-	return string(body), nil
+	return audioFile, nil
 }
 
 func parseVTT(vttContent string) ([]TranscriptItem, error) {
 	lines := strings.Split(vttContent, "\n")
 	var transcriptItems []TranscriptItem
 
-	timeStampRegex := regexp.MustCompile(`^(\d{2}:\d{2}:\d{2}\.\d{3}) --> (\d{2}:\d{2}:\d{2}\.\d{3})`)
-	var currentStartTime, currentEndTime string
+	timeStampRegex := regexp.MustCompile(`^((?:\d{2}:)?\d{2}:\d{2}\.\d{3}) --> ((?:\d{2}:)?\d{2}:\d{2}\.\d{3})`)
+	tagRegex := regexp.MustCompile(`<[^>]+>`)
+	voiceRegex := regexp.MustCompile(`^<v(?:\.[^ >]+)? ([^>]+)>`)
+	var current *TranscriptItem
+
+	flush := func() {
+		if current != nil && current.Text != "" {
+			transcriptItems = append(transcriptItems, *current)
+		}
+		current = nil
+	}
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
 		if matches := timeStampRegex.FindStringSubmatch(line); matches != nil {
-			currentStartTime = matches[1]
-			currentEndTime = matches[2]
+			flush()
+			current = &TranscriptItem{
+				StartTime: normalizeTimestamp(matches[1]),
+				EndTime:   normalizeTimestamp(matches[2]),
+			}
 			continue
 		}
 
 		if strings.HasPrefix(line, "WEBVTT") || line == "" {
+			flush()
 			continue
 		}
 
-		if line != "" && currentStartTime != "" {
-			transcriptItems = append(transcriptItems, TranscriptItem{
-				StartTime: currentStartTime,
-				EndTime:   currentEndTime,
-				Text:      line,
-			})
-			currentStartTime = ""
-			currentEndTime = ""
+		// Cues can span multiple lines, so keep joining text until a blank line
+		if current != nil {
+			if matches := voiceRegex.FindStringSubmatch(line); matches != nil && current.Speaker == "" {
+				current.Speaker = strings.TrimSpace(matches[1])
+			}
+			text := strings.TrimSpace(tagRegex.ReplaceAllString(line, ""))
+			if current.Text != "" && text != "" {
+				current.Text += " "
+			}
+			current.Text += text
 		}
 	}
+	flush()
 
 	return transcriptItems, nil
 }
 
+// normalizeTimestamp pads MM:SS.mmm timestamps out to HH:MM:SS.mmm.
+func normalizeTimestamp(timestamp string) string {
+	if strings.Count(timestamp, ":") == 1 {
+		return "00:" + timestamp
+	}
+	return timestamp
+}
+
+// videoPreview is the player window playing a preview. Only one is open at
+// a time, so previewing line after line doesn't stack up windows.
+var videoPreview struct {
+	mu  sync.Mutex
+	cmd *exec.Cmd
+}
+
+// previewVideo plays a span in the preview player, closing the preview before
+// it, and returns once the window is closed.
 func previewVideo(inputFile, startTime, endTime string) {
-	cmd := exec.Command("mpv", "--start="+startTime, "--end="+endTime, inputFile)
-	cmd.Run()
+	cmd := previewCommand(inputFile, startTime, endTime)
+
+	videoPreview.mu.Lock()
+	if videoPreview.cmd != nil {
+		videoPreview.cmd.Process.Kill()
+	}
+	if err := startChild(cmd); err != nil {
+		videoPreview.cmd = nil
+		videoPreview.mu.Unlock()
+		return
+	}
+	videoPreview.cmd = cmd
+	videoPreview.mu.Unlock()
+
+	waitChild(cmd)
+	videoPreview.mu.Lock()
+	if videoPreview.cmd == cmd {
+		videoPreview.cmd = nil
+	}
+	videoPreview.mu.Unlock()
+}
+
+// closeVideoPreview closes the player window from previewVideo if one is open.
+func closeVideoPreview() {
+	videoPreview.mu.Lock()
+	defer videoPreview.mu.Unlock()
+	if videoPreview.cmd != nil {
+		videoPreview.cmd.Process.Kill()
+		videoPreview.cmd = nil
+	}
 }
 
 func getEndTime(items []list.Item, currentIndex int) string {
 	if currentIndex+1 < len(items) {
 		if nextItem, ok := items[currentIndex+1].(item); ok {
-			return strings.Split(nextItem.timestamp, " - ")[0]
+			return formatTimestamp(nextItem.start)
 		}
 	}
 	if currentItem, ok := items[currentIndex].(item); ok {
-		startTime := strings.Split(currentItem.timestamp, " - ")[0]
-		return addSecondsToTimestamp(startTime, 10)
+		return addSecondsToTimestamp(formatTimestamp(currentItem.start), 10)
 	}
 	return "00:00:10.000"
 }
@@ -203,54 +237,126 @@ func addSecondsToTimestamp(timestamp string, seconds int) string {
 	return fmt.Sprintf("%s:%s:%02d.%s", parts[0], parts[1], newSec, secParts[1])
 }
 
-func compileVideoCmd(inputFile string, items []list.Item) tea.Cmd {
+func compileVideoCmd(inputFile string, items []list.Item, opts compileOptions) tea.Cmd {
 	return func() tea.Msg {
-		outputFile, err := compileVideoSegments(inputFile, items)
+		segments := selectedSegments(items)
+		analyzing := time.Now()
+		if opts.TrimSilence {
+			segments = trimSegments(inputFile, segments, opts.SilenceThreshold)
+		}
+		if opts.RemoveBreaths {
+			opts.Breaths = detectBreaths(inputFile, segments)
+		}
+		if opts.SpeakerChannel == "auto" {
+			opts.LeftChannel, opts.RightChannel = pickChannels(inputFile, segments)
+		}
+		if opts.TrimSilence || opts.RemoveBreaths || opts.SpeakerChannel == "auto" {
+			recordTiming("analyze audio", analyzing)
+		}
+		if opts.LowerThird != nil {
+			opts.LowerThirds = lowerThirdsFor(items, segments, *opts.LowerThird)
+		}
+		if opts.Captions != nil {
+			opts.BurnedCaptions = captionsFor(items, opts.Redactions)
+		}
+		if err := preflightCompile(inputFile, segments, opts); err != nil {
+			return errorMsg{err: err}
+		}
+
+		if opts.Split {
+			return compileClips(inputFile, segments, opts)
+		}
+
+		outputFile, err := compileSegments(inputFile, segments, opts)
 		if err != nil {
 			return errorMsg{err: err}
 		}
-		return videoCompilationDoneMsg{outputFile: outputFile}
+
+		var warnings []string
+		if opts.Verify {
+			warnings, err = verifyOutput(outputFile, expectedDuration(segments, opts))
+			if err != nil {
+				warnings = []string{err.Error()}
+			}
+		}
+		return videoCompilationDoneMsg{outputFile: outputFile, warnings: warnings}
 	}
 }
 
-func compileVideoSegments(inputFile string, items []list.Item) (string, error) {
-	// Collect selected segments
-	var segments []struct {
-		start, end float64
+func segmentFromItem(i item) segment {
+	return segment{start: i.start, end: i.end}
+}
+
+func selectedSegments(items []list.Item) []segment {
+	var segments []segment
+
+	for _, listItem := range items {
+		if i, ok := listItem.(item); ok && i.selected {
+			segments = append(segments, segmentFromItem(i))
+		}
 	}
 
+	return segments
+}
+
+func hasSelection(items []list.Item) bool {
 	for _, listItem := range items {
 		if i, ok := listItem.(item); ok && i.selected {
-			timestamps := strings.Split(i.timestamp, " - ")
-			if len(timestamps) == 2 {
-				// Convert MM:SS.XX back to HH:MM:SS.mmm format for ffmpeg
-				start, err := parseTimeToSeconds(timestamps[0])
-				if err != nil {
-					return "", fmt.Errorf("could not parse start time '%s': %w", timestamps[0], err)
-				}
-
-				end, err := parseTimeToSeconds(timestamps[1])
-				if err != nil {
-					return "", fmt.Errorf("could not parse end time '%s': %w", timestamps[1], err)
-				}
-
-				segments = append(segments, struct {
-					start, end float64
-				}{start: start, end: end})
-			}
+			return true
 		}
 	}
+	return false
+}
+
+// compiledBasename is what outputs are named after, the meeting when there is
+// one and otherwise the source, followed by the speaker of a speaker's reel.
+func compiledBasename(inputFile string, opts compileOptions) string {
+	basename := tagSlug(opts.Title)
+	if basename == "" {
+		basename = strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	}
+	if slug := tagSlug(opts.Speaker); slug != "" {
+		basename += "_" + slug
+	}
+	return basename
+}
+
+// outputDir is where compiled videos are saved, set with --output-dir or
+// output_dir in the config. They're saved next to their source without one.
+var outputDir string
+
+// compiledDir is the folder a video's compiles are saved to.
+func compiledDir(inputFile string) string {
+	if outputDir == "" {
+		return filepath.Dir(inputFile)
+	}
+	return outputDir
+}
+
+func compileSegments(inputFile string, segments []segment, opts compileOptions) (string, error) {
+	defer recordTiming("compile", time.Now())
 
 	if len(segments) == 0 {
 		return "", fmt.Errorf("no segments selected")
 	}
+	if err := checkStreamCopy(opts); err != nil {
+		return "", err
+	}
 
 	// Generate output filename
-	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
-	outputFile := fmt.Sprintf("%s_compiled.mp4", basename)
-
-	// Use the same directory as input file
-	outputFile = filepath.Join(filepath.Dir(inputFile), outputFile)
+	suffix := "compiled"
+	if opts.Draft {
+		suffix = "draft"
+	} else if opts.Suffix != "" {
+		suffix = opts.Suffix
+	}
+	if opts.Clip > 0 {
+		suffix = fmt.Sprintf("clip_%02d", opts.Clip)
+		if opts.Draft {
+			suffix += "_draft"
+		}
+	}
+	outputFile := filepath.Join(compiledDir(inputFile), fmt.Sprintf("%s_%s.mp4", compiledBasename(inputFile, opts), suffix))
 
 	// Build ffmpeg filter_complex command for multiple segments
 	var filterParts []string
@@ -261,25 +367,269 @@ func compileVideoSegments(inputFile string, items []list.Item) (string, error) {
 
 	selectFilter := strings.Join(filterParts, "+")
 
-	cmd := exec.Command(
-		"ffmpeg",
-		"-y",
-		"-i",
-		inputFile,
-		"-vf",
-		fmt.Sprintf("select='%s',setpts=N/FRAME_RATE/TB", selectFilter),
-		"-af",
-		fmt.Sprintf("aselect='%s',asetpts=N/SR/TB", selectFilter),
-		outputFile,
-	)
+	// Outputs are still named and placed after the original
+	source := inputFile
+	if opts.LocalCopy != "" {
+		source = opts.LocalCopy
+	}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to compile video segments: %w", err)
+	args := []string{"-y", "-i", source}
+	// Renumbering frames only keeps sync when they're evenly spaced, so
+	// variable frame rate sources are resampled first
+	frameRate := ""
+	if opts.ConstantFrameRate != "" {
+		frameRate = fmt.Sprintf("fps=%s,", opts.ConstantFrameRate)
+	}
+	// Lower thirds and captions are drawn on the source timeline, before
+	// it's cut
+	overlays := ""
+	if opts.LowerThird != nil {
+		overlays = drawLowerThirds(opts.LowerThirds, *opts.LowerThird, 0)
+	}
+	if opts.Captions != nil {
+		overlays += drawCaptions(opts.BurnedCaptions, *opts.Captions, 0)
+	}
+	filters := []string{fmt.Sprintf("[0:v:0]%s%sselect='%s',setpts=N/FRAME_RATE/TB[v]", frameRate, overlays, selectFilter)}
+	maps := []string{"-map", "[v]"}
+	var outputArgs []string
+	switch {
+	case opts.Draft:
+		outputArgs = draftEncoderArgs()
+	case opts.MaxSize == 0:
+		outputArgs = compileEncoderArgs(opts)
+	}
+	if opts.AudioCodec != "" {
+		outputArgs = append(outputArgs, "-c:a", opts.AudioCodec)
+	}
+
+	hasClips := opts.Intro != "" || opts.Outro != ""
+	var probe probeResult
+	if opts.KeepStreams || hasClips {
+		var err error
+		if probe, err = probeStreams(source); err != nil {
+			return "", err
+		}
+	}
+
+	// Subtitles and chapters start after the intro
+	var introDuration float64
+	if opts.Intro != "" {
+		var err error
+		if introDuration, err = probeDuration(opts.Intro); err != nil {
+			return "", fmt.Errorf("could not read intro: %w", err)
+		}
+	}
+
+	// Only the first audio track is kept unless every stream was asked for
+	audioStreams := 1
+	if opts.KeepStreams {
+		inputArgs, streamArgs, cleanup, err := keepStreamsArgs(source, probe, segments, introDuration)
+		defer cleanup()
+		if err != nil {
+			return "", err
+		}
+		args = append(args, inputArgs...)
+		outputArgs = append(outputArgs, streamArgs...)
+		audioStreams = countStreams(probe, "audio")
+	}
+
+	edits := audioEditsFor(opts, segments)
+	for index := range audioStreams {
+		filters = append(filters, audioFilters(index, selectFilter, edits)...)
+		maps = append(maps, "-map", fmt.Sprintf("[a%d]", index))
+	}
+
+	var duration float64
+	for _, segment := range segments {
+		duration += segment.end - segment.start
+	}
+
+	if hasClips {
+		inputs := 0
+		for _, arg := range args {
+			if arg == "-i" {
+				inputs++
+			}
+		}
+
+		clipArgs, clipFilters, clipMaps, added, err := attachClips(probe, inputs, audioStreams, opts)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, clipArgs...)
+		filters = append(filters, clipFilters...)
+		maps = clipMaps
+		duration += added
+	}
+
+	// Drafts are scaled down last, so the clips and selection still match
+	if opts.Draft {
+		filters = append(filters, fmt.Sprintf("%sscale=-2:%d[draft]", maps[1], draftHeight))
+		maps[1] = "[draft]"
+	}
+
+	args = append(args, "-filter_complex", strings.Join(filters, ";"))
+	args = append(args, maps...)
+	args = append(args, outputArgs...)
+	args = append(args, audioOutputArgs(opts)...)
+
+	created := time.Now()
+	metadata := outputMetadataArgs(inputFile, segments, created, opts)
+	args = append(args, metadata...)
+
+	err := renderTo(outputFile, func(partial string) error {
+		var err error
+		switch {
+		case opts.StreamCopy:
+			// Segments are cut, then all of them copied into the output
+			opts.Progress.expect(2 * duration)
+			args, err = compileStreamCopy(source, segments, opts, metadata, partial)
+		case canCacheSegments(opts):
+			// Segments are encoded, then all of them copied into the output
+			opts.Progress.expect(2 * duration)
+			args, err = compileCachedSegments(source, segments, opts, metadata, partial)
+		case opts.MaxSize > 0 && !opts.Draft:
+			// The size limit is left for the final render, the draft is small anyway
+			var videoBitrate int
+			if videoBitrate, err = videoBitrateFor(opts.MaxSize, duration, audioStreams); err == nil {
+				opts.Progress.expect(2 * duration)
+				args, err = twoPassEncode(args, partial, videoBitrate, opts.Progress)
+			}
+		default:
+			opts.Progress.expect(duration)
+			args = append(args, partial)
+			if err = runFFmpeg(opts.Progress, args...); err != nil {
+				err = fmt.Errorf("failed to compile video segments: %w", err)
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	// The manifest records the command as if it wrote the output itself
+	args[len(args)-1] = outputFile
+
+	outputFiles := []string{outputFile}
+	if opts.XMPSidecar {
+		if err := writeXMPSidecar(outputFile, inputFile, segments, created, opts); err != nil {
+			return "", fmt.Errorf("failed to write XMP sidecar: %w", err)
+		}
+		outputFiles = append(outputFiles, strings.TrimSuffix(outputFile, filepath.Ext(outputFile))+".xmp")
+	}
+
+	if opts.Stems {
+		stems, err := exportStems(source, outputFile, selectFilter, edits, audioOutputArgs(opts))
+		if err != nil {
+			return "", err
+		}
+		outputFiles = append(outputFiles, stems...)
+	}
+
+	// A draft's manifest is the cut list --final renders again
+	if opts.Manifest || opts.Draft {
+		if err := writeManifest(inputFile, outputFiles, segments, opts, args, created); err != nil {
+			return "", fmt.Errorf("failed to write manifest: %w", err)
+		}
 	}
 
 	return outputFile, nil
 }
 
+// audioEdits are enable expressions on the compiled timeline for spans of
+// audio that get changed rather than just trimmed.
+type audioEdits struct {
+	bleep     string
+	breath    string
+	boost     string
+	boostGain float64
+	// loudness is the integrated loudness the first track is normalized to
+	loudness float64
+	// channel picks one side of the first track for the whole compile, while
+	// leftChannel and rightChannel pick one for each span
+	channel      string
+	leftChannel  string
+	rightChannel string
+}
+
+func audioEditsFor(opts compileOptions, segments []segment) audioEdits {
+	return audioEdits{
+		bleep:        rangeExpression(opts.Bleeps, segments),
+		breath:       rangeExpression(opts.Breaths, segments),
+		boost:        rangeExpression(opts.Boosts, segments),
+		boostGain:    opts.BoostGain,
+		loudness:     opts.Loudness,
+		channel:      opts.SpeakerChannel,
+		leftChannel:  rangeExpression(opts.LeftChannel, segments),
+		rightChannel: rangeExpression(opts.RightChannel, segments),
+	}
+}
+
+// audioFilters trims one audio track to the selection, labelled [a<index>].
+func audioFilters(index int, selectFilter string, edits audioEdits) []string {
+	chain := fmt.Sprintf("[0:a:%d]aselect='%s',asetpts=N/SR/TB", index, selectFilter)
+
+	// Only the first track holds the speakers, others are usually music or commentary
+	var filters []string
+	if index == 0 {
+		if pan, ok := channelPan[edits.channel]; ok {
+			chain += "," + pan
+		} else if edits.leftChannel != "" || edits.rightChannel != "" {
+			filters, chain = channelFilters(chain, index, edits)
+		}
+	}
+
+	if edits.boost != "" {
+		chain += fmt.Sprintf(",volume=volume=%.1fdB:enable='%s'", edits.boostGain, edits.boost)
+	}
+	if edits.breath != "" {
+		chain += fmt.Sprintf(",volume=volume=%.2f:enable='%s'", breathVolume, edits.breath)
+	}
+	// loudnorm resamples to 192kHz as it works, which is brought back down
+	if index == 0 && edits.loudness != 0 {
+		chain += fmt.Sprintf(",loudnorm=I=%g:TP=-1.5:LRA=11,aresample=48000", edits.loudness)
+	}
+	if edits.bleep == "" {
+		return append(filters, fmt.Sprintf("%s[a%d]", chain, index))
+	}
+
+	// Mute each bleeped word and mix a tone in over the gap
+	return append(filters,
+		fmt.Sprintf("%s,volume=volume=0:enable='%s'[a%dmuted]", chain, edits.bleep, index),
+		fmt.Sprintf("sine=frequency=1000,volume=volume=0:enable='not(%s)'[a%dtone]", edits.bleep, index),
+		fmt.Sprintf("[a%dmuted][a%dtone]amix=inputs=2:duration=first:normalize=0[a%d]", index, index, index),
+	)
+}
+
+// audioOutputArgs sets the channel layout and sample rate of the output audio,
+// leaving whatever the source uses when neither was asked for.
+func audioOutputArgs(opts compileOptions) []string {
+	var args []string
+	switch opts.Channels {
+	case "mono":
+		args = append(args, "-ac", "1")
+	case "stereo":
+		// ffmpeg's default matrix folds center and surround channels into L/R
+		args = append(args, "-ac", "2")
+	}
+	if opts.SampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(opts.SampleRate))
+	}
+	return args
+}
+
+// rangeExpression builds an ffmpeg enable expression covering every range,
+// mapped onto the compiled timeline.
+func rangeExpression(ranges []timeRange, segments []segment) string {
+	var parts []string
+	for _, r := range ranges {
+		for _, piece := range remapRange(r.Start, r.End, segments) {
+			parts = append(parts, fmt.Sprintf("between(t,%.3f,%.3f)", piece.start, piece.end))
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
 func parseTimeToSeconds(timeStr string) (float64, error) {
 	var hours, minutes int
 	var seconds float64
@@ -321,3 +671,281 @@ func getSystemUser() string {
 
 	return username
 }
+
+func formatVTT(transcriptItems []TranscriptItem) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, transcriptItem := range transcriptItems {
+		text := transcriptItem.Text
+		if transcriptItem.Speaker != "" {
+			text = "<v " + transcriptItem.Speaker + ">" + text
+		}
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", transcriptItem.StartTime, transcriptItem.EndTime, text)
+	}
+	return b.String()
+}
+
+func validateInputFile(inputFile string) error {
+	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		return fmt.Errorf("file '%s' does not exist", inputFile)
+	}
+
+	// Validate the input file is a video file
+	fileExt := strings.ToLower(filepath.Ext(inputFile))
+	if !slices.Contains(validExtensions, fileExt) {
+		return fmt.Errorf("file '%s' is not a valid video file", inputFile)
+	}
+
+	// Anything opened for editing is an original from here on
+	protectSource(inputFile)
+	setWorkspaceInput(inputFile)
+	return nil
+}
+
+// apiKeyCommand is api_key_cmd from the config, such as a password manager's
+// CLI, printing the API key. Keys it prints are only ever kept in memory.
+var apiKeyCommand string
+
+// storedAPIKey reads the API key from api_key_cmd if it's set, otherwise the
+// one saved on a previous run, if there is one.
+func storedAPIKey() (string, error) {
+	if apiKeyCommand != "" {
+		return commandAPIKey(apiKeyCommand)
+	}
+
+	apiKey, err := keyring.Get("tsplice", getSystemUser())
+	if err != nil && !strings.Contains(err.Error(), "secret not found") {
+		return "", fmt.Errorf("could not read API key: %w", err)
+	}
+	return apiKey, nil
+}
+
+// commandAPIKey runs api_key_cmd through the shell and reads the key it prints.
+func commandAPIKey(command string) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	cmd := exec.Command(shell, flag, command)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("api_key_cmd failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	apiKey := strings.TrimSpace(string(out))
+	if apiKey == "" {
+		return "", fmt.Errorf("api_key_cmd didn't print an API key")
+	}
+	return apiKey, nil
+}
+
+func setupAPIKey() error {
+	// Nothing will be sent with it, so don't ask for one
+	if offline {
+		return nil
+	}
+
+	username := getSystemUser()
+
+	apiKey, err := storedAPIKey()
+	if err != nil {
+		return err
+	}
+
+	if apiKey != "" {
+		os.Setenv("OPENAI_API_KEY", apiKey)
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("API key set for this session."))
+	}
+
+	if os.Getenv("OPENAI_API_KEY") == "" {
+		fmt.Print(BulletStyle.Render("├") + TextStyle.Render("OPENAI_API_KEY not found, enter one: "))
+
+		byteApiKey, err := term.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return fmt.Errorf("could not read API key: %w", err)
+		}
+
+		fmt.Println()
+		apiKey := strings.TrimSpace(string(byteApiKey))
+
+		if apiKey == "" {
+			return fmt.Errorf("an OpenAI API key is required to proceed")
+		}
+
+		if err := keyring.Set("tsplice", username, apiKey); err != nil {
+			return fmt.Errorf("could not save API key: %w", err)
+		}
+
+		os.Setenv("OPENAI_API_KEY", apiKey)
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("API key set for this session."))
+	}
+
+	return nil
+}
+
+func extractAudioSample(inputFile string, seconds int) (string, error) {
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	audioFile := intermediatePath(basename + "_sample.mp3")
+
+	if err := execute.Run("ffmpeg", "-y", "-i", inputFile, "-t", fmt.Sprintf("%d", seconds), "-vn", audioFile); err != nil {
+		return "", fmt.Errorf("failed to extract audio sample: %w", err)
+	}
+
+	return audioFile, nil
+}
+
+func probeDuration(file string) (float64, error) {
+	out, err := execute.Output("ffprobe", "-v", "error", "-show_entries", "format=duration", "-of", "csv=p=0", file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe duration: %w", err)
+	}
+
+	var duration float64
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(out)), "%f", &duration); err != nil {
+		return 0, fmt.Errorf("could not parse duration '%s': %w", strings.TrimSpace(string(out)), err)
+	}
+
+	return duration, nil
+}
+
+// findSubtitleStream returns the index of the first text-based subtitle stream
+// in the input, or -1 if there isn't one.
+func findSubtitleStream(inputFile string) (int, string) {
+	out, err := execute.Output("ffprobe", "-v", "error", "-select_streams", "s", "-show_entries", "stream=codec_name:stream_tags=language", "-of", "csv=p=0", inputFile)
+	if err != nil {
+		return -1, ""
+	}
+
+	// Image-based subtitles can't be converted to text
+	bitmapCodecs := []string{"dvd_subtitle", "hdmv_pgs_subtitle", "dvb_subtitle", "xsub"}
+
+	for index, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ",")
+		if fields[0] == "" || slices.Contains(bitmapCodecs, fields[0]) {
+			continue
+		}
+		language := ""
+		if len(fields) > 1 {
+			language = fields[1]
+		}
+		return index, language
+	}
+
+	return -1, ""
+}
+
+func extractSubtitles(inputFile string, stream int, vttFile string) error {
+	if err := execute.Run("ffmpeg", "-y", "-i", inputFile, "-map", fmt.Sprintf("0:s:%d", stream), "-f", "webvtt", vttFile); err != nil {
+		return fmt.Errorf("failed to extract subtitles: %w", err)
+	}
+	return nil
+}
+
+func confirm(question string) bool {
+	fmt.Print(BulletStyle.Render("├") + TextStyle.Render(question+" [Y/n] "))
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "" || answer == "y" || answer == "yes"
+}
+
+// formatTimestamp converts seconds into the HH:MM:SS.mmm format used by VTT.
+func formatTimestamp(seconds float64) string {
+	millis := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", millis/3600000, millis/60000%60, millis/1000%60, millis%1000)
+}
+
+func toListItems(transcriptItems []TranscriptItem) []list.Item {
+	// Convert transcript items to list items
+	items := make([]list.Item, len(transcriptItems))
+	for i, transcriptItem := range transcriptItems {
+		// parseVTT only keeps timestamps it could read
+		start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+		end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+		items[i] = item{
+			title:      transcriptItem.Text,
+			start:      start,
+			end:        end,
+			selected:   false,
+			speaker:    transcriptItem.Speaker,
+			confidence: transcriptItem.Confidence,
+		}
+	}
+	return items
+}
+
+// vttPath is where a video's transcript is saved, next to the video so two
+// videos with the same name in different folders each get their own.
+func vttPath(inputFile string) string {
+	return strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + ".vtt"
+}
+
+// loadTranscript reads a transcript along with its details, so speakers have
+// the names they were given.
+func loadTranscript(vttFile string) ([]TranscriptItem, error) {
+	vttBytes, err := readProjectFile(vttFile)
+	if err != nil {
+		return nil, err
+	}
+	transcriptItems, err := parseVTT(string(vttBytes))
+	if err != nil {
+		return nil, err
+	}
+	details, _ := loadDetails(vttFile)
+	return applyDetails(transcriptItems, details), nil
+}
+
+// transcriptFor loads the transcript of a video, transcribing and saving it
+// first if there isn't one yet. One that's there but can't be read, like
+// when the passphrase is mistyped, is never transcribed over.
+func transcriptFor(inputFile string, transcriber Transcriber) ([]TranscriptItem, error) {
+	vttFile := vttPath(inputFile)
+	if transcriptItems, err := loadTranscript(vttFile); !errors.Is(err, fs.ErrNotExist) {
+		return transcriptItems, err
+	}
+
+	// Another instance transcribing it too would pay for it twice and write
+	// over this one, and when it's done there's nothing left to transcribe
+	release, pid, err := lockVideo(vttFile)
+	if err != nil {
+		return nil, fmt.Errorf("this video is already being transcribed by %s, wait for it to finish", lockHolder(pid))
+	}
+	defer release()
+	if transcriptItems, err := loadTranscript(vttFile); !errors.Is(err, fs.ErrNotExist) {
+		return transcriptItems, err
+	}
+
+	if err := preflightExtract(inputFile, vttFile); err != nil {
+		return nil, err
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Extracting audio with ffmpeg..."))
+	audioFile, err := extractAudio(inputFile, false, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(audioFile)
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Transcribing audio..."))
+	vttContent, details, err := transcriber.Transcribe(audioFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriptItems, err := parseVTT(vttContent)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeProjectFile(vttFile, []byte(vttContent)); err != nil {
+		return nil, err
+	}
+	if err := saveDetails(vttFile, details); err != nil {
+		return nil, err
+	}
+
+	return applyDetails(transcriptItems, details), nil
+}
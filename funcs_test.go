@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// compileArgs compiles segments of a video in a temp folder against a fake
+// executor, returning the output file and the arguments ffmpeg was run with.
+func compileArgs(t *testing.T, segments []segment, opts compileOptions) (string, []string) {
+	t.Helper()
+	fake := useFakeExecutor(t)
+	inputFile := filepath.Join(t.TempDir(), "talk.mp4")
+	// A draft's manifest reads the source
+	if err := os.WriteFile(inputFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outputFile, err := compileSegments(inputFile, segments, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.calls) != 1 || fake.calls[0][0] != "ffmpeg" {
+		t.Fatalf("want one call to ffmpeg, got %v", fake.calls)
+	}
+	return outputFile, fake.calls[0][1:]
+}
+
+// argAfter is the argument that follows flag, like the value of -i.
+func argAfter(args []string, flag string) string {
+	index := slices.Index(args, flag)
+	if index < 0 || index+1 >= len(args) {
+		return ""
+	}
+	return args[index+1]
+}
+
+func TestCompileSegmentsSelectsEachSegment(t *testing.T) {
+	outputFile, args := compileArgs(t, []segment{{0, 2}, {5, 10}}, compileOptions{})
+
+	if filepath.Base(outputFile) != "talk_compiled.mp4" {
+		t.Errorf("output is %s, want talk_compiled.mp4", outputFile)
+	}
+	if input := argAfter(args, "-i"); filepath.Base(input) != "talk.mp4" {
+		t.Errorf("input is %s, want talk.mp4", input)
+	}
+
+	filters := argAfter(args, "-filter_complex")
+	want := "between(t,0.000,2.000)+between(t,5.000,10.000)"
+	if !strings.Contains(filters, "[0:v:0]select='"+want+"',setpts=N/FRAME_RATE/TB[v]") {
+		t.Errorf("video filter doesn't select %s: %s", want, filters)
+	}
+	if !strings.Contains(filters, "[0:a:0]aselect='"+want+"',asetpts=N/SR/TB") {
+		t.Errorf("audio filter doesn't select %s: %s", want, filters)
+	}
+
+	var maps []string
+	for index, arg := range args {
+		if arg == "-map" {
+			maps = append(maps, args[index+1])
+		}
+	}
+	if !slices.Equal(maps, []string{"[v]", "[a0]"}) {
+		t.Errorf("maps are %v, want [v] and [a0]", maps)
+	}
+
+	// ffmpeg writes next to the output, which it's moved to once it's done
+	last := args[len(args)-1]
+	if last == outputFile || filepath.Dir(last) != filepath.Dir(outputFile) {
+		t.Errorf("ffmpeg writes to %s, want a partial file next to %s", last, outputFile)
+	}
+}
+
+func TestCompileSegmentsNamesDraftsAndClips(t *testing.T) {
+	tests := []struct {
+		opts compileOptions
+		want string
+	}{
+		{compileOptions{Suffix: "highlights"}, "talk_highlights.mp4"},
+		{compileOptions{Draft: true}, "talk_draft.mp4"},
+		{compileOptions{Clip: 3}, "talk_clip_03.mp4"},
+		{compileOptions{Clip: 3, Draft: true}, "talk_clip_03_draft.mp4"},
+	}
+	for _, test := range tests {
+		outputFile, _ := compileArgs(t, []segment{{0, 2}}, test.opts)
+		if filepath.Base(outputFile) != test.want {
+			t.Errorf("output with %+v is %s, want %s", test.opts, filepath.Base(outputFile), test.want)
+		}
+	}
+}
+
+func TestCompileSegmentsScalesDraftsDown(t *testing.T) {
+	_, args := compileArgs(t, []segment{{0, 2}}, compileOptions{Draft: true})
+
+	if filters := argAfter(args, "-filter_complex"); !strings.Contains(filters, "[v]scale=-2:") {
+		t.Errorf("draft isn't scaled down: %s", filters)
+	}
+	if index := slices.Index(args, "-map"); index < 0 || args[index+1] != "[draft]" {
+		t.Errorf("draft maps the full size video: %v", args)
+	}
+}
+
+func TestCompileSegmentsBleepsOnTheCompiledTimeline(t *testing.T) {
+	// The bleep at 6s in the source is 1s into the second segment, 3s in
+	opts := compileOptions{Bleeps: []timeRange{{6, 6.5}}}
+	_, args := compileArgs(t, []segment{{0, 2}, {5, 10}}, opts)
+
+	filters := argAfter(args, "-filter_complex")
+	if !strings.Contains(filters, "volume=volume=0:enable='between(t,3.000,3.500)'") {
+		t.Errorf("bleep isn't muted at 3s: %s", filters)
+	}
+	if !strings.Contains(filters, "amix=inputs=2") {
+		t.Errorf("no tone mixed in over the bleep: %s", filters)
+	}
+}
+
+func TestCompileSegmentsNeedsASegment(t *testing.T) {
+	useFakeExecutor(t)
+	if _, err := compileSegments(filepath.Join(t.TempDir(), "talk.mp4"), nil, compileOptions{}); err == nil {
+		t.Error("compiled with no segments")
+	}
+}
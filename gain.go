@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// defaultQuietGain is the boost, in dB, for segments marked quiet when the
+// config doesn't set one. Enough to even out a low mic without clipping.
+const defaultQuietGain = 6.0
+
+// boostRanges returns the source time ranges of the selected segments that
+// were marked quiet.
+func boostRanges(items []list.Item) []timeRange {
+	var ranges []timeRange
+	for _, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok || !i.selected || !i.quiet {
+			continue
+		}
+		ranges = append(ranges, timeRange{Start: i.start, End: i.end})
+	}
+	return ranges
+}
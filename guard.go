@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// sources are the original videos opened this run. Nothing tsplice runs may
+// write over them, however an output's name was arrived at.
+var sources struct {
+	mu    sync.Mutex
+	files []os.FileInfo
+}
+
+// protectSource marks a video as an original, so outputs that would end up
+// being the same file are refused.
+func protectSource(file string) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return
+	}
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+	sources.files = append(sources.files, info)
+}
+
+// isSource reports whether path is one of the original videos, under its own
+// name or any other, like a relative path or a link.
+func isSource(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+	for _, source := range sources.files {
+		if os.SameFile(info, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFFmpegArgs refuses an ffmpeg command that would write over a file it
+// reads or an original video. Which arguments are outputs can't be told
+// without knowing every option ffmpeg has, so no argument outside of -i may
+// name an existing input or source at all.
+func checkFFmpegArgs(args []string) error {
+	var inputs []os.FileInfo
+	for index, arg := range args {
+		if arg == "-i" && index+1 < len(args) {
+			if info, err := os.Stat(args[index+1]); err == nil {
+				inputs = append(inputs, info)
+			}
+		}
+	}
+
+	for index, arg := range args {
+		if arg == "-" || (index > 0 && args[index-1] == "-i") {
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if isSource(arg) {
+			return fmt.Errorf("refusing to run ffmpeg, it would write over the original video %s", arg)
+		}
+		for _, input := range inputs {
+			if os.SameFile(info, input) {
+				return fmt.Errorf("refusing to run ffmpeg, it would write over its own input %s", arg)
+			}
+		}
+	}
+	return nil
+}
+
+// partialPath is where a render of file goes until it's finished, keeping
+// the extension so ffmpeg still picks the container from it.
+func partialPath(file string) string {
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + ".partial" + ext
+}
+
+// renderTo has render write to a partial file and moves it over outputFile
+// only once render succeeds, so a render that fails or is interrupted never
+// leaves a truncated file under the finished name.
+func renderTo(outputFile string, render func(partial string) error) error {
+	if isSource(outputFile) {
+		return fmt.Errorf("refusing to write over the original video %s", outputFile)
+	}
+	partial := partialPath(outputFile)
+	removeOnExit(partial)
+	if err := render(partial); err != nil {
+		removeTemp(partial)
+		return err
+	}
+	if err := os.Rename(partial, outputFile); err != nil {
+		removeTemp(partial)
+		return err
+	}
+	keepTemp(partial)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpWidth is how wide descriptions are wrapped in the help.
+const helpWidth = 76
+
+// commandFlags is every option a command takes, from the definitions it
+// parses them with.
+func commandFlags(c command) []*flag.Flag {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	if c.flags != nil {
+		c.flags(fs)
+	}
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+	return flags
+}
+
+// flagLines lists options with their usage, lined up after the longest name.
+func flagLines(flags []*flag.Flag) []string {
+	width := 0
+	for _, f := range flags {
+		width = max(width, len(f.Name))
+	}
+	var lines []string
+	for _, f := range flags {
+		name := "--" + f.Name
+		spaces := strings.Repeat(" ", width+4-len(name))
+		lines = append(lines, BulletStyle.Render("├────")+TextStyle.Render(name)+DimTextStyle.Render(spaces+f.Usage))
+	}
+	return lines
+}
+
+// printCommandHelp prints a command's usage, what it does, its options, and
+// examples of it.
+func printCommandHelp(c command, flags []*flag.Flag) {
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Usage: "+c.usage))
+	if c.description != "" {
+		fmt.Println(BulletStyle.Render("│"))
+		for index, line := range strings.Split(lipgloss.NewStyle().Width(helpWidth).Render(c.description), "\n") {
+			bullet := "│"
+			if index == 0 {
+				bullet = "├"
+			}
+			fmt.Println(BulletStyle.Render(bullet) + DimTextStyle.Render(strings.TrimRight(line, " ")))
+		}
+	}
+	fmt.Println(BulletStyle.Render("│"))
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Options:"))
+
+	lines := flagLines(flags)
+	if len(c.examples) > 0 {
+		lines = append(lines, BulletStyle.Render("│"), BulletStyle.Render("├")+TextStyle.Render("Examples:"))
+		for _, example := range c.examples {
+			lines = append(lines, BulletStyle.Render("├────")+TextStyle.Render(example))
+		}
+	}
+	if len(lines) > 0 {
+		lines[len(lines)-1] = strings.Replace(lines[len(lines)-1], "├", "└", 1)
+	}
+	fmt.Println(strings.Join(lines, "\n"))
+}
+
+// editorTopics are what tsplice help takes for the options of opening and
+// compiling a video, which isn't a command of its own.
+var editorTopics = []string{"compile", "edit", "editor"}
+
+func runHelp(args []string) error {
+	fs := newCommandFlagSet("help")
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: tsplice help [command]")
+	}
+	if len(positional) == 0 || slices.Contains(editorTopics, positional[0]) {
+		flag.Usage()
+		return nil
+	}
+
+	c, ok := findCommand(positional[0])
+	if !ok {
+		var names []string
+		for _, cmd := range commands {
+			names = append(names, cmd.name)
+		}
+		return fmt.Errorf("there's no %s command, use one of %s, or compile for the editor's options", positional[0], strings.Join(names, ", "))
+	}
+	printCommandHelp(c, commandFlags(c))
+	return nil
+}
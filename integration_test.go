@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// needMedia skips a test on machines without ffmpeg and ffprobe.
+func needMedia(t *testing.T) {
+	t.Helper()
+	for _, tool := range []string{"ffmpeg", "ffprobe"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("needs %s", tool)
+		}
+	}
+}
+
+// fixture generates the synthetic video and its transcript in a temp folder.
+func fixture(t *testing.T) string {
+	t.Helper()
+	needMedia(t)
+	dir := t.TempDir()
+	if err := generateFixture(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(vttPath(filepath.Join(dir, "fixture.mp4")), []byte(selftestVTT), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestMediaPipeline(t *testing.T) {
+	needMedia(t)
+	dir := t.TempDir()
+	for _, check := range selftestChecks {
+		// Each check works on what the ones before it wrote
+		if !t.Run(check.name, func(t *testing.T) {
+			if err := check.run(dir); err != nil {
+				t.Fatal(err)
+			}
+		}) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestDraftIsScaledDown(t *testing.T) {
+	dir := fixture(t)
+	outputFile, err := selftestCompile(dir, compileOptions{Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	probe, err := probeStreams(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "video" && stream.Height != draftHeight {
+			t.Errorf("draft is %dp, want %dp", stream.Height, draftHeight)
+		}
+	}
+	if err := expectInSync(outputFile, 7); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCachedSegmentsMatchAFullCompile(t *testing.T) {
+	dir := fixture(t)
+	outputFile, err := selftestCompile(dir, compileOptions{CacheSegments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := expectStreams(outputFile, 1, 1); err != nil {
+		t.Error(err)
+	}
+	if err := expectInSync(outputFile, 7); err != nil {
+		t.Error(err)
+	}
+}
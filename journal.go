@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// Pipeline stages recorded in the journal. Finished stages aren't recorded,
+// since the transcript and compiled video are their own record.
+const (
+	stageAudioExtracted = "audio_extracted"
+	stageCompileStarted = "compile_started"
+)
+
+// journal is the last pipeline stage reached for a video, so a run that
+// crashed or was killed can pick up from there instead of starting over.
+type journal struct {
+	Stage     string      `json:"stage"`
+	AudioFile string      `json:"audio_file,omitempty"`
+	Gate      bool        `json:"gate,omitempty"`
+	Channel   string      `json:"channel,omitempty"`
+	Segments  []timeRange `json:"segments,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func journalPath(vttFile string) string {
+	return strings.TrimSuffix(vttFile, ".vtt") + ".journal.json"
+}
+
+// writeJournal is best effort, a missing journal only means less to resume.
+func writeJournal(vttFile string, j journal) {
+	j.UpdatedAt = time.Now()
+	content, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return
+	}
+	writeProjectFile(journalPath(vttFile), content)
+}
+
+func loadJournal(vttFile string) (journal, bool) {
+	var j journal
+	content, err := readProjectFile(journalPath(vttFile))
+	if err != nil {
+		return j, false
+	}
+	if err := json.Unmarshal(content, &j); err != nil {
+		return j, false
+	}
+	return j, true
+}
+
+func clearJournal(vttFile string) {
+	os.Remove(journalPath(vttFile))
+}
+
+// resumableAudio returns the audio a previous run extracted, as long as it's
+// still around and was extracted the same way this run would.
+func resumableAudio(j journal, gate bool, channel string) (string, bool) {
+	if j.Stage != stageAudioExtracted || j.Gate != gate || j.Channel != channel {
+		return "", false
+	}
+	if _, err := os.Stat(j.AudioFile); err != nil {
+		return "", false
+	}
+	return j.AudioFile, true
+}
+
+// restoreSelection selects every line that falls inside a segment of a
+// compile that never finished, returning how many were selected.
+func restoreSelection(items []list.Item, segments []timeRange) ([]list.Item, int) {
+	restored := 0
+	for index, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok {
+			continue
+		}
+		// Padding isn't journaled, so match on the middle of the line
+		middle := (i.start + i.end) / 2
+		for _, r := range segments {
+			if middle >= r.Start && middle <= r.End {
+				i.selected = true
+				items[index] = i
+				restored++
+				break
+			}
+		}
+	}
+	return items, restored
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const karaokeInterval = 50 * time.Millisecond
+
+type karaokeTickMsg time.Time
+
+type audioPreviewDoneMsg struct {
+	process *exec.Cmd
+}
+
+type karaokeState struct {
+	start   float64
+	end     float64
+	started time.Time
+	words   []Word
+	process *exec.Cmd
+}
+
+func karaokeTick() tea.Cmd {
+	return tea.Tick(karaokeInterval, func(t time.Time) tea.Msg {
+		return karaokeTickMsg(t)
+	})
+}
+
+// startAudioPreview plays a span without video and follows along word by word,
+// in place of any preview already playing.
+func (m model) startAudioPreview(startTime, endTime string) (model, tea.Cmd) {
+	m = m.stopAudioPreview()
+	closeVideoPreview()
+
+	start, err := parseTimeToSeconds(startTime)
+	if err != nil {
+		return m, nil
+	}
+	end, err := parseTimeToSeconds(endTime)
+	if err != nil {
+		return m, nil
+	}
+
+	process, err := audioPreviewCommand(m.previewFile(), startTime, endTime)
+	if err != nil {
+		m.statuses = append(m.statuses, trf("Could not start audio preview: %s", err.Error()))
+		return m, nil
+	}
+	if err := startChild(process); err != nil {
+		m.statuses = append(m.statuses, trf("Could not start audio preview: %s", err.Error()))
+		return m, nil
+	}
+
+	m.karaoke = &karaokeState{start: start, end: end, started: time.Now(), words: wordsBetween(m.wordsFor(), start, end), process: process}
+
+	return m, tea.Batch(
+		func() tea.Msg {
+			waitChild(process)
+			return audioPreviewDoneMsg{process: process}
+		},
+		karaokeTick(),
+	)
+}
+
+func (m model) stopAudioPreview() model {
+	if m.karaoke != nil && m.karaoke.process.Process != nil {
+		m.karaoke.process.Process.Kill()
+	}
+	m.karaoke = nil
+	return m
+}
+
+func (m model) karaokeView() string {
+	if m.karaoke == nil {
+		return ""
+	}
+
+	position := m.karaoke.start + time.Since(m.karaoke.started).Seconds()
+
+	var rendered []string
+	for _, word := range m.karaoke.words {
+		switch {
+		case position >= word.Start && position < word.End:
+			rendered = append(rendered, KaraokeCurrentStyle.Render(word.Text))
+		case position >= word.End:
+			rendered = append(rendered, TextStyle.Render(word.Text))
+		default:
+			rendered = append(rendered, DimTextStyle.Render(word.Text))
+		}
+	}
+
+	progress := formatTimestamp(min(position, m.karaoke.end))
+	return "  " + SpinnerStyle.Render("♪ "+progress+" ") + strings.Join(rendered, " ") + "\n"
+}
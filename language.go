@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m model) startLanguagePrompt() model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "auto"
+	input.CharLimit = 8
+	input.SetValue(m.language)
+	input.Focus()
+
+	m.languageInput = input
+	m.choosingLanguage = true
+	return m
+}
+
+// updateLanguage edits the language to re-transcribe in, which is handy when
+// detection guessed wrong for speakers who switch between languages.
+func (m model) updateLanguage(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.choosingLanguage = false
+		return m, nil
+
+	case "enter":
+		m.choosingLanguage = false
+		language := strings.ToLower(strings.TrimSpace(m.languageInput.Value()))
+		if language == "" {
+			language = "auto"
+		}
+
+		// The key is only set up at launch when there's no transcript yet
+		if os.Getenv("OPENAI_API_KEY") == "" {
+			if apiKey, err := storedAPIKey(); err == nil && apiKey != "" {
+				os.Setenv("OPENAI_API_KEY", apiKey)
+			} else {
+				m.statuses = append(m.statuses, tr("No API key saved, run tsplice with --retranscribe instead."))
+				return m, nil
+			}
+		}
+
+		m.language = language
+		m.transcriber = m.transcriber.WithLanguage(language)
+		m.previousItems = m.transcriptItems
+		m.loading = true
+		m.loadingMsg = tr("Extracting audio with ffmpeg...")
+		m.progress = &renderProgress{}
+		return m, tea.Batch(
+			m.spinner.Tick,
+			extractAudioCmd(m.mediaFile(), m.gate, m.compileOptions.SpeakerChannel, m.progress),
+		)
+	}
+
+	var cmd tea.Cmd
+	m.languageInput, cmd = m.languageInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) languageHeader() string {
+	if m.choosingLanguage {
+		return TagStyle.Render("  "+tr("Re-transcribe in language (e.g. en, es, auto): ")) + m.languageInput.View()
+	}
+	if m.details.Language != "" {
+		return DimTextStyle.Render("  " + trf("Language: %s", m.details.Language))
+	}
+	return ""
+}
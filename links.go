@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// deepLink points a video URL at a moment in it, using YouTube's t parameter
+// or a media fragment that most browsers' players understand.
+func deepLink(base string, seconds float64) string {
+	if !isYouTubeURL(base) {
+		base, _, _ = strings.Cut(base, "#")
+		return fmt.Sprintf("%s#t=%d", base, int(seconds))
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	query := u.Query()
+	query.Set("t", fmt.Sprintf("%ds", int(seconds)))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// exportDeepLinks writes the selected lines as a markdown list of links to the
+// moment they're said in the published video, for show notes and posts.
+func exportDeepLinks(inputFile string, items []list.Item, base string, tcOffset float64, redactions []string) (string, error) {
+	if base == "" {
+		return "", fmt.Errorf("set --source-url to the published video to export links")
+	}
+
+	var b strings.Builder
+	for _, transcriptItem := range redactItems(selectedTranscript(items), redactions) {
+		start, err := parseTimeToSeconds(transcriptItem.StartTime)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "- [%s](%s) %s\n", formatDuration(start+tcOffset), deepLink(base, start), transcriptItem.Text)
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("no segments selected")
+	}
+
+	linksFile := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "_links.md"
+	if err := os.WriteFile(linksFile, []byte(b.String()), 0644); err != nil {
+		return "", err
+	}
+
+	return linksFile, nil
+}
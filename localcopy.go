@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localCopyPath is where a source on a network mount is copied to, so it's
+// read over the network once instead of on every extraction and compile.
+func localCopyPath(inputFile string) (string, error) {
+	key, err := cacheKey(inputFile)
+	if err != nil {
+		return "", err
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sources", key+strings.ToLower(filepath.Ext(inputFile))), nil
+}
+
+type progressWriter struct {
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	w.progress(w.written, w.total)
+	return len(p), nil
+}
+
+// copyToLocal copies a source to the local cache, reusing the copy made on a
+// previous run. It's written under another name until it's done, so a copy
+// in the cache is never a partial one.
+func copyToLocal(inputFile string, progress func(written, total int64)) (string, error) {
+	localFile, err := localCopyPath(inputFile)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(localFile); err == nil {
+		return localFile, nil
+	}
+
+	source, err := os.Open(inputFile)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+	info, err := source.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localFile), 0755); err != nil {
+		return "", err
+	}
+	if free, err := freeSpace(filepath.Dir(localFile)); err == nil && free < info.Size() {
+		return "", fmt.Errorf("not enough free space in %s for a %s copy", filepath.Dir(localFile), formatBytes(info.Size()))
+	}
+
+	partial := localFile + ".partial"
+	removeOnExit(partial)
+	defer keepTemp(partial)
+	destination, err := os.Create(partial)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(io.MultiWriter(destination, &progressWriter{total: info.Size(), progress: progress}), source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partial)
+		return "", fmt.Errorf("failed to copy %s: %w", filepath.Base(inputFile), err)
+	}
+	return localFile, os.Rename(partial, localFile)
+}
+
+// mediaFile is what ffmpeg and mpv read the source from, the local copy when
+// there is one.
+func (m model) mediaFile() string {
+	if m.compileOptions.LocalCopy != "" {
+		return m.compileOptions.LocalCopy
+	}
+	return m.inputFile
+}
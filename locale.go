@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// uiLanguage is the language the editor is shown in, from --ui-lang, ui_lang
+// in the config, or the system's locale.
+var uiLanguage = "en"
+
+// translations are the editor's text in every language besides English, by
+// the English text. Anything a language is missing is shown in English.
+var translations = map[string]map[string]string{
+	"de": translationsDE,
+	"es": translationsES,
+	"fr": translationsFR,
+	"pt": translationsPT,
+}
+
+// tr is the editor's text in the language it's shown in.
+func tr(text string) string {
+	if translated, ok := translations[uiLanguage][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// trf is tr for text with fmt verbs in it, which keep their order in every
+// language.
+func trf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// setUILanguage picks the language the editor is shown in, going by the
+// system's locale when none is given.
+func setUILanguage(language string) error {
+	if language == "" {
+		uiLanguage = systemLanguage()
+		return nil
+	}
+	language = strings.ToLower(language)
+	if _, ok := translations[language]; !ok && language != "en" {
+		return fmt.Errorf("the interface isn't translated to %s, use one of en, %s", language, strings.Join(slices.Sorted(maps.Keys(translations)), ", "))
+	}
+	uiLanguage = language
+	return nil
+}
+
+// systemLanguage is the language of the locale in the environment, like pt
+// for pt_BR.UTF-8, or English when it isn't translated.
+func systemLanguage() string {
+	// The first one set wins, the same as for every other program
+	for _, variable := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(variable)
+		if locale == "" {
+			continue
+		}
+		language, _, _ := strings.Cut(strings.ToLower(locale), "_")
+		language, _, _ = strings.Cut(language, ".")
+		if _, ok := translations[language]; ok {
+			return language
+		}
+		break
+	}
+	return "en"
+}
+
+// translateList puts the list's own help and filter prompt in the editor's
+// language.
+func translateList(l *list.Model) {
+	keys := &l.KeyMap
+	for _, binding := range []*key.Binding{
+		&keys.CursorUp, &keys.CursorDown, &keys.PrevPage, &keys.NextPage, &keys.GoToStart, &keys.GoToEnd,
+		&keys.Filter, &keys.ClearFilter, &keys.CancelWhileFiltering, &keys.AcceptWhileFiltering,
+		&keys.ShowFullHelp, &keys.CloseFullHelp, &keys.Quit,
+	} {
+		binding.SetHelp(binding.Help().Key, tr(binding.Help().Desc))
+	}
+	l.FilterInput.Prompt = tr("Filter: ")
+}
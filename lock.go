@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+func lockPath(vttFile string) string {
+	return strings.TrimSuffix(vttFile, ".vtt") + ".lock"
+}
+
+// locks are the videos this instance holds the lock on, so taking one it
+// already has, like transcriptFor does under tsplice serve and batch,
+// shares it instead of being locked out of its own video.
+var locks struct {
+	mu   sync.Mutex
+	held map[string]*heldLock
+}
+
+type heldLock struct {
+	file    *os.File
+	holders int
+}
+
+// lockVideo takes an advisory lock on the video's transcript, so two
+// instances editing the same video don't overwrite each other's transcript
+// and sidecars. When another instance holds it, the returned pid is theirs.
+func lockVideo(vttFile string) (release func(), pid int, err error) {
+	path := lockPath(vttFile)
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+
+	held, ok := locks.held[path]
+	if !ok {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if err := lockFile(file); err != nil {
+			content, _ := os.ReadFile(path)
+			pid, _ = strconv.Atoi(strings.TrimSpace(string(content)))
+			file.Close()
+			return nil, pid, fmt.Errorf("already being edited")
+		}
+
+		file.Truncate(0)
+		fmt.Fprintf(file, "%d\n", os.Getpid())
+
+		held = &heldLock{file: file}
+		if locks.held == nil {
+			locks.held = map[string]*heldLock{}
+		}
+		locks.held[path] = held
+	}
+	held.holders++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			locks.mu.Lock()
+			defer locks.mu.Unlock()
+			if held.holders--; held.holders > 0 {
+				return
+			}
+			// The file is left in place: removing it would let one instance
+			// lock the old file while another makes and locks a new one
+			held.file.Close()
+			delete(locks.held, path)
+		})
+	}, 0, nil
+}
+
+// lockHolder names the instance holding a video's lock for errors.
+func lockHolder(pid int) string {
+	if pid > 0 {
+		return fmt.Sprintf("another tsplice (pid %d)", pid)
+	}
+	return "another tsplice"
+}
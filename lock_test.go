@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// lockedElsewhere is whether another instance would find the video locked.
+func lockedElsewhere(t *testing.T, vttFile string) bool {
+	t.Helper()
+	file, err := os.OpenFile(lockPath(vttFile), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	return lockFile(file) != nil
+}
+
+func TestLockIsSharedWithinAnInstance(t *testing.T) {
+	vttFile := filepath.Join(t.TempDir(), "talk.vtt")
+
+	release, _, err := lockVideo(vttFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := lockVideo(vttFile)
+	if err != nil {
+		t.Fatalf("locked out of its own video: %v", err)
+	}
+
+	again()
+	// Releasing twice only lets go once
+	again()
+	if !lockedElsewhere(t, vttFile) {
+		t.Fatal("lock was let go while still held")
+	}
+
+	release()
+	if lockedElsewhere(t, vttFile) {
+		t.Error("lock is still held after every holder let go")
+	}
+	if _, err := os.Stat(lockPath(vttFile)); err != nil {
+		t.Errorf("lock file was removed: %v", err)
+	}
+}
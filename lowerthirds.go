@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// lowerThirdFade is how long, in seconds, a lower third takes to fade in and
+// out.
+const lowerThirdFade = 0.3
+
+// unnamedSpeaker matches the labels transcribers give speakers, which aren't
+// worth putting on screen until they've been named with N.
+var unnamedSpeaker = regexp.MustCompile(`(?i)^speaker[ _-]?\d+$`)
+
+// lowerThirdConfig is how lower thirds look, from lower_third in the config.
+type lowerThirdConfig struct {
+	// Duration is how long each is on screen, in seconds
+	Duration float64 `toml:"duration" json:"duration"`
+	// Font is a font file, or a family name for fontconfig to find
+	Font       string `toml:"font" json:"font,omitempty"`
+	Color      string `toml:"color" json:"color"`
+	Background string `toml:"background" json:"background"`
+	// Titles is the line shown under each speaker's name, by name
+	Titles map[string]string `toml:"titles" json:"titles,omitempty"`
+}
+
+// lowerThird is a speaker's name shown as they first speak, on the source
+// timeline.
+type lowerThird struct {
+	Name  string  `json:"name"`
+	Title string  `json:"title,omitempty"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// title finds a speaker's title, ignoring case like the caption styles do.
+func (c lowerThirdConfig) title(speaker string) string {
+	if title, ok := c.Titles[speaker]; ok {
+		return title
+	}
+	for name, title := range c.Titles {
+		if strings.EqualFold(name, speaker) {
+			return title
+		}
+	}
+	return ""
+}
+
+// lowerThirdsFor places a lower third at the start of each speaker's first
+// selected segment. Segments are the selected lines in order, after any
+// trimming, so the lower third starts when the speaker does.
+func lowerThirdsFor(items []list.Item, segments []segment, c lowerThirdConfig) []lowerThird {
+	var thirds []lowerThird
+	seen := map[string]bool{}
+	index := 0
+	for _, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok || !i.selected {
+			continue
+		}
+		s := segments[index]
+		index++
+
+		speaker := strings.TrimSpace(i.speaker)
+		if speaker == "" || unnamedSpeaker.MatchString(speaker) || seen[strings.ToLower(speaker)] {
+			continue
+		}
+		seen[strings.ToLower(speaker)] = true
+		thirds = append(thirds, lowerThird{
+			Name:  speaker,
+			Title: c.title(speaker),
+			Start: s.start,
+			End:   min(s.start+c.Duration, s.end),
+		})
+	}
+	return thirds
+}
+
+// drawLowerThirds draws lower thirds over the source before it's cut, with
+// their times moved back by offset for inputs that were seeked into. It
+// ends in a comma, ready to go in front of the select filter.
+func drawLowerThirds(thirds []lowerThird, c lowerThirdConfig, offset float64) string {
+	font := ""
+	if c.Font != "" {
+		if strings.ContainsAny(c.Font, `/\`) {
+			font = "fontfile=" + escapeFilterPath(c.Font) + ":"
+		} else {
+			font = "font=" + escapeFilterText(c.Font) + ":"
+		}
+	}
+	box := fmt.Sprintf("box=1:boxcolor=%s@0.6:boxborderw=16", ffmpegColor(c.Background))
+
+	var b strings.Builder
+	for _, third := range thirds {
+		start, end := third.Start-offset, third.End-offset
+		fade := min(lowerThirdFade, (end-start)/2)
+		timing := fmt.Sprintf("enable='between(t,%.3f,%.3f)':alpha='if(lt(t,%.3f),(t-%.3f)/%.3f,if(gt(t,%.3f),(%.3f-t)/%.3f,1))'",
+			start, end, start+fade, start, fade, end-fade, end, fade)
+
+		fmt.Fprintf(&b, "drawtext=%sexpansion=none:text=%s:fontcolor=%s:fontsize=h/16:x=w/20:y=h*0.72:%s:%s,",
+			font, escapeFilterText(third.Name), ffmpegColor(c.Color), box, timing)
+		if third.Title != "" {
+			fmt.Fprintf(&b, "drawtext=%sexpansion=none:text=%s:fontcolor=%s:fontsize=h/28:x=w/20:y=h*0.72+h/16+28:%s:%s,",
+				font, escapeFilterText(third.Title), ffmpegColor(c.Color), box, timing)
+		}
+	}
+	return b.String()
+}
+
+// escapeFilterText quotes text for use as a filter option in a filter graph,
+// which unescapes it once for the graph and again for the option, so names
+// like O'Brien come through whole.
+func escapeFilterText(text string) string {
+	text = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(text)
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
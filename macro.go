@@ -0,0 +1,45 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateMacro handles recording and replaying key macros. It reports whether
+// the key was consumed.
+func (m model) updateMacro(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "Q":
+		if m.recordingMacro {
+			m.recordingMacro = false
+			m.macro = m.macroKeys
+			m.macroKeys = nil
+			m.statuses = append(m.statuses, tr("Macro recorded, press @ to replay it."))
+		} else {
+			m.recordingMacro = true
+			m.macroKeys = nil
+		}
+		return m, nil, true
+
+	case "@":
+		if m.recordingMacro || len(m.macro) == 0 {
+			return m, nil, true
+		}
+
+		var cmds []tea.Cmd
+		var current tea.Model = m
+		for _, key := range m.macro {
+			var cmd tea.Cmd
+			current, cmd = current.(model).Update(key)
+			cmds = append(cmds, cmd)
+		}
+		return current.(model), tea.Batch(cmds...), true
+
+	case "q", "ctrl+c":
+		return m, nil, false
+	}
+
+	if m.recordingMacro {
+		m.macroKeys = append(m.macroKeys, msg)
+	}
+	return m, nil, false
+}
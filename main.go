@@ -1,6 +1,8 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -8,22 +10,34 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
-	"syscall"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/zalando/go-keyring"
-	"golang.org/x/term"
 )
 
 const VERSION = "1.0.3"
 
-func (i item) FilterValue() string { return i.title }
+var validExtensions = []string{".mp4", ".avi", ".mov", ".mkv", ".m4v"}
+
+func (i item) FilterValue() string { return i.title + " " + formatTags(i.tags) }
+
+func (d itemDelegate) Height() int {
+	if d.zen {
+		return zenLines
+	}
+	return 2
+}
+
+func (d itemDelegate) Spacing() int {
+	if d.zen {
+		return 1
+	}
+	return 0
+}
 
-func (d itemDelegate) Height() int                             { return 2 }
-func (d itemDelegate) Spacing() int                            { return 0 }
 func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
 	i, ok := listItem.(item)
@@ -36,67 +50,605 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		checkbox = "◼"
 	}
 
-	timestampLine := TimestampStyle.Render(i.timestamp)
+	if d.zen {
+		d.renderZen(w, m, index, i, checkbox)
+		return
+	}
+
+	timestampLine := TimestampStyle.Render(formatTimestamp(i.start+d.tcOffset) + " - " + formatTimestamp(i.end+d.tcOffset))
+	if len(i.tags) > 0 {
+		timestampLine += " " + TagStyle.Render(formatTags(i.tags))
+	}
+	if i.quiet {
+		timestampLine += " " + TagStyle.Render("["+tr("boost")+"]")
+	}
+	if i.removal != "" && !i.selected {
+		timestampLine += " " + ErrorStyle.Render("[✂ "+tr(i.removal)+"]")
+	} else if i.removal != "" {
+		timestampLine += " " + DimTextStyle.Render("["+trf("kept, %s", tr(i.removal))+"]")
+	}
 	str := fmt.Sprintf("%s %s", checkbox, i.title)
+	if index == m.Index() && d.editing != "" {
+		str = checkbox + " " + d.editing
+	}
 
 	fn := ItemStyle.Render
+	if style, ok := d.colorRules.match(i); ok {
+		fn = ItemStyle.Inherit(style).Render
+	}
 	if index == m.Index() {
 		fn = func(s ...string) string {
 			return SelectedItemStyle.Render("> " + strings.Join(s, " "))
 		}
+	} else if d.inRange(m, i) {
+		fn = func(s ...string) string {
+			return RangeItemStyle.Render("┃ " + strings.Join(s, " "))
+		}
 	}
 
 	fmt.Fprintf(w, "%s\n%s", timestampLine, fn(str))
 }
 
+func (m model) newItemDelegate() itemDelegate {
+	return itemDelegate{colorRules: m.colorRules, zen: m.zen, tcOffset: m.tcOffset, ranging: m.ranging, rangeFrom: m.rangeFrom, editing: m.editingView()}
+}
+
+// extraHelpKeys lists bindings that only apply with certain options enabled.
+func (m model) extraHelpKeys() []key.Binding {
+	var bindings []key.Binding
+	if len(m.tags) > 0 {
+		bindings = append(bindings,
+			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp(fmt.Sprintf("1-%d", len(m.tags)), tr("tag"))),
+			key.NewBinding(key.WithKeys("T"), key.WithHelp("T", tr("export tags"))),
+		)
+	}
+	if m.rulesFile != "" {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("r"), key.WithHelp("r", tr("apply rules"))))
+	}
+	return bindings
+}
+
+func (m model) newTranscriptList(transcriptItems []TranscriptItem) list.Model {
+	// Create and configure the list
+	l := list.New(m.store.restore(m.autoSelect.apply(toListItems(transcriptItems))), m.newItemDelegate(), 64, 16)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.Filter = tagFilter
+	translateList(&l)
+	l.SetShowHelp(true)
+	l.SetShowPagination(false)
+
+	// Add custom key bindings for help
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return append([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("p"),
+				key.WithHelp("p", tr("preview")),
+			),
+			key.NewBinding(
+				key.WithKeys("P"),
+				key.WithHelp("P", tr("audio preview")),
+			),
+			key.NewBinding(
+				key.WithKeys("c"),
+				key.WithHelp("c", tr("compile")),
+			),
+			key.NewBinding(
+				key.WithKeys("E"),
+				key.WithHelp("E", tr("export edl")),
+			),
+			key.NewBinding(
+				key.WithKeys("C"),
+				key.WithHelp("C", tr("export captions")),
+			),
+			key.NewBinding(
+				key.WithKeys("L"),
+				key.WithHelp("L", tr("export links")),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", tr("re-transcribe")),
+			),
+			key.NewBinding(
+				key.WithKeys("w"),
+				key.WithHelp("w", tr("audiograms")),
+			),
+			key.NewBinding(
+				key.WithKeys("X"),
+				key.WithHelp("X", tr("export clips")),
+			),
+			key.NewBinding(
+				key.WithKeys("z"),
+				key.WithHelp("z", tr("zen")),
+			),
+			key.NewBinding(
+				key.WithKeys("tab"),
+				key.WithHelp("tab", tr("summary")),
+			),
+			key.NewBinding(
+				key.WithKeys("S"),
+				key.WithHelp("S", tr("stats")),
+			),
+			key.NewBinding(
+				key.WithKeys("N"),
+				key.WithHelp("N", tr("name speakers")),
+			),
+			key.NewBinding(
+				key.WithKeys("+", "-"),
+				key.WithHelp("+/-", tr("pad")),
+			),
+			key.NewBinding(
+				key.WithKeys("v"),
+				key.WithHelp("v", tr("boost quiet")),
+			),
+			key.NewBinding(
+				key.WithKeys("b"),
+				key.WithHelp("b", tr("bulk")),
+			),
+			key.NewBinding(
+				key.WithKeys("e"),
+				key.WithHelp("e", tr("edit text")),
+			),
+			key.NewBinding(
+				key.WithKeys("m"),
+				key.WithHelp("m", tr("merge with next")),
+			),
+			key.NewBinding(
+				key.WithKeys("s"),
+				key.WithHelp("s", tr("split")),
+			),
+			key.NewBinding(
+				key.WithKeys("V"),
+				key.WithHelp("V", tr("select range")),
+			),
+			key.NewBinding(
+				key.WithKeys("a", "A", "i"),
+				key.WithHelp("a/A/i", tr("select all/none/invert")),
+			),
+			key.NewBinding(
+				key.WithKeys("F"),
+				key.WithHelp("F", tr("auto-edit")),
+			),
+			key.NewBinding(
+				key.WithKeys("ctrl+z", "ctrl+y"),
+				key.WithHelp("ctrl+z/y", tr("undo/redo")),
+			),
+			key.NewBinding(
+				key.WithKeys("Q", "@"),
+				key.WithHelp("Q/@", tr("record/replay macro")),
+			),
+		}, m.extraHelpKeys()...)
+	}
+
+	return l
+}
+
+func (m model) startCompile(opts compileOptions) (tea.Model, tea.Cmd) {
+	m.retryCompile = &opts
+	m.loading = true
+	m.loadingMsg = tr("Compiling video segments with ffmpeg...")
+	m.progress = &renderProgress{}
+
+	opts = m.withAudioEdits(opts)
+	opts.Progress = m.progress
+	opts.Split = opts.Split || m.clipsPending
+	if opts.Split {
+		m.loadingMsg = tr("Compiling each segment as a clip with ffmpeg...")
+	}
+
+	if segments := selectedSegments(m.list.Items()); len(segments) > 0 {
+		var ranges []timeRange
+		for _, s := range segments {
+			ranges = append(ranges, timeRange{Start: s.start, End: s.end})
+		}
+		if !m.readOnly {
+			writeJournal(m.vttFile, journal{Stage: stageCompileStarted, Segments: ranges})
+		}
+	}
+
+	return m, tea.Batch(
+		m.spinner.Tick,
+		compileVideoCmd(m.inputFile, m.list.Items(), opts),
+	)
+}
+
+// withAudioEdits adds the bleeps and quiet lines' boosts to a compile, and
+// the redactions, which are also taken out of burned in captions.
+func (m model) withAudioEdits(opts compileOptions) compileOptions {
+	opts.Redactions = m.redactions
+	opts.Bleeps = append(bleepRanges(m.wordsFor(), m.bleep), redactionRanges(m.wordsFor(), m.redactions)...)
+	opts.Boosts = boostRanges(m.list.Items())
+	return opts
+}
+
 func (m model) Init() tea.Cmd {
-	if m.loading {
+	var cmds []tea.Cmd
+	if m.makeProxy != "" {
+		cmds = append(cmds, makeProxyCmd(m.mediaFile(), m.makeProxy))
+	}
+
+	if m.loading && m.resumeAudio != "" {
+		audioFile := m.resumeAudio
+		cmds = append(cmds,
+			m.spinner.Tick,
+			func() tea.Msg { return audioExtractedMsg{audioFile: audioFile} },
+		)
+	} else if m.loading {
 		// Start the spinner and begin audio extraction
-		return tea.Batch(
+		cmds = append(cmds,
 			m.spinner.Tick,
-			extractAudioCmd(m.inputFile, m.gate),
+			extractAudioCmd(m.mediaFile(), m.gate, m.compileOptions.SpeakerChannel, m.progress),
 		)
+	} else if m.redactNames {
+		cmds = append(cmds, findRedactionsCmd(m.transcriptItems, m.redactions))
 	}
-	// If not loading, just return nil (no commands to run)
-	return nil
+	if !m.loading {
+		cmds = append(cmds, m.embedCmd())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.failure != nil && msg.String() != "q" && msg.String() != "ctrl+c" {
+			return m.updateFailure(msg)
+		}
+
+		if m.diffing && msg.String() != "q" && msg.String() != "ctrl+c" {
+			return m.updateDiff(msg)
+		}
+
+		if m.naming && msg.String() != "ctrl+c" {
+			return m.updateNaming(msg)
+		}
+
+		if m.autoEditing && msg.String() != "q" && msg.String() != "ctrl+c" {
+			return m.updateAutoEdit(msg)
+		}
+
+		if m.showStats {
+			switch msg.String() {
+			case "S", "esc":
+				m.showStats = false
+			case "x":
+				stats := computeStats(m.list.Items(), m.fillers)
+				files, err := exportStats(m.inputFile, stats, "")
+				if err != nil {
+					m.statuses = append(m.statuses, err.Error())
+				} else {
+					m.statuses = append(m.statuses, trf("Exported analytics to %s", strings.Join(files, tr(" and "))))
+				}
+			}
+			if msg.String() != "q" && msg.String() != "ctrl+c" {
+				return m, nil
+			}
+		}
+
+		if m.choosingLanguage && msg.String() != "ctrl+c" {
+			return m.updateLanguage(msg)
+		}
+
+		if m.editingText && msg.String() != "ctrl+c" {
+			return m.updateEditing(msg)
+		}
+
+		if m.splitting && msg.String() != "ctrl+c" {
+			return m.updateSplit(msg)
+		}
+
+		if m.askTracks > 0 && msg.String() != "q" && msg.String() != "ctrl+c" {
+			opts := m.compileOptions
+			m.askTracks = 0
+			switch msg.String() {
+			case "k":
+				opts.KeepStreams = true
+			case "s":
+				opts.Stems = true
+			case "enter":
+			default:
+				return m, nil
+			}
+			return m.startCompile(opts)
+		}
+
+		// Let the filter input have every key while it's being typed into
+		if !m.loading && m.list.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, cmd
+		}
+
+		if !m.loading && len(m.list.Items()) > 0 {
+			var cmd tea.Cmd
+			var handled bool
+			if m, cmd, handled = m.updateMacro(msg); handled {
+				return m, cmd
+			}
+		}
+
+		if m.bulkPending {
+			return m.updateBulk(msg)
+		}
+
+		if m.ranging && msg.String() != "q" && msg.String() != "ctrl+c" {
+			return m.updateRange(msg)
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m = m.stopAudioPreview()
+			closeVideoPreview()
 			m.quitting = true
 			return m, tea.Quit
 
+		case "ctrl+z":
+			if !m.loading {
+				return m.undo()
+			}
+			return m, nil
+
+		case "ctrl+y":
+			if !m.loading {
+				return m.redo()
+			}
+			return m, nil
+
 		case "enter", " ":
 			if !m.loading && len(m.list.Items()) > 0 {
-				selectedIndex := m.list.Index()
+				selectedIndex := m.list.GlobalIndex()
 				if selectedIndex >= 0 && selectedIndex < len(m.list.Items()) {
 					items := m.list.Items()
 					if i, ok := items[selectedIndex].(item); ok {
+						m = m.pushUndo()
 						i.selected = !i.selected
-						items[selectedIndex] = i
-						m.list.SetItems(items)
+						m = m.saveItem(selectedIndex, i)
+						return m, m.list.SetItem(selectedIndex, i)
+					}
+				}
+			}
+			return m, nil
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			tagIndex := int(msg.String()[0] - '1')
+			if !m.loading && len(m.list.Items()) > 0 && tagIndex < len(m.tags) {
+				selectedIndex := m.list.GlobalIndex()
+				if selectedIndex >= 0 && selectedIndex < len(m.list.Items()) {
+					if i, ok := m.list.Items()[selectedIndex].(item); ok {
+						m = m.pushUndo()
+						i.tags = toggleTag(i.tags, m.tags[tagIndex])
+						m = m.saveItem(selectedIndex, i)
+						return m, m.list.SetItem(selectedIndex, i)
 					}
 				}
 			}
 			return m, nil
 
+		case "e":
+			if m.readOnly {
+				m.statuses = append(m.statuses, tr("Read-only, another tsplice is editing this video."))
+				return m, nil
+			}
+			if !m.loading && len(m.list.Items()) > 0 {
+				m = m.startEditing()
+			}
+			return m, nil
+
+		case "m", "s":
+			if m.readOnly {
+				m.statuses = append(m.statuses, tr("Read-only, another tsplice is editing this video."))
+				return m, nil
+			}
+			if !m.loading && len(m.list.Items()) > 0 {
+				if msg.String() == "m" {
+					return m.mergeNext()
+				}
+				m = m.startSplit()
+			}
+			return m, nil
+
+		case "N":
+			if !m.loading && len(m.list.Items()) > 0 {
+				if m.readOnly {
+					m.statuses = append(m.statuses, tr("Speakers can't be renamed while the video is open read-only."))
+				} else if len(speakersOf(m.list.Items())) == 0 {
+					m.statuses = append(m.statuses, tr("No speaker labels in this transcript to name."))
+				} else {
+					m = m.startNaming()
+				}
+			}
+			return m, nil
+
+		case "S":
+			if !m.loading && len(m.list.Items()) > 0 {
+				m.showStats = true
+			}
+			return m, nil
+
+		case "tab":
+			if !m.loading && len(m.list.Items()) > 0 {
+				m.showSummary = !m.showSummary
+			}
+			return m, nil
+
+		case "z":
+			if !m.loading && len(m.list.Items()) > 0 {
+				m.zen = !m.zen
+				m.list.SetDelegate(m.newItemDelegate())
+			}
+			return m, nil
+
+		case "+", "-":
+			if !m.loading && len(m.list.Items()) > 0 {
+				selectedIndex := m.list.GlobalIndex()
+				if selectedIndex >= 0 && selectedIndex < len(m.list.Items()) {
+					if i, ok := m.list.Items()[selectedIndex].(item); ok {
+						amount := padStep
+						if msg.String() == "-" {
+							amount = -padStep
+						}
+						m = m.pushUndo()
+						return m, m.list.SetItem(selectedIndex, padItem(i, amount))
+					}
+				}
+			}
+			return m, nil
+
+		case "v":
+			if !m.loading && len(m.list.Items()) > 0 {
+				selectedIndex := m.list.GlobalIndex()
+				if selectedIndex >= 0 && selectedIndex < len(m.list.Items()) {
+					if i, ok := m.list.Items()[selectedIndex].(item); ok {
+						m = m.pushUndo()
+						i.quiet = !i.quiet
+						return m, m.list.SetItem(selectedIndex, i)
+					}
+				}
+			}
+			return m, nil
+
+		case "b":
+			if !m.loading && len(m.list.Items()) > 0 {
+				m.bulkPending = true
+			}
+			return m, nil
+
+		case "F":
+			if !m.loading && len(m.list.Items()) > 0 {
+				return m.startAutoEdit()
+			}
+			return m, nil
+
+		case "T":
+			if !m.loading && len(m.list.Items()) > 0 {
+				files, err := exportTagCutLists(m.inputFile, m.list.Items(), m.tcOffset)
+				if err != nil {
+					m.statuses = append(m.statuses, err.Error())
+					return m, nil
+				}
+				m.statuses = append(m.statuses, trf("Exported %d tag cut lists.", len(files)))
+			}
+			return m, nil
+
+		case "E":
+			if !m.loading && len(m.list.Items()) > 0 {
+				edlFile, err := exportEDL(m.inputFile, m.list.Items(), m.tcOffset, m.fps, m.dropFrame)
+				if err != nil {
+					m.statuses = append(m.statuses, err.Error())
+					return m, nil
+				}
+				m.statuses = append(m.statuses, trf("Exported EDL to %s", edlFile))
+			}
+			return m, nil
+
+		case "V":
+			if !m.loading && len(m.list.Items()) > 0 {
+				m = m.startRange()
+			}
+			return m, nil
+
+		case "a", "A", "i":
+			if !m.loading && len(m.list.Items()) > 0 {
+				return m.updateSelectAll(msg)
+			}
+			return m, nil
+
+		case "C":
+			if !m.loading && len(m.list.Items()) > 0 {
+				files, err := exportSelectionSubtitles(m.inputFile, m.list.Items(), m.redactions, m.audiogram.Speakers)
+				if err != nil {
+					m.statuses = append(m.statuses, err.Error())
+					return m, nil
+				}
+				m.statuses = append(m.statuses, trf("Exported selected captions to %s", strings.Join(files, tr(" and "))))
+			}
+			return m, nil
+
+		case "L":
+			if !m.loading && len(m.list.Items()) > 0 {
+				linksFile, err := exportDeepLinks(m.inputFile, m.list.Items(), m.sourceURL, m.tcOffset, m.redactions)
+				if err != nil {
+					m.statuses = append(m.statuses, err.Error())
+					return m, nil
+				}
+				m.statuses = append(m.statuses, trf("Exported links to %s", linksFile))
+			}
+			return m, nil
+
+		case "R":
+			if m.readOnly {
+				m.statuses = append(m.statuses, tr("Read-only, another tsplice is editing this video."))
+				return m, nil
+			}
+			if !m.loading && len(m.transcriptItems) > 0 {
+				return m.startLanguagePrompt(), nil
+			}
+			return m, nil
+
 		case "p":
 			if !m.loading && len(m.list.Items()) > 0 {
-				selectedIndex := m.list.Index()
+				selectedIndex := m.list.GlobalIndex()
 				if selectedIndex >= 0 && selectedIndex < len(m.list.Items()) {
 					items := m.list.Items()
 					if i, ok := items[selectedIndex].(item); ok {
-						startTime := strings.Split(i.timestamp, " - ")[0]
-						go previewVideo(m.inputFile, startTime, getEndTime(items, selectedIndex))
+						m = m.stopAudioPreview()
+						go previewVideo(m.previewFile(), formatTimestamp(i.start), getEndTime(items, selectedIndex))
 					}
 				}
 			}
 			return m, nil
 
-		case "c":
+		case "P":
+			if !m.loading && len(m.list.Items()) > 0 {
+				selectedIndex := m.list.GlobalIndex()
+				if selectedIndex >= 0 && selectedIndex < len(m.list.Items()) {
+					items := m.list.Items()
+					if i, ok := items[selectedIndex].(item); ok {
+						return m.startAudioPreview(formatTimestamp(i.start), getEndTime(items, selectedIndex))
+					}
+				}
+			}
+			return m, nil
+
+		case "w":
+			if !m.loading && len(m.list.Items()) > 0 {
+				if len(selectedSegments(m.list.Items())) == 0 {
+					m.statuses = append(m.statuses, tr("Select the segments to export as audiograms first."))
+					return m, nil
+				}
+				m.loading = true
+				m.loadingMsg = tr("Rendering audiograms with ffmpeg...")
+				opts := m.audiogram
+				opts.Bleeps = redactionRanges(m.wordsFor(), m.redactions)
+				return m, tea.Batch(
+					m.spinner.Tick,
+					exportAudiogramsCmd(m.inputFile, m.list.Items(), redactWords(m.wordsFor(), m.redactions), opts),
+				)
+			}
+			return m, nil
+
+		case "r":
+			if !m.loading && m.rulesFile != "" && len(m.list.Items()) > 0 {
+				// Rules change the list's own lines as they go
+				before := slices.Clone(m.list.Items())
+				items, changed, err := applyRules(m.rulesFile, m.list.Items())
+				if err != nil {
+					m.statuses = append(m.statuses, err.Error())
+					return m, nil
+				}
+				m.statuses = append(m.statuses, trf("Rules applied, %d segments changed.", changed))
+				m = m.pushUndoItems(before)
+				m = m.saveItems(items)
+				return m, m.list.SetItems(items)
+			}
+			return m, nil
+
+		case "c", "X":
+			if m.readOnly {
+				m.statuses = append(m.statuses, tr("Read-only, another tsplice is editing this video."))
+				return m, nil
+			}
 			if !m.loading && len(m.list.Items()) > 0 {
 				// Check if any items are selected
 				items := m.list.Items()
@@ -108,12 +660,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				if hasSelected {
-					m.loading = true
-					m.loadingMsg = "Compiling video segments with ffmpeg..."
-					return m, tea.Batch(
-						m.spinner.Tick,
-						compileVideoCmd(m.inputFile, items),
-					)
+					// X saves each selected segment as a clip of its own
+					m.clipsPending = msg.String() == "X"
+					// Offer to keep every audio track when there's more than one
+					if !m.compileOptions.KeepStreams && !m.compileOptions.Stems {
+						if probe, err := probeStreams(m.inputFile); err == nil && countStreams(probe, "audio") > 1 {
+							m.askTracks = countStreams(probe, "audio")
+							return m, nil
+						}
+					}
+					return m.startCompile(m.compileOptions)
 				}
 			}
 			return m, nil
@@ -127,62 +683,109 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case audioExtractedMsg:
-		m.statuses = append(m.statuses, "Audio extracted from ffmpeg.")
-		m.loadingMsg = "Transcribing with OpenAI Whisper..."
-		return m, transcribeAudioCmd(msg.audioFile)
+		writeJournal(m.vttFile, journal{Stage: stageAudioExtracted, AudioFile: msg.audioFile, Gate: m.gate, Channel: m.compileOptions.SpeakerChannel})
+		m.statuses = append(m.statuses, tr("Audio extracted from ffmpeg."))
+		m.loadingMsg = transcribingMessage(m.transcriber)
+		m.progress = nil
+		m.retryAudio = msg.audioFile
+		return m, transcribeAudioCmd(m.transcriber, msg.audioFile, m.vttFile, len(m.previousItems) == 0)
 
 	case transcriptionDoneMsg:
-		m.statuses = append(m.statuses, "Transcription finished and saved locally.")
+		clearJournal(m.vttFile)
 		m.loading = false
-		m.transcriptItems = msg.transcriptItems
+		m.retryAudio = ""
+		m.details = msg.details
 
-		// Convert transcript items to list items
-		items := make([]list.Item, len(msg.transcriptItems))
-		for i, transcriptItem := range msg.transcriptItems {
-			items[i] = item{
-				title:     transcriptItem.Text,
-				timestamp: transcriptItem.StartTime + " - " + transcriptItem.EndTime,
-				selected:  false,
-			}
+		// When re-transcribing, let the user pick between versions before saving
+		if len(m.previousItems) > 0 {
+			m.statuses = append(m.statuses, tr("Transcription finished."))
+			m.diffing = true
+			m.diffRows = alignTranscripts(m.previousItems, msg.transcriptItems)
+			m.diffCursor = 0
+			return m, nil
 		}
 
-		// Create and configure the list
-		l := list.New(items, itemDelegate{}, 64, 16)
-		l.SetShowTitle(false)
-		l.SetShowStatusBar(false)
-		l.SetFilteringEnabled(true)
-		l.SetShowHelp(true)
-		l.SetShowPagination(false)
+		m.statuses = append(m.statuses, tr("Transcription finished and saved locally."))
+		m.transcriptItems = msg.transcriptItems
 
-		// Add custom key bindings for help
-		l.AdditionalShortHelpKeys = func() []key.Binding {
-			return []key.Binding{
-				key.NewBinding(
-					key.WithKeys("p"),
-					key.WithHelp("p", "preview"),
-				),
-				key.NewBinding(
-					key.WithKeys("c"),
-					key.WithHelp("c", "compile"),
-				),
-			}
+		m = m.syncStore(msg.transcriptItems)
+		m.list = m.newTranscriptList(msg.transcriptItems)
+
+		m, cmd := m.findRedactions(msg.transcriptItems)
+		return m, tea.Batch(cmd, m.embedCmd())
+
+	case proxyReadyMsg:
+		if msg.err != nil {
+			m.statuses = append(m.statuses, trf("Previews will play the source, %s", msg.err.Error()))
+			return m, nil
+		}
+		if m.proxyFile == "" {
+			m.statuses = append(m.statuses, tr("Preview proxy ready, previews now play a low-res copy."))
 		}
+		m.proxyFile = msg.file
+		return m, nil
 
-		m.list = l
+	case embeddingsDoneMsg:
+		if msg.err != nil {
+			m.statuses = append(m.statuses, trf("Embedded %d lines before failing, the rest are embedded next time: %s", msg.count, msg.err.Error()))
+		} else if msg.count > 0 {
+			m.statuses = append(m.statuses, trf("Embedded %d lines for tsplice search --semantic.", msg.count))
+		}
+		return m, nil
 
+	case redactionsFoundMsg:
+		m.redactions = msg.terms
+		if msg.err != nil {
+			m.statuses = append(m.statuses, trf("Could not look for names to redact: %s", msg.err.Error()))
+		}
+		m.statuses = append(m.statuses, trf("Redacting %d names, email addresses, and phone numbers from exports and the compiled audio.", len(m.redactions)))
 		return m, nil
 
 	case videoCompilationDoneMsg:
-		m.statuses = append(m.statuses, "Video compiled successfully.")
-		m.statuses = append(m.statuses, "Saved output to "+msg.outputFile)
+		clearJournal(m.vttFile)
+		m.progress = nil
+		if len(msg.clips) > 0 {
+			m.statuses = append(m.statuses, trf("Saved %d clips to %s", len(msg.clips), filepath.Dir(msg.outputFile)))
+		} else {
+			m.statuses = append(m.statuses, tr("Video compiled successfully."))
+			m.statuses = append(m.statuses, trf("Saved output to %s", msg.outputFile))
+		}
+		if m.compileOptions.Manifest && len(msg.clips) == 0 {
+			m.statuses = append(m.statuses, trf("Saved manifest to %s", manifestPath(msg.outputFile)))
+		}
+		if m.compileOptions.Draft && len(msg.clips) == 0 {
+			m.statuses = append(m.statuses, trf("Once the draft looks right, run tsplice --final %s to render it at full quality.", m.inputFile))
+		}
+		if m.compileOptions.Verify {
+			if len(msg.warnings) == 0 {
+				m.statuses = append(m.statuses, tr("Output verified, audio and video are in sync."))
+			}
+			for _, warning := range msg.warnings {
+				m.statuses = append(m.statuses, trf("Warning: %s", warning))
+			}
+		}
 		m.loading = false
 		m.quitting = true
 		return m, tea.Quit
 
-	case errorMsg:
-		m.statuses = append(m.statuses, msg.err.Error())
+	case audiogramsDoneMsg:
+		m.statuses = append(m.statuses, trf("Exported %d audiograms.", len(msg.files)))
 		m.loading = false
-		m.errorMsg = msg.err.Error()
+		return m, nil
+
+	case errorMsg:
+		return m.fail(msg.err), nil
+
+	case karaokeTickMsg:
+		if m.karaoke != nil {
+			return m, karaokeTick()
+		}
+		return m, nil
+
+	case audioPreviewDoneMsg:
+		if m.karaoke != nil && m.karaoke.process == msg.process {
+			m.karaoke = nil
+		}
 		return m, nil
 
 	case spinner.TickMsg:
@@ -199,22 +802,35 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) View() string {
 	if m.quitting {
+		// The error stays in the terminal once the error screen is gone
+		if m.failure != nil {
+			return styleOutput(append(slices.Clip(m.statuses), m.failure.detail))
+		}
 		return styleOutput(m.statuses)
 	}
 
 	// Content area
-	if m.errorMsg != "" {
-		return styleOutput(m.statuses) + "\nPress 'q' to quit"
+	if m.failure != nil {
+		return styleOutput(m.statuses) + m.failureView()
 	} else if m.loading {
-		loadingText := fmt.Sprintf("%s%s", m.spinner.View(), m.loadingMsg)
+		// The spinner's ticks redraw the bar as ffmpeg moves it along
+		loadingText := fmt.Sprintf("%s%s", m.spinner.View(), m.loadingMsg) + m.progressView()
 		if len(m.statuses) > 0 {
 			return styleOutput(m.statuses) + loadingText
 		}
 		return loadingText
+	} else if m.diffing {
+		return styleOutput(m.statuses) + m.diffView()
+	} else if m.naming {
+		return styleOutput(m.statuses) + m.namingView()
+	} else if m.showStats {
+		return m.statsView()
+	} else if m.zen {
+		return m.listView()
 	} else {
 		// Show transcript list
 		if len(m.transcriptItems) == 0 {
-			return styleOutput(m.statuses) + "No transcript items found"
+			return styleOutput(m.statuses) + tr("No transcript items found")
 		}
 
 		// Add header with total time info
@@ -222,38 +838,176 @@ func (m model) View() string {
 		if len(m.transcriptItems) > 0 {
 			firstStart := m.transcriptItems[0].StartTime
 			lastEnd := m.transcriptItems[len(m.transcriptItems)-1].EndTime
-			header = fmt.Sprintf("  Start: %s | End: %s\n", firstStart, lastEnd)
+			header = "  " + trf("Start: %s | End: %s", offsetTimestamp(firstStart, m.tcOffset), offsetTimestamp(lastEnd, m.tcOffset))
+			header += m.languageHeader()
+			if m.readOnly {
+				header += ErrorStyle.Render("  " + tr("read-only"))
+			}
+			if offline {
+				header += TagStyle.Render("  " + tr("offline, nothing leaves this machine"))
+			}
+			if m.recordingMacro {
+				header += ErrorStyle.Render("  ● " + tr("recording macro"))
+			}
+			if m.askTracks > 0 {
+				header += TagStyle.Render("  " + trf("%d audio tracks: k keep all • s export stems • enter first only • esc cancel", m.askTracks))
+			}
+			if m.autoEditing {
+				header += ErrorStyle.Render("  " + tr("auto-edit preview: enter keep/remove • F apply • esc discard"))
+			}
+			if m.editingText {
+				header += TagStyle.Render("  " + tr("editing: enter save • esc cancel"))
+			}
+			if m.splitting {
+				at, _ := m.splitTime()
+				header += TagStyle.Render("  " + trf("split at %s: ←/→ move • enter split • esc cancel", formatTimestamp(at+m.tcOffset)))
+			}
+			if m.ranging {
+				header += TagStyle.Render("  " + trf("range of %d: enter select • A deselect • i invert • esc cancel", len(m.rangeIndexes())))
+			}
+			if m.bulkPending {
+				header += TagStyle.Render("  " + trf("all %d visible: s select • d deselect • 1-9 tag • +/- pad", len(visibleIndexes(m.list))))
+			}
+			header += "\n"
 		}
 
-		return styleOutput(m.statuses) + header + m.list.View()
+		return styleOutput(m.statuses) + header + m.karaokeView() + m.listView()
 	}
 }
 
 func main() {
 	fmt.Println(BulletStyle.Render("┌") + TitleStyle.Render("tsplice"))
+	handleSignals()
+	defer catchCrash()
 
 	var lang string
+	var uiLang string
 	var prompt string
 	var gate bool
+	var retranscribe bool
+	var autoSubs bool
+	var keepStreams bool
+	var xmp bool
+	var writeManifestFile bool
+	var rulesFile string
+	var stems bool
+	var channels string
+	var speakerChannel string
+	var sampleRate int
+	var maxSize string
+	var audiogramImage string
+	var waveform bool
+	var verify bool
+	var trimSilence bool
+	var removeBreaths bool
+	var tcOffset string
+	var sourceURL string
+	var calendarFile string
+	var redact bool
+	var offlineFlag bool
+	var encrypt bool
+	var sttCommandFlag string
+	var localFlag bool
+	var provider string
+	var whisperModelFlag string
+	var profileName string
+	var redactNames bool
+	var noProxy bool
+	var noLocalCopy bool
+	var draft bool
+	var cacheSegments bool
+	var streamCopy bool
+	var split bool
+	var timingsFlag bool
+	var crashReportsFlag bool
+	var at string
+	var speakers string
+	var embed bool
+	var embedCommandFlag string
+	var final bool
+	var lowerThirds bool
+	var burnCaptions bool
+	var intro string
+	var outro string
+	var apiBaseFlag string
+	var modelFlag string
+	var outputDirFlag string
+	var player string
 	var help bool
 	var version bool
 
-	flag.StringVar(&lang, "lang", "auto", "Language for transcription (e.g. en, es, fr)")
+	flag.StringVar(&lang, "lang", "", "Language for transcription (e.g. en, es, fr)")
+	flag.StringVar(&uiLang, "ui-lang", "", "Language the editor is shown in: en, de, es, fr, or pt (the system's by default)")
 	flag.StringVar(&prompt, "prompt", "", "Optional prompt used to create a more accurate transcription")
 	flag.BoolVar(&gate, "gate", false, "Remove long periods of silence during audio extraction")
+	flag.BoolVar(&retranscribe, "retranscribe", false, "Transcribe again and compare against an existing transcript")
+	flag.BoolVar(&autoSubs, "auto-subs", false, "Use YouTube's captions instead of transcribing when the input is a URL")
+	flag.BoolVar(&keepStreams, "keep-streams", false, "Carry every audio track, subtitle track, and chapter through to the output")
+	flag.BoolVar(&xmp, "xmp", false, "Write an XMP sidecar with provenance info next to the output")
+	flag.BoolVar(&writeManifestFile, "manifest", false, "Write a manifest with checksums and compile parameters next to the output")
+	flag.StringVar(&rulesFile, "rules", "", "Starlark file with a rule(segment) function, applied with 'r'")
+	flag.BoolVar(&stems, "stems", false, "Export each audio track of the selection as a separate WAV file")
+	flag.StringVar(&channels, "channels", "", "Output channel layout, mono or stereo (downmixes surround sources)")
+	flag.StringVar(&speakerChannel, "speaker-channel", "", "Use only the left or right channel of stereo audio, or auto to pick the louder one per segment")
+	flag.IntVar(&sampleRate, "sample-rate", 0, "Output audio sample rate in Hz (e.g. 44100, 48000)")
+	flag.StringVar(&maxSize, "max-size", "", "Two-pass encode the output to fit a size limit (e.g. 50MB)")
+	flag.StringVar(&audiogramImage, "audiogram-image", "", "Background image for audiograms exported with 'w'")
+	flag.BoolVar(&waveform, "waveform", false, "Animate a waveform behind the captions of exported audiograms")
+	flag.BoolVar(&lowerThirds, "lower-thirds", false, "Show each speaker's name over the start of their first selected line")
+	flag.BoolVar(&burnCaptions, "burn-captions", false, "Draw the selected lines onto the compiled video as captions")
+	flag.StringVar(&intro, "intro", "", "Video clip to play before the compiled selection")
+	flag.StringVar(&outro, "outro", "", "Video clip to play after the compiled selection")
+	flag.BoolVar(&draft, "draft", false, "Compile a quick low-res draft of the selection for review")
+	flag.StringVar(&speakers, "speaker", "", "Compile only a speaker's lines, without opening the editor (comma separated for a reel each)")
+	flag.BoolVar(&final, "final", false, "Render the last --draft's cut list at full quality, without opening the editor")
+	flag.BoolVar(&split, "split", false, "Save each selected segment as its own numbered clip instead of one video")
+	flag.BoolVar(&cacheSegments, "cache-segments", false, "Encode segments separately and reuse them across recompiles")
+	flag.BoolVar(&streamCopy, "copy", false, "Cut at keyframes without re-encoding, fast and lossless but not frame accurate")
+	flag.StringVar(&outputDirFlag, "output-dir", "", "Folder compiled videos are saved to, next to the source by default")
+	flag.BoolVar(&verify, "verify", false, "Check the compiled video for truncation or audio/video drift")
+	flag.StringVar(&profileName, "profile", "", "Automatic editing settings for a kind of video: podcast, screencast, lecture, or one from the config")
+	flag.BoolVar(&trimSilence, "trim-silence", false, "Trim non-speech from the start and end of every selected segment")
+	flag.BoolVar(&removeBreaths, "remove-breaths", false, "Turn down audible breaths at the start and end of every selected segment")
+	flag.StringVar(&tcOffset, "tc-offset", "", "Start timecode of the footage (e.g. 01:00:00:00), read from metadata if not set")
+	flag.StringVar(&sourceURL, "source-url", "", "URL of the published video, used for links exported with 'L'")
+	flag.BoolVar(&offlineFlag, "offline", false, "Never send audio or transcripts off this machine, transcribing with --local or --stt-command")
+	flag.StringVar(&provider, "provider", "", "Transcription provider: openai, azure, deepgram, assemblyai, whisper, or command")
+	flag.StringVar(&apiBaseFlag, "api-base", "", "OpenAI-compatible server to transcribe with (e.g. http://localhost:8080/v1)")
+	flag.StringVar(&modelFlag, "model", "", "Model the transcription server transcribes with, whisper-1 by default")
+	flag.BoolVar(&localFlag, "local", false, "Transcribe with a local whisper.cpp instead of OpenAI")
+	flag.StringVar(&whisperModelFlag, "whisper-model", "", "ggml model file for whisper.cpp to transcribe with, used with --local")
+	flag.StringVar(&sttCommandFlag, "stt-command", "", "Local program that prints a VTT transcript of the audio file appended to it")
+	flag.BoolVar(&embed, "embed", false, "Embed the transcript for tsplice search --semantic")
+	flag.StringVar(&embedCommandFlag, "embed-command", "", "Local program that prints an embedding for each line of text it reads, used instead of OpenAI")
+	flag.BoolVar(&encrypt, "encrypt", false, "Encrypt transcripts and project files with a passphrase, with no proxy, local copy, or cached segments made, and the run's workspace removed when it exits")
+	flag.BoolVar(&redact, "redact", false, "Redact email addresses and phone numbers from exports and bleep them in the output")
+	flag.BoolVar(&redactNames, "redact-names", false, "Also redact people's names, found with OpenAI's chat API")
+	flag.StringVar(&player, "player", "", "Player previews open in: mpv, vlc, or ffplay")
+	flag.BoolVar(&noProxy, "no-proxy", false, "Preview large videos from the source instead of a low-res proxy")
+	flag.BoolVar(&noLocalCopy, "no-local-copy", false, "Read sources on network drives in place instead of copying them to the local cache")
+	flag.StringVar(&calendarFile, "calendar", "", "ICS file to name the output after the meeting the video was recorded in")
+	flag.StringVar(&at, "at", "", "Start on the line being said at a time (e.g. 00:12:30)")
+	flag.BoolVar(&timingsFlag, "timings", false, "Print how long each stage took when tsplice exits")
+	flag.BoolVar(&crashReportsFlag, "crash-reports", false, "On a crash, save a report and offer to open a GitHub issue with it")
 	flag.BoolVar(&help, "help", false, "Show usage info")
 	flag.BoolVar(&version, "version", false, "Show version info")
 	flag.Usage = func() {
 		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Usage: tsplice [options] <input-file>"))
 		fmt.Println(BulletStyle.Render("│"))
 		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Options:"))
-		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render("--lang") + DimTextStyle.Render("    language for transcription (e.g. en, es, fr)"))
-		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render("--prompt") + DimTextStyle.Render("  optional prompt used to create a more accurate transcription"))
-		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render("--gate") + DimTextStyle.Render("    remove long periods of silence (>10s) during audio extraction"))
+		var options []*flag.Flag
+		flag.CommandLine.VisitAll(func(f *flag.Flag) { options = append(options, f) })
+		fmt.Println(strings.Join(flagLines(options), "\n"))
+		fmt.Println(BulletStyle.Render("│"))
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Commands:") + DimTextStyle.Render(" see tsplice help <command> for its options and examples"))
+		for _, cmd := range commands {
+			spaces := strings.Repeat(" ", 20-len(cmd.name))
+			fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(cmd.name) + DimTextStyle.Render(spaces+cmd.summary))
+		}
 		fmt.Println(BulletStyle.Render("│"))
 		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Requirements:"))
 
-		dependencies := []string{"ffmpeg", "mpv"}
+		dependencies := []string{"ffmpeg", "mpv", "yt-dlp"}
 		for _, dependency := range dependencies {
 			status := "✔ installed"
 			if !checkDependency(dependency) {
@@ -279,7 +1033,119 @@ func main() {
 		os.Exit(0)
 	}
 
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: could not read "+configPath()+": "+err.Error()))
+		os.Exit(1)
+	}
+
+	// Subcommands transcribe too, so these apply to them as well
+	if lang == "" {
+		lang = cfg.Lang
+	}
+	if lang == "" {
+		lang = "auto"
+	}
+	if prompt == "" {
+		prompt = cfg.Prompt
+	}
+	defaultLanguage, defaultPrompt = lang, prompt
+	offline = offlineFlag || cfg.Offline
+	sttCommand = cfg.STTCommand
+	if sttCommandFlag != "" {
+		sttCommand = sttCommandFlag
+	}
+	localWhisper = localFlag || cfg.Local
+	whisperModel = cfg.WhisperModel
+	if whisperModelFlag != "" {
+		whisperModel = whisperModelFlag
+	}
+	whisperBinary = cfg.WhisperBin
+	if provider == "" {
+		provider = cfg.Provider
+	}
+	if err := setProvider(strings.ToLower(provider)); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if apiBaseFlag == "" {
+		apiBaseFlag = cmp.Or(os.Getenv("TSPLICE_API_BASE"), cfg.APIBase)
+	}
+	if err := setAPIBase(apiBaseFlag); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if modelFlag == "" {
+		modelFlag = cmp.Or(os.Getenv("TSPLICE_MODEL"), cfg.Model)
+	}
+	if modelFlag != "" {
+		transcriptionModel = modelFlag
+	}
+	if profileName == "" {
+		profileName = cfg.Profile
+	}
+	if uiLang == "" {
+		uiLang = cfg.UILang
+	}
+	if err := setUILanguage(uiLang); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	outputDir = outputDirFlag
+	if outputDir == "" {
+		outputDir = cfg.OutputDir
+	}
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: could not create the output folder: "+err.Error()))
+			os.Exit(1)
+		}
+	}
+	if player == "" {
+		player = cfg.PreviewPlayer
+	}
+	if err := setPreviewPlayer(player); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	baseEncoding = profile{VideoCodec: cfg.VideoCodec, AudioCodec: cfg.AudioCodec, CRF: cfg.CRF}
+	if err := baseEncoding.validate(); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+configPath()+" "+err.Error()))
+		os.Exit(1)
+	}
+	if err := setProfiles(cfg.Profiles, profileName); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	embedCommand = cfg.EmbedCommand
+	if embedCommandFlag != "" {
+		embedCommand = embedCommandFlag
+	}
+	if offline {
+		fmt.Println(BulletStyle.Render("├") + TagStyle.Render("Offline mode: audio and transcripts never leave this machine."))
+	}
+	encryptAtRest = encrypt || cfg.Encrypt
+	apiKeyCommand = cfg.APIKeyCmd
+	timings.enabled = timingsFlag
+	crashReports = crashReportsFlag || cfg.CrashReports
+
 	args := flag.Args()
+	if len(args) > 0 {
+		if cmd, ok := findCommand(args[0]); ok {
+			err := cmd.run(args[1:])
+			shutdown()
+			if err != nil {
+				if errors.Is(err, flag.ErrHelp) {
+					os.Exit(0)
+				}
+				fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			printTimings()
+			os.Exit(0)
+		}
+	}
+
 	if len(args) != 1 {
 		flag.Usage()
 		os.Exit(0)
@@ -292,85 +1158,424 @@ func main() {
 		os.Exit(0)
 	}
 
-	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
-		fmt.Printf(BulletStyle.Render("└")+TextStyle.Render("Error: file '%s' does not exist.")+"\n", inputFile)
-		os.Exit(1)
-	}
+	// Download remote videos first so the rest of the flow works on a local file
+	var captions []TranscriptItem
+	if isURL(inputFile) {
+		url := inputFile
+		if sourceURL == "" {
+			sourceURL = url
+		}
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Downloading video with yt-dlp..."))
 
-	// Validate the input file is a video file
-	validExtensions := []string{".mp4", ".avi", ".mov", ".mkv", ".m4v"}
-	fileExt := strings.ToLower(filepath.Ext(inputFile))
+		downloaded, err := downloadVideo(url)
+		if err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		inputFile = downloaded
 
-	if !slices.Contains(validExtensions, fileExt) {
-		fmt.Printf(BulletStyle.Render("└")+TextStyle.Render("Error: file '%s' is not a valid video file.")+"\n", inputFile)
-		os.Exit(1)
+		if autoSubs && isYouTubeURL(url) {
+			basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+			captions, err = downloadAutoCaptions(url, lang, basename)
+			if err != nil {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Could not use YouTube captions: "+err.Error()))
+			}
+		}
 	}
 
-	// Check if OPENAI_API_KEY env variable is set, and if not, prompt for it
-	username := getSystemUser()
-
-	apiKey, err := keyring.Get("tsplice", username)
-	if err != nil {
-		if !strings.Contains(err.Error(), "secret not found") {
-			fmt.Println("Error reading API key:", err)
-			return
+	// A Zoom or Meet recording folder opens as its recording
+	var meeting meetingRecording
+	if info, err := os.Stat(inputFile); err == nil && info.IsDir() {
+		if meeting, err = findMeetingRecording(inputFile); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
 		}
+		inputFile = meeting.video
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Opening %s recording %s", meeting.app, filepath.Base(inputFile))))
 	}
 
-	if apiKey != "" {
-		os.Setenv("OPENAI_API_KEY", apiKey)
-		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("API key set for this session."))
+	if channels != "" && channels != "mono" && channels != "stereo" {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --channels must be mono or stereo"))
+		os.Exit(1)
 	}
 
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		fmt.Print(BulletStyle.Render("├") + TextStyle.Render("OPENAI_API_KEY not found, enter one: "))
+	if speakerChannel != "" && speakerChannel != "left" && speakerChannel != "right" && speakerChannel != "auto" {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --speaker-channel must be left, right, or auto"))
+		os.Exit(1)
+	}
 
-		byteApiKey, err := term.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			fmt.Println("Error reading API key:", err)
-			return
+	if sampleRate < 0 {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --sample-rate must be a positive number of Hz"))
+		os.Exit(1)
+	}
+
+	var atSeconds float64
+	if at != "" {
+		var err error
+		if atSeconds, err = parseTimeToSeconds(at); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --at must be a time like 00:12:30"))
+			os.Exit(1)
 		}
+	}
 
-		fmt.Println()
-		apiKey := strings.TrimSpace(string(byteApiKey))
+	if draft && final {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --draft and --final can't be used together, compile the draft first"))
+		os.Exit(1)
+	}
+	if split && final {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --final renders one draft, so it can't be used with --split"))
+		os.Exit(1)
+	}
+	var speakerReels []string
+	for _, speaker := range strings.Split(speakers, ",") {
+		if speaker = strings.TrimSpace(speaker); speaker != "" {
+			speakerReels = append(speakerReels, speaker)
+		}
+	}
 
-		if apiKey == "" {
-			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("An OpenAI API key is required to proceed."))
+	var maxSizeBytes int64
+	if maxSize != "" {
+		var err error
+		if maxSizeBytes, err = parseSize(maxSize); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
 			os.Exit(1)
 		}
+	}
 
-		err = keyring.Set("tsplice", username, apiKey)
-		if err != nil {
-			fmt.Println("Error saving API key:", err)
-			return
+	for _, clip := range []string{intro, outro} {
+		if clip == "" {
+			continue
+		}
+		if _, err := os.Stat(clip); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
 		}
+	}
 
-		os.Setenv("OPENAI_API_KEY", apiKey)
-		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("API key set for this session."))
+	if err := validateInputFile(inputFile); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+
+	colorRules, err := newColorRules(cfg.Colors)
+	if err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: invalid color rule in "+configPath()+": "+err.Error()))
+		os.Exit(1)
+	}
+
+	captionStyles, err := newSpeakerStyles(cfg.CaptionSpeakers)
+	if err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: invalid caption_speakers style in "+configPath()+": "+err.Error()))
+		os.Exit(1)
+	}
+
+	autoSelect, err := newAutoSelectRules(cfg.AutoSelect)
+	if err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: invalid auto_select rule in "+configPath()+": "+err.Error()))
+		os.Exit(1)
 	}
 
 	// Check if VTT file already exists
-	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
-	vttFile := basename + ".vtt"
+	vttFile := vttPath(inputFile)
+
+	// Another instance editing the same video would overwrite its transcript
+	readOnly := false
+	release, pid, err := lockVideo(vttFile)
+	if err != nil {
+		holder := lockHolder(pid)
+		if _, statErr := os.Stat(vttFile); statErr != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: this video is already being transcribed by "+holder+", wait for it to finish"))
+			os.Exit(1)
+		}
+		if retranscribe || !confirm("This video is already being edited by "+holder+". Open it read-only?") {
+			os.Exit(1)
+		}
+		readOnly = true
+		captions = nil
+	} else {
+		defer release()
+		atShutdown(release)
+	}
 
 	// Initialize spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = SpinnerStyle
 
+	transcriber := newTranscriber(lang, prompt)
+
 	// Create initial model
 	initialModel := model{
-		spinner:    s,
-		loading:    true,
-		loadingMsg: "Extracting audio with ffmpeg...",
-		inputFile:  inputFile,
-		gate:       gate,
+		spinner:     s,
+		progress:    &renderProgress{},
+		progressBar: newProgressBar(),
+		transcriber: transcriber,
+		language:    lang,
+		sourceURL:   sourceURL,
+		loading:     true,
+		loadingMsg:  tr("Extracting audio with ffmpeg..."),
+		inputFile:   inputFile,
+		readOnly:    readOnly,
+		gate:        gate,
+		vttFile:     vttFile,
+		rulesFile:   rulesFile,
+		tags:        cfg.Tags,
+		colorRules:  colorRules,
+		autoSelect:  autoSelect,
+		fillers:     cfg.Fillers,
+		bleep:       cfg.Bleep,
+		redact:      redact || redactNames,
+		redactNames: redactNames,
+		audiogram:   audiogramOptions{Image: audiogramImage, Waveform: waveform, Colors: cfg.Audiogram, Speakers: captionStyles},
+		compileOptions: compileOptions{
+			KeepStreams:    keepStreams,
+			XMPSidecar:     xmp,
+			Manifest:       writeManifestFile,
+			Stems:          stems,
+			Channels:       channels,
+			SpeakerChannel: speakerChannel,
+			SampleRate:     sampleRate,
+			MaxSize:        maxSizeBytes,
+			Verify:         verify,
+			Draft:          draft,
+			CacheSegments:  cacheSegments,
+			StreamCopy:     streamCopy,
+			Split:          split,
+			TrimSilence:    trimSilence,
+			RemoveBreaths:  removeBreaths,
+			BoostGain:      cfg.QuietGain,
+			Intro:          intro,
+			Outro:          outro,
+		},
+	}
+	editProfile, _ := findProfile(activeProfile)
+	editProfile.apply(&initialModel.compileOptions)
+	if activeProfile != "" {
+		initialModel.compileOptions.TrimSilence = trimSilence || editProfile.TrimSilence
+		initialModel.compileOptions.SilenceThreshold = editProfile.SilenceThreshold
+		if len(editProfile.Fillers) > 0 {
+			initialModel.fillers = editProfile.Fillers
+		}
+		initialModel.statuses = append(initialModel.statuses, trf("Editing with the %s profile.", activeProfile))
+	}
+	if lowerThirds {
+		if cfg.LowerThird.Duration <= 0 {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: lower_third duration in "+configPath()+" has to be more than 0 seconds"))
+			os.Exit(1)
+		}
+		initialModel.compileOptions.LowerThird = &cfg.LowerThird
+	}
+	if burnCaptions {
+		if err := cfg.Captions.validate(); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: captions in "+configPath()+" "+err.Error()))
+			os.Exit(1)
+		}
+		cfg.Captions.speakers = captionStyles
+		initialModel.compileOptions.Captions = &cfg.Captions
+	}
+
+	// Find out now if this ffmpeg can't encode what was asked for, rather
+	// than at the end of a long compile
+	if status, err := probeEncoders(); err != nil {
+		initialModel.statuses = append(initialModel.statuses, trf("Compiling may fail, %s", err.Error()))
+	} else if status != "" {
+		initialModel.statuses = append(initialModel.statuses, status)
+	}
+	if err := editProfile.checkEncoders(); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if maxSizeBytes > 0 && twoPassEncoder() == "" {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --max-size needs ffmpeg built with libx264 or mpeg4 for its two-pass encode"))
+		os.Exit(1)
+	}
+	if err := checkStreamCopy(initialModel.compileOptions); err != nil {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if streamCopy {
+		initialModel.statuses = append(initialModel.statuses, tr("Compiling with --copy, each cut starts at the keyframe before its line."))
+	}
+
+	probe, probeErr := probeStreams(inputFile)
+
+	// Screen recordings are often VFR, which drifts out of sync when compiled as is
+	if probeErr == nil {
+		if rate, vfr := variableFrameRate(probe); vfr {
+			initialModel.compileOptions.ConstantFrameRate = rate
+			initialModel.statuses = append(initialModel.statuses, trf("Variable frame rate detected, output will be converted to a constant %.2f fps.", parseFrameRate(rate)))
+		}
+	}
+
+	// A project that was encrypted stays that way, and decides before
+	// anything is cached whether it can be
+	if !encryptAtRest {
+		for _, file := range projectFiles(inputFile) {
+			if isEncryptedFile(file) {
+				encryptAtRest = true
+				break
+			}
+		}
+	}
+
+	// Every extraction, preview, and compile reads the whole source, which is
+	// slow over a network, so it's read across once. The copy couldn't be
+	// encrypted, so it's read in place while encrypting
+	if !noLocalCopy && !encryptAtRest && onNetworkMount(inputFile) {
+		label := "Copying " + filepath.Base(inputFile) + " off the network drive..."
+		shown := int64(-1)
+		copying := time.Now()
+		localFile, err := copyToLocal(inputFile, func(written, total int64) {
+			if percent := written * 100 / max(total, 1); percent != shown {
+				shown = percent
+				fmt.Print("\r" + BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("%s %3d%%", label, percent)))
+			}
+		})
+		fmt.Print("\r\033[K")
+		recordTiming("copy to local", copying)
+		if err != nil {
+			initialModel.statuses = append(initialModel.statuses, trf("Reading the source from the network drive, %s", err.Error()))
+		} else {
+			initialModel.compileOptions.LocalCopy = localFile
+			initialModel.statuses = append(initialModel.statuses, tr("Source is on a network drive, working from a local copy."))
+		}
+	}
+
+	// Large sources on slow drives take a while to open, so previews play a
+	// copy, unless encrypting, where it would be left unencrypted in the cache
+	if probeErr == nil && !noProxy && !encryptAtRest && needsProxy(probe) {
+		if proxyFile, err := proxyPath(inputFile); err == nil {
+			if _, err := os.Stat(proxyFile); err == nil {
+				initialModel.proxyFile = proxyFile
+			} else {
+				initialModel.makeProxy = proxyFile
+				initialModel.statuses = append(initialModel.statuses, tr("Making a low-res preview proxy in the background."))
+			}
+		}
+	}
+
+	// Show timestamps the way the camera and NLE count them
+	initialModel.fps = 30
+	detectedTimecode := ""
+	if probeErr == nil {
+		initialModel.fps, detectedTimecode = timecodeInfo(probe)
+	}
+	if tcOffset == "" && detectedTimecode != "" {
+		tcOffset = detectedTimecode
+		initialModel.statuses = append(initialModel.statuses, trf("Start timecode %s read from metadata.", detectedTimecode))
+	}
+	if tcOffset != "" {
+		offset, err := parseTimecode(tcOffset, initialModel.fps)
+		if err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		initialModel.tcOffset = offset
+	}
+	initialModel.dropFrame = usesDropFrame(tcOffset, initialModel.fps)
+
+	if calendarFile != "" {
+		event, ok, err := meetingFor(calendarFile, inputFile)
+		if err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		if ok {
+			// The date keeps recurring meetings from overwriting each other
+			initialModel.compileOptions.Title = event.Summary + " " + event.Start.Local().Format("2006-01-02")
+			initialModel.compileOptions.Attendees = event.Attendees
+			initialModel.statuses = append(initialModel.statuses, trf("Recorded during %s with %d attendees", event.Summary, len(event.Attendees)))
+		} else {
+			initialModel.statuses = append(initialModel.statuses, tr("No meeting in the calendar matches when this was recorded"))
+		}
+	}
+
+	// Offer to use an embedded subtitle track instead of transcribing
+	existingStatus := tr("Transcript already exists locally")
+	if len(captions) > 0 {
+		if err := writeProjectFile(vttFile, []byte(formatVTT(captions))); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		existingStatus = tr("Transcript created from YouTube captions")
+	} else if _, err := os.Stat(vttFile); os.IsNotExist(err) && len(meeting.participants) > 0 {
+		// Each participant's own audio says exactly who is talking
+		if err := setupAPIKey(); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		transcriptItems, err := transcribeParticipants(meeting.participants, initialModel.transcriber)
+		if err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		if err := writeProjectFile(vttFile, []byte(formatVTT(transcriptItems))); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		existingStatus = trf("Transcript created from %d participants' audio", len(meeting.participants))
+	} else if _, err := os.Stat(vttFile); os.IsNotExist(err) {
+		if stream, language := findSubtitleStream(inputFile); stream >= 0 {
+			question := "Subtitle track found, use it instead of transcribing?"
+			if language != "" {
+				question = fmt.Sprintf("Subtitle track (%s) found, use it instead of transcribing?", language)
+			}
+			if confirm(question) {
+				if err := extractSubtitles(inputFile, stream, vttFile); err != nil {
+					fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				existingStatus = tr("Transcript extracted from subtitle track")
+			}
+		}
+	}
+
+	if meeting.chatFile != "" && !readOnly {
+		if count, err := saveMeetingChat(meeting.chatFile, vttFile); err != nil {
+			initialModel.statuses = append(initialModel.statuses, trf("Could not read the meeting chat: %s", err.Error()))
+		} else if count > 0 {
+			initialModel.statuses = append(initialModel.statuses, trf("Saved %d chat messages to %s", count, meetingChatPath(vttFile)))
+		}
+	}
+
+	// Ask for the passphrase now, it can't be typed in once the interface is up
+	if encryptAtRest && !readOnly {
+		if _, err := unlock(!isEncryptedFile(vttFile)); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		if err := verifyPassphrase(inputFile); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		if count, err := encryptProject(inputFile); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		} else if count > 0 {
+			initialModel.statuses = append(initialModel.statuses, trf("Encrypted %d existing project files.", count))
+		}
+	}
+
+	// SQLite files can't be encrypted like the rest of the project, so
+	// there's no store while encrypting
+	if !readOnly && !encryptAtRest {
+		s, err := openStore(vttFile)
+		if err != nil {
+			initialModel.statuses = append(initialModel.statuses, trf("Selections won't be saved, %s", err.Error()))
+		}
+		initialModel.store = s
+		defer s.Close()
+		atShutdown(func() { s.Close() })
+
+		if s != nil && (embed || cfg.Embed) {
+			initialModel.embedder = defaultEmbedder()
+		}
 	}
 
 	// Check if transcript already exists
 	if _, err := os.Stat(vttFile); err == nil {
 		// Load existing transcript
-		vttBytes, err := os.ReadFile(vttFile)
+		vttBytes, err := readProjectFile(vttFile)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, BulletStyle.Render("└")+TextStyle.Render("There was a problem reading the existing VTT file: %v")+"\n", err)
 			os.Exit(1)
@@ -382,50 +1587,168 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Convert to list items
-		items := make([]list.Item, len(transcriptItems))
-		for i, transcriptItem := range transcriptItems {
-			items[i] = item{
-				title:     transcriptItem.Text,
-				timestamp: transcriptItem.StartTime + " - " + transcriptItem.EndTime,
-				selected:  false,
+		details, err := loadDetails(vttFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, BulletStyle.Render("└")+TextStyle.Render("There was a problem reading the transcript details: %v")+"\n", err)
+			os.Exit(1)
+		}
+		transcriptItems = applyDetails(transcriptItems, details)
+
+		if retranscribe {
+			// Keep the existing transcript around to compare against the new one
+			initialModel.previousItems = transcriptItems
+			initialModel.statuses = append(initialModel.statuses, tr("Transcript already exists locally, re-transcribing"))
+		} else {
+			initialModel.loading = false
+			initialModel.details = details
+			initialModel = initialModel.syncStore(transcriptItems)
+			initialModel.list = initialModel.newTranscriptList(transcriptItems)
+			initialModel.transcriptItems = transcriptItems
+			initialModel.statuses = append(initialModel.statuses, existingStatus)
+			initialModel, _ = initialModel.findRedactions(transcriptItems)
+		}
+	}
+
+	// Pick up where a run that crashed or was killed left off
+	if j, ok := loadJournal(vttFile); ok && !readOnly {
+		if initialModel.loading {
+			if audioFile, ok := resumableAudio(j, gate, speakerChannel); ok {
+				initialModel.resumeAudio = audioFile
+				initialModel.loadingMsg = transcribingMessage(initialModel.transcriber)
+				initialModel.statuses = append(initialModel.statuses, tr("Resuming with the audio extracted by a previous run."))
 			}
+		} else if j.Stage == stageCompileStarted {
+			items, restored := restoreSelection(initialModel.list.Items(), j.Segments)
+			if restored > 0 {
+				initialModel.list.SetItems(items)
+				initialModel.statuses = append(initialModel.statuses, trf("A previous compile didn't finish, restored its %d selected lines. Press c to compile again.", restored))
+			}
+			clearJournal(vttFile)
 		}
+	}
 
-		// Create list
-		l := list.New(items, itemDelegate{}, 64, 16)
-		l.SetShowTitle(false)
-		l.SetShowStatusBar(false)
-		l.SetFilteringEnabled(true)
-		l.SetShowHelp(true)
-		l.SetShowPagination(false)
+	// Bring in whatever was picked together with tsplice serve, unless a
+	// restored compile or auto_select rules already set the selection
+	if !initialModel.loading && !hasSelection(initialModel.list.Items()) {
+		if shared := loadSharedSelection(inputFile); len(shared) > 0 {
+			items := initialModel.list.Items()
+			loaded := 0
+			for index, listItem := range items {
+				if i, ok := listItem.(item); ok && shared[i.start] {
+					i.selected = true
+					items[index] = i
+					loaded++
+				}
+			}
+			initialModel.list.SetItems(items)
+			initialModel.statuses = append(initialModel.statuses, trf("Loaded %d lines selected in a shared session.", loaded))
+		}
+	}
 
-		// Add custom key bindings for help
-		l.AdditionalShortHelpKeys = func() []key.Binding {
-			return []key.Binding{
-				key.NewBinding(
-					key.WithKeys("p"),
-					key.WithHelp("p", "preview"),
-				),
-				key.NewBinding(
-					key.WithKeys("c"),
-					key.WithHelp("c", "compile"),
-				),
+	// Start on the line a search matched
+	if at != "" && !initialModel.loading {
+		for index, listItem := range initialModel.list.Items() {
+			if i, ok := listItem.(item); ok && i.end > atSeconds {
+				initialModel.list.Select(index)
+				break
 			}
 		}
+	}
 
-		initialModel.loading = false
-		initialModel.list = l
-		initialModel.transcriptItems = transcriptItems
-		initialModel.statuses = append(initialModel.statuses, "Transcript already exists locally")
+	// Fail now rather than after a long extraction if the audio can't be saved
+	if initialModel.loading && initialModel.resumeAudio == "" {
+		if err := preflightExtract(inputFile, vttFile); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
 	}
 
-	// Create and run the program
+	// The draft recorded the cut list, so the final render needs nothing from the editor
+	if final {
+		// Speakers' drafts are named after them
+		reels := speakerReels
+		if len(reels) == 0 {
+			reels = []string{""}
+		}
+		for index, speaker := range reels {
+			opts := initialModel.compileOptions
+			opts.Speaker = speaker
+			outputFile, err := renderFinal(inputFile, opts)
+			if err != nil {
+				fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			bullet := "├"
+			if index == len(reels)-1 {
+				bullet = "└"
+			}
+			fmt.Println(BulletStyle.Render(bullet) + TextStyle.Render("Saved output to "+outputFile))
+		}
+		printTimings()
+		return
+	}
+
+	// Everything a speaker said is in the transcript, so their reel needs nothing from the editor
+	if len(speakerReels) > 0 {
+		if initialModel.loading {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: --speaker compiles from the transcript, open the video without it once to transcribe it"))
+			os.Exit(1)
+		}
+		if err := initialModel.compileSpeakers(speakerReels); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Compiled %d speaker reels.", len(speakerReels))))
+		printTimings()
+		return
+	}
+
+	if initialModel.loading && offline && !transcribesLocally() {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: offline mode only transcribes locally, use --local or set --stt-command or stt_command in "+configPath()))
+		os.Exit(1)
+	}
+
+	if initialModel.loading {
+		if err := checkProvider(); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	_, embedsWithOpenAI := initialModel.embedder.(openAIEmbedder)
+	if embedsWithOpenAI && offline {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: offline mode only embeds locally, set --embed-command or embed_command in "+configPath()))
+		os.Exit(1)
+	}
+
+	// Check if OPENAI_API_KEY env variable is set, and if not, prompt for it
+	if (initialModel.loading && transcribesWithOpenAI()) || redactNames || embedsWithOpenAI {
+		if err := setupAPIKey(); err != nil {
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	// Create and run the program, noting where it panics to report it
+	var program tea.Model = initialModel
+	if crashReports {
+		program = crashGuard{initialModel}
+	}
 	p := tea.NewProgram(
-		initialModel,
+		program,
 	)
 
-	if _, err := p.Run(); err != nil {
+	// Bubble Tea takes ctrl+c and SIGTERM while it runs, either way
+	// returning here to stop whatever it left running
+	tuiRunning.Store(true)
+	_, err = p.Run()
+	tuiRunning.Store(false)
+	if err != nil && !errors.Is(err, tea.ErrInterrupted) {
 		fmt.Printf("Error running program: %v", err)
 	}
+	shutdown()
+	if crashReports && reportEditorCrash() {
+		os.Exit(2)
+	}
+	printTimings()
 }
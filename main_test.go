@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const testVTT = `WEBVTT
+
+00:00:00.000 --> 00:00:02.000
+<v Alice>First line
+
+00:00:02.000 --> 00:00:05.000
+<v Alice>Second line
+
+00:00:05.000 --> 00:00:10.000
+<v Bob>Third line
+`
+
+// TestMain gives the tests a home of their own, so the workspaces and caches
+// they make don't end up in the real one.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "tsplice-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// useFakeExecutor swaps execute for a fake until the test is done.
+func useFakeExecutor(t *testing.T) *fakeExecutor {
+	t.Helper()
+	fake := &fakeExecutor{}
+	previous := execute
+	execute = fake
+	t.Cleanup(func() { execute = previous })
+	return fake
+}
+
+// transcribedModel is the editor once testVTT has come back from a fake
+// transcriber, for a video in a temp folder.
+func transcribedModel(t *testing.T) model {
+	t.Helper()
+	dir := t.TempDir()
+	m := model{
+		loading:     true,
+		inputFile:   filepath.Join(dir, "talk.mp4"),
+		vttFile:     filepath.Join(dir, "talk.vtt"),
+		transcriber: fakeTranscriber{vttContent: testVTT},
+	}
+
+	msg := transcribeAudioCmd(m.transcriber, filepath.Join(dir, "talk.mp3"), m.vttFile, true)()
+	if errMsg, ok := msg.(errorMsg); ok {
+		t.Fatal(errMsg.err)
+	}
+	return update(t, m, msg)
+}
+
+func update(t *testing.T, m model, msg tea.Msg) model {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	return updated.(model)
+}
+
+func press(t *testing.T, m model, key string) model {
+	t.Helper()
+	switch key {
+	case "enter":
+		return update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	case "esc":
+		return update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	case "ctrl+z":
+		return update(t, m, tea.KeyMsg{Type: tea.KeyCtrlZ})
+	}
+	return update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+}
+
+func selectedLines(m model) []int {
+	var selected []int
+	for index, listItem := range m.list.Items() {
+		if i, ok := listItem.(item); ok && i.selected {
+			selected = append(selected, index)
+		}
+	}
+	return selected
+}
+
+func TestTranscriptionFillsTheList(t *testing.T) {
+	m := transcribedModel(t)
+	if m.loading {
+		t.Fatal("still loading after the transcript came back")
+	}
+	if len(m.list.Items()) != 3 || len(m.transcriptItems) != 3 {
+		t.Fatalf("got %d lines in the list and %d in the transcript, want 3", len(m.list.Items()), len(m.transcriptItems))
+	}
+}
+
+func TestEnterTogglesTheLineAndUndoBringsItBack(t *testing.T) {
+	m := transcribedModel(t)
+
+	m = press(t, m, "enter")
+	if got := selectedLines(m); len(got) != 1 || got[0] != 0 {
+		t.Fatalf("selected %v after enter, want [0]", got)
+	}
+	m = press(t, m, "enter")
+	if got := selectedLines(m); len(got) != 0 {
+		t.Fatalf("selected %v after enter twice, want none", got)
+	}
+	m = press(t, m, "ctrl+z")
+	if got := selectedLines(m); len(got) != 1 || got[0] != 0 {
+		t.Fatalf("selected %v after undo, want [0]", got)
+	}
+}
+
+func TestNumberKeysToggleTags(t *testing.T) {
+	m := transcribedModel(t)
+	m.tags = []string{"intro", "quote"}
+
+	m = press(t, m, "2")
+	if tags := m.list.Items()[0].(item).tags; len(tags) != 1 || tags[0] != "quote" {
+		t.Fatalf("tags are %v after 2, want [quote]", tags)
+	}
+	m = press(t, m, "2")
+	if tags := m.list.Items()[0].(item).tags; len(tags) != 0 {
+		t.Fatalf("tags are %v after 2 twice, want none", tags)
+	}
+	// There's no third tag, so 3 does nothing
+	m = press(t, m, "3")
+	if tags := m.list.Items()[0].(item).tags; len(tags) != 0 {
+		t.Fatalf("tags are %v after 3, want none", tags)
+	}
+}
+
+func TestMergeJoinsTheSameSpeakersLines(t *testing.T) {
+	m := transcribedModel(t)
+
+	m = press(t, m, "m")
+	if len(m.transcriptItems) != 2 {
+		t.Fatalf("got %d lines after merging, want 2", len(m.transcriptItems))
+	}
+	if first := m.transcriptItems[0]; first.Text != "First line Second line" || first.EndTime != "00:00:05.000" {
+		t.Errorf("merged line is %+v", first)
+	}
+
+	// The next line is someone else's
+	m = press(t, m, "m")
+	if len(m.transcriptItems) != 2 {
+		t.Errorf("got %d lines after merging with another speaker, want 2", len(m.transcriptItems))
+	}
+}
+
+func TestReadOnlyTurnsAwayEdits(t *testing.T) {
+	m := transcribedModel(t)
+	m.readOnly = true
+
+	for _, key := range []string{"e", "m", "s"} {
+		m = press(t, m, key)
+		if m.editingText || m.splitting || len(m.transcriptItems) != 3 {
+			t.Errorf("%s changed a read-only transcript", key)
+		}
+	}
+}
+
+func TestCompileNeedsASelection(t *testing.T) {
+	useFakeExecutor(t)
+	m := transcribedModel(t)
+
+	m = press(t, m, "c")
+	if m.loading {
+		t.Fatal("compile started with nothing selected")
+	}
+}
+
+func TestCompileRunsFFmpegOnTheSelection(t *testing.T) {
+	fake := useFakeExecutor(t)
+	m := transcribedModel(t)
+
+	m = press(t, m, "enter")
+	m = press(t, m, "c")
+	if !m.loading {
+		t.Fatal("compile did not start after selecting a line")
+	}
+
+	msg := compileVideoCmd(m.inputFile, m.list.Items(), m.compileOptions)()
+	if errMsg, ok := msg.(errorMsg); ok {
+		t.Fatal(errMsg.err)
+	}
+	if !fake.ran("ffmpeg", "between(t,0.000,2.000)") {
+		t.Errorf("ffmpeg was not asked to compile the selected line, ran %v", fake.calls)
+	}
+}
+
+func TestFailureScreenGoesBackToTheEditor(t *testing.T) {
+	m := transcribedModel(t)
+	m = m.fail(errors.New("something broke"))
+	if m.failure == nil {
+		t.Fatal("no error screen for a failure")
+	}
+
+	// Keys go to the error screen while it's up
+	m = press(t, m, "enter")
+	if len(selectedLines(m)) != 0 {
+		t.Error("enter selected a line behind the error screen")
+	}
+	m = press(t, m, "esc")
+	if m.failure != nil {
+		t.Error("esc did not go back to the editor")
+	}
+}
+
+func TestQuit(t *testing.T) {
+	m := transcribedModel(t)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if !updated.(model).quitting || cmd == nil {
+		t.Fatal("q did not quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("q did not send tea.Quit")
+	}
+}
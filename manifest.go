@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type manifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type manifest struct {
+	TspliceVersion string         `json:"tsplice_version"`
+	Created        string         `json:"created"`
+	Source         manifestFile   `json:"source"`
+	Outputs        []manifestFile `json:"outputs"`
+	Segments       []timeRange    `json:"segments"`
+	Options        compileOptions `json:"options"`
+	FFmpegArgs     []string       `json:"ffmpeg_args"`
+}
+
+func hashFile(path string) (manifestFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return manifestFile{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return manifestFile{}, err
+	}
+
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		absolute = path
+	}
+
+	return manifestFile{Path: absolute, SHA256: hex.EncodeToString(hash.Sum(nil)), Size: size}, nil
+}
+
+func manifestPath(outputFile string) string {
+	return strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".manifest.json"
+}
+
+// writeManifest records checksums and the exact parameters used for a compile,
+// so the output can be audited or reproduced later.
+func writeManifest(inputFile string, outputFiles []string, segments []segment, opts compileOptions, ffmpegArgs []string, created time.Time) error {
+	source, err := hashFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	m := manifest{
+		TspliceVersion: VERSION,
+		Created:        created.UTC().Format(time.RFC3339),
+		Source:         source,
+		Options:        opts,
+		FFmpegArgs:     ffmpegArgs,
+	}
+
+	for _, outputFile := range outputFiles {
+		output, err := hashFile(outputFile)
+		if err != nil {
+			return err
+		}
+		m.Outputs = append(m.Outputs, output)
+	}
+
+	for _, s := range segments {
+		m.Segments = append(m.Segments, timeRange{Start: s.start, End: s.end})
+	}
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(manifestPath(outputFiles[0]), append(content, '\n'), 0644)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// manEnvironment are the environment variables tsplice reads, for the man
+// page.
+var manEnvironment = [][2]string{
+	{"OPENAI_API_KEY", "OpenAI API key, asked for and saved to the system keyring when it isn't set"},
+	{"TSPLICE_API_BASE", "OpenAI-compatible server to transcribe with, the same as --api-base"},
+	{"TSPLICE_MODEL", "model the transcription server transcribes with, the same as --model"},
+	{"TSPLICE_API_KEY", "key sent to the --api-base server, if it needs one"},
+	{"AZURE_SPEECH_KEY", "Azure Speech key, for --provider=azure"},
+	{"AZURE_SPEECH_REGION", "Azure Speech region, for --provider=azure"},
+	{"DEEPGRAM_API_KEY", "Deepgram key, for --provider=deepgram"},
+	{"ASSEMBLYAI_API_KEY", "AssemblyAI key, for --provider=assemblyai"},
+	{"TSPLICE_PASSPHRASE", "passphrase for transcripts encrypted with --encrypt, instead of asking for it"},
+	{"SLACK_SIGNING_SECRET", "verifies the Slack requests tsplice bot takes"},
+	{"XDG_CONFIG_HOME", "folder the config is read from, ~/.config by default"},
+	{"LANG", "the editor is shown in its language unless --ui-lang is set"},
+}
+
+// defaultManFile is what tsplice man saves to without a file.
+const defaultManFile = "tsplice.1"
+
+func runMan(args []string) error {
+	fs := newCommandFlagSet("man")
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: tsplice man [file]")
+	}
+	manFile := defaultManFile
+	if len(positional) == 1 {
+		manFile = positional[0]
+	}
+	if err := os.MkdirAll(filepath.Dir(manFile), 0755); err != nil {
+		return fmt.Errorf("could not create %s: %w", filepath.Dir(manFile), err)
+	}
+	if err := os.WriteFile(manFile, []byte(manPage()), 0644); err != nil {
+		return fmt.Errorf("could not write man page: %w", err)
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved man page to "+manFile))
+	return nil
+}
+
+// manPage is the man page in roff, with the options and commands from the
+// same definitions their help is printed from.
+func manPage() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH TSPLICE 1 \"\" \"tsplice %s\" \"User Commands\"\n", VERSION)
+	b.WriteString(".SH NAME\ntsplice \\- splice and merge video files from the terminal\n")
+	b.WriteString(".SH SYNOPSIS\n.B tsplice\n[\\fIoptions\\fR] \\fIinput-file\\fR\n.br\n.B tsplice\n[\\fIoptions\\fR] \\fIcommand\\fR [\\fIcommand-options\\fR] [\\fIargs\\fR]\n")
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString(roffText("tsplice transcribes a video, or a YouTube URL, and lists its transcript line by line. Select the lines to keep, preview them, and compile them into a new video with ffmpeg, saved next to the original as _compiled.mp4. Commands do the same without the editor, or work with transcripts already made.") + "\n")
+
+	b.WriteString(".SH OPTIONS\n")
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		writeManFlag(&b, f)
+	})
+
+	b.WriteString(".SH COMMANDS\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, ".SS %s\n", c.name)
+		fmt.Fprintf(&b, ".B %s\n.PP\n", roffText(c.usage))
+		b.WriteString(roffText(commandDescription(c)) + "\n")
+		for _, f := range commandFlags(c) {
+			writeManFlag(&b, f)
+		}
+		if len(c.examples) > 0 {
+			b.WriteString(".PP\nExamples:\n.RS\n.nf\n")
+			for _, example := range c.examples {
+				b.WriteString(roffText(example) + "\n")
+			}
+			b.WriteString(".fi\n.RE\n")
+		}
+	}
+
+	b.WriteString(".SH ENVIRONMENT\n")
+	for _, variable := range manEnvironment {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", variable[0], roffText(upperFirst(variable[1])))
+	}
+	b.WriteString(".SH FILES\n.TP\n.I ~/.config/tsplice/config.toml\n")
+	b.WriteString(roffText("Defaults for the options above, profiles, tags, colors, and the rest of the settings described in the README. Flags passed on the command line win over it.") + "\n")
+	b.WriteString(".SH SEE ALSO\n.BR ffmpeg (1),\n.BR mpv (1),\n.BR yt-dlp (1)\n")
+	return b.String()
+}
+
+// commandDescription is what a command does, its summary when it has nothing
+// longer.
+func commandDescription(c command) string {
+	if c.description != "" {
+		return c.description
+	}
+	return upperFirst(c.summary) + "."
+}
+
+func writeManFlag(b *strings.Builder, f *flag.Flag) {
+	name, usage := flag.UnquoteUsage(f)
+	fmt.Fprintf(b, ".TP\n.B \\-\\-%s", roffText(f.Name))
+	if name != "" {
+		fmt.Fprintf(b, " \\fI%s\\fR", name)
+	}
+	b.WriteString("\n" + roffText(usage))
+	if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" {
+		b.WriteString(" (default " + roffText(f.DefValue) + ")")
+	}
+	b.WriteString("\n")
+}
+
+// roffText escapes text for roff, where backslashes start escapes, hyphens
+// are dashes, and a line starting with a dot or quote is a request.
+func roffText(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+	return text
+}
+
+func upperFirst(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// participantAudio is one person's own audio track from a meeting recording.
+type participantAudio struct {
+	name string
+	file string
+}
+
+// meetingRecording is a folder saved by Zoom or Google Meet, with the
+// recording itself and whatever it was saved alongside.
+type meetingRecording struct {
+	app          string
+	video        string
+	participants []participantAudio
+	chatFile     string
+}
+
+var (
+	// Zoom names separate audio files after the participant without spaces,
+	// followed by their user id, e.g. audioJaneDoe21234567890.m4a
+	zoomAudioRegex = regexp.MustCompile(`^audio(.+?)\d+\.m4a$`)
+	// Zoom chat lines are "00:01:23 From Jane Doe to Everyone:" followed by
+	// the message on the next line, or on the same line in older versions
+	zoomChatRegex = regexp.MustCompile(`^(\d{2}:\d{2}:\d{2})\s+From\s+(.+?)(?:\s+to\s+[^:]+)?\s*:\s*(.*)$`)
+	// Meet saves chat in the SBV format, a time range line and then "Name: message"
+	sbvTimeRegex = regexp.MustCompile(`^(\d+:\d{2}:\d{2}\.\d{3}),(\d+:\d{2}:\d{2}\.\d{3})$`)
+	camelRegex   = regexp.MustCompile(`([a-z])([A-Z])`)
+)
+
+// findMeetingRecording looks for the files Zoom and Meet save in a recording
+// folder, using the largest video as the recording.
+func findMeetingRecording(dir string) (meetingRecording, error) {
+	rec := meetingRecording{app: "Meet"}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return rec, err
+	}
+
+	var largest int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			if name == "Audio Record" {
+				rec.app = "Zoom"
+				rec.participants = zoomParticipants(filepath.Join(dir, name))
+			}
+			continue
+		}
+
+		lower := strings.ToLower(name)
+		switch {
+		case lower == "chat.txt" || lower == "meeting_saved_chat.txt":
+			rec.app = "Zoom"
+			rec.chatFile = filepath.Join(dir, name)
+		case strings.HasSuffix(lower, ".sbv") || strings.HasSuffix(lower, "chat.txt"):
+			rec.chatFile = filepath.Join(dir, name)
+		case slices.Contains(validExtensions, filepath.Ext(lower)):
+			if strings.HasPrefix(lower, "zoom_") {
+				rec.app = "Zoom"
+			}
+			if info, err := entry.Info(); err == nil && info.Size() > largest {
+				largest = info.Size()
+				rec.video = filepath.Join(dir, name)
+			}
+		}
+	}
+
+	if rec.video == "" {
+		return rec, fmt.Errorf("no recording found in '%s'", dir)
+	}
+	return rec, nil
+}
+
+func zoomParticipants(dir string) []participantAudio {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var participants []participantAudio
+	for _, entry := range entries {
+		matches := zoomAudioRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		participants = append(participants, participantAudio{
+			name: camelRegex.ReplaceAllString(matches[1], "$1 $2"),
+			file: filepath.Join(dir, entry.Name()),
+		})
+	}
+	return participants
+}
+
+// transcribeParticipants transcribes every participant's own audio and merges
+// the lines in order, so each one is attributed to whoever said it.
+func transcribeParticipants(participants []participantAudio, transcriber Transcriber) ([]TranscriptItem, error) {
+	var transcriptItems []TranscriptItem
+	for _, participant := range participants {
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Transcribing "+participant.name+"'s audio..."))
+		audioFile, err := extractAudio(participant.file, false, "", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		vttContent, _, err := transcriber.Transcribe(audioFile)
+		os.Remove(audioFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transcribe %s: %w", participant.name, err)
+		}
+
+		participantItems, err := parseVTT(vttContent)
+		if err != nil {
+			return nil, err
+		}
+		for _, transcriptItem := range participantItems {
+			if strings.TrimSpace(transcriptItem.Text) == "" {
+				continue
+			}
+			transcriptItem.Speaker = participant.name
+			transcriptItems = append(transcriptItems, transcriptItem)
+		}
+	}
+
+	sort.SliceStable(transcriptItems, func(a, b int) bool {
+		start, _ := parseTimeToSeconds(transcriptItems[a].StartTime)
+		other, _ := parseTimeToSeconds(transcriptItems[b].StartTime)
+		return start < other
+	})
+	return transcriptItems, nil
+}
+
+// parseMeetingChat reads a Zoom or Meet chat log into lines attributed to
+// whoever sent them. Messages are shown for a few seconds from when they
+// were sent, since chat logs don't have an end time.
+func parseMeetingChat(content string) []TranscriptItem {
+	var transcriptItems []TranscriptItem
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for index := 0; index < len(lines); index++ {
+		line := strings.TrimSpace(lines[index])
+
+		if matches := zoomChatRegex.FindStringSubmatch(line); matches != nil {
+			text := matches[3]
+			for text == "" && index+1 < len(lines) && strings.HasPrefix(lines[index+1], "\t") {
+				index++
+				text = strings.TrimSpace(lines[index])
+			}
+			start, _ := parseTimeToSeconds(matches[1])
+			transcriptItems = append(transcriptItems, TranscriptItem{
+				StartTime: formatTimestamp(start),
+				EndTime:   formatTimestamp(start + 5),
+				Text:      text,
+				Speaker:   matches[2],
+			})
+			continue
+		}
+
+		if matches := sbvTimeRegex.FindStringSubmatch(line); matches != nil && index+1 < len(lines) {
+			index++
+			speaker, text, found := strings.Cut(strings.TrimSpace(lines[index]), ":")
+			if !found {
+				speaker, text = "", speaker
+			}
+			start, _ := parseTimeToSeconds(matches[1])
+			transcriptItems = append(transcriptItems, TranscriptItem{
+				StartTime: formatTimestamp(start),
+				EndTime:   formatTimestamp(start + 5),
+				Text:      strings.TrimSpace(text),
+				Speaker:   strings.TrimSpace(speaker),
+			})
+		}
+	}
+	return transcriptItems
+}
+
+func meetingChatPath(vttFile string) string {
+	return strings.TrimSuffix(vttFile, ".vtt") + ".chat.vtt"
+}
+
+// saveMeetingChat writes the chat next to the transcript as its own VTT, so
+// it can be loaded as a second caption track alongside the recording.
+func saveMeetingChat(chatFile string, vttFile string) (int, error) {
+	content, err := os.ReadFile(chatFile)
+	if err != nil {
+		return 0, err
+	}
+	transcriptItems := parseMeetingChat(string(content))
+	if len(transcriptItems) == 0 {
+		return 0, nil
+	}
+	return len(transcriptItems), writeProjectFile(meetingChatPath(vttFile), []byte(formatVTT(transcriptItems)))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func formatRanges(segments []segment) string {
+	var ranges []string
+	for _, s := range segments {
+		ranges = append(ranges, formatTimestamp(s.start)+"-"+formatTimestamp(s.end))
+	}
+	return strings.Join(ranges, ",")
+}
+
+func compiledTitle(inputFile string, opts compileOptions) string {
+	if opts.Title != "" {
+		return opts.Title
+	}
+	return strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile)) + " (compiled)"
+}
+
+// outputMetadataArgs tags the compiled file with where it came from, so the
+// provenance survives once the clip is shared around.
+func outputMetadataArgs(inputFile string, segments []segment, created time.Time, opts compileOptions) []string {
+	args := []string{
+		"-metadata", "title=" + compiledTitle(inputFile, opts),
+		"-metadata", "creation_time=" + created.UTC().Format(time.RFC3339),
+		"-metadata", "comment=" + fmt.Sprintf("Compiled with tsplice %s from %s", VERSION, filepath.Base(inputFile)),
+		"-metadata", "source=" + filepath.Base(inputFile),
+		"-metadata", "tsplice_version=" + VERSION,
+		"-metadata", "tsplice_ranges=" + formatRanges(segments),
+		"-movflags", "use_metadata_tags",
+	}
+	if len(opts.Attendees) > 0 {
+		args = append(args, "-metadata", "tsplice_attendees="+strings.Join(opts.Attendees, ", "))
+	}
+	return args
+}
+
+func writeXMPSidecar(outputFile, inputFile string, segments []segment, created time.Time, opts compileOptions) error {
+	var ranges strings.Builder
+	for _, s := range segments {
+		fmt.Fprintf(&ranges, "      <rdf:li>%s-%s</rdf:li>\n", formatTimestamp(s.start), formatTimestamp(s.end))
+	}
+
+	xmp := fmt.Sprintf(`<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:tsplice="https://github.com/aschmelyun/tsplice/ns/1.0/">
+   <dc:title><rdf:Alt><rdf:li xml:lang="x-default">%s</rdf:li></rdf:Alt></dc:title>
+   <dc:source>%s</dc:source>
+   <xmp:CreateDate>%s</xmp:CreateDate>
+   <xmp:CreatorTool>tsplice %s</xmp:CreatorTool>
+   <tsplice:Ranges>
+    <rdf:Seq>
+%s    </rdf:Seq>
+   </tsplice:Ranges>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>
+`,
+		html.EscapeString(compiledTitle(inputFile, opts)),
+		html.EscapeString(filepath.Base(inputFile)),
+		created.UTC().Format(time.RFC3339),
+		VERSION,
+		ranges.String(),
+	)
+
+	return os.WriteFile(strings.TrimSuffix(outputFile, filepath.Ext(outputFile))+".xmp", []byte(xmp), 0644)
+}
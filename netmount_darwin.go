@@ -0,0 +1,23 @@
+//go:build darwin
+
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// networkFilesystems are the filesystem names macOS mounts network shares
+// with, counting FUSE since that's how sshfs and rclone mount.
+var networkFilesystems = []string{"nfs", "smbfs", "afpfs", "webdav", "ftp", "osxfuse", "macfuse"}
+
+func onNetworkMount(path string) bool {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return false
+	}
+	name := strings.TrimRight(string(stat.Fstypename[:]), "\x00")
+	return slices.Contains(networkFilesystems, name)
+}
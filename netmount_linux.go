@@ -0,0 +1,33 @@
+//go:build linux
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// networkFilesystems are the statfs magic numbers of filesystems that read
+// over the network, counting FUSE since that's how sshfs and rclone mount.
+var networkFilesystems = []uint32{
+	unix.NFS_SUPER_MAGIC,
+	unix.SMB_SUPER_MAGIC,
+	unix.SMB2_SUPER_MAGIC,
+	unix.CIFS_SUPER_MAGIC,
+	unix.AFS_SUPER_MAGIC,
+	unix.AFS_FS_MAGIC,
+	unix.CEPH_SUPER_MAGIC,
+	unix.CODA_SUPER_MAGIC,
+	unix.V9FS_MAGIC,
+	unix.FUSE_SUPER_MAGIC,
+}
+
+func onNetworkMount(path string) bool {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return false
+	}
+	for _, magic := range networkFilesystems {
+		if uint32(stat.Type) == magic {
+			return true
+		}
+	}
+	return false
+}
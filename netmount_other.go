@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+// onNetworkMount can't tell on this platform, so sources are always read in place.
+func onNetworkMount(path string) bool {
+	return false
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// noteSection is a run of transcript lines shown under one frame.
+type noteSection struct {
+	start float64
+	frame string
+	lines []TranscriptItem
+}
+
+// groupSections starts a new section at the first line that begins at least
+// interval seconds after the start of the previous one.
+func groupSections(transcriptItems []TranscriptItem, interval float64) []noteSection {
+	var sections []noteSection
+	for _, transcriptItem := range transcriptItems {
+		start, err := parseTimeToSeconds(transcriptItem.StartTime)
+		if err != nil {
+			continue
+		}
+		if len(sections) == 0 || start-sections[len(sections)-1].start >= interval {
+			sections = append(sections, noteSection{start: start})
+		}
+		last := &sections[len(sections)-1]
+		last.lines = append(last.lines, transcriptItem)
+	}
+	return sections
+}
+
+// sectionText joins a section's lines into paragraphs, one per speaker turn.
+func sectionText(section noteSection) [][2]string {
+	var paragraphs [][2]string
+	for _, line := range section.lines {
+		if len(paragraphs) > 0 && paragraphs[len(paragraphs)-1][0] == line.Speaker {
+			paragraphs[len(paragraphs)-1][1] += " " + line.Text
+			continue
+		}
+		paragraphs = append(paragraphs, [2]string{line.Speaker, line.Text})
+	}
+	return paragraphs
+}
+
+func notesMarkdown(title string, attendees []string, sections []noteSection) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if len(attendees) > 0 {
+		fmt.Fprintf(&b, "*Attendees: %s*\n\n", strings.Join(attendees, ", "))
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "## %s\n\n![%s](%s)\n\n", formatDuration(section.start), formatDuration(section.start), section.frame)
+		for _, paragraph := range sectionText(section) {
+			if paragraph[0] != "" {
+				fmt.Fprintf(&b, "**%s:** ", paragraph[0])
+			}
+			b.WriteString(paragraph[1] + "\n\n")
+		}
+	}
+	return b.String()
+}
+
+func notesHTML(title string, attendees []string, sections []noteSection) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+img { width: 100%%; border-radius: 4px; }
+h2 { color: #888; font-size: 1rem; margin-top: 3rem; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+`, html.EscapeString(title), html.EscapeString(title))
+	if len(attendees) > 0 {
+		fmt.Fprintf(&b, "<p><em>Attendees: %s</em></p>\n", html.EscapeString(strings.Join(attendees, ", ")))
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "<h2>%s</h2>\n<img src=\"%s\" alt=\"\">\n", formatDuration(section.start), html.EscapeString(section.frame))
+		for _, paragraph := range sectionText(section) {
+			b.WriteString("<p>")
+			if paragraph[0] != "" {
+				fmt.Fprintf(&b, "<strong>%s:</strong> ", html.EscapeString(paragraph[0]))
+			}
+			b.WriteString(html.EscapeString(paragraph[1]) + "</p>\n")
+		}
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// exportNotes writes an illustrated document of the transcript to a folder
+// next to the video, with a frame from the start of every section. The
+// document is titled after the video unless a meeting is given.
+func exportNotes(inputFile string, transcriptItems []TranscriptItem, format string, interval float64, meeting calendarEvent) (string, error) {
+	sections := groupSections(transcriptItems, interval)
+	if len(sections) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	dir := filepath.Join(filepath.Dir(inputFile), basename+"_notes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// Frames are only thumbnails, so they come from the preview proxy if there is one
+	frameSource := cachedProxy(inputFile)
+	for index := range sections {
+		sections[index].frame = fmt.Sprintf("frame_%03d.jpg", index+1)
+		if err := extractFrame(frameSource, sections[index].start, filepath.Join(dir, sections[index].frame)); err != nil {
+			return "", err
+		}
+	}
+
+	title := basename
+	if meeting.Summary != "" {
+		title = meeting.Summary + ", " + meeting.Start.Local().Format("January 2, 2006")
+	}
+
+	notesFile := filepath.Join(dir, "notes.md")
+	content := notesMarkdown(title, meeting.Attendees, sections)
+	if format == "html" {
+		notesFile = filepath.Join(dir, "notes.html")
+		content = notesHTML(title, meeting.Attendees, sections)
+	}
+
+	if err := os.WriteFile(notesFile, []byte(content), 0644); err != nil {
+		return "", err
+	}
+
+	return notesFile, nil
+}
+
+// notesFlags are the options of tsplice notes.
+type notesFlags struct {
+	format   string
+	interval float64
+	calendar string
+}
+
+func (f *notesFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "md", "Document format, md or html")
+	fs.Float64Var(&f.interval, "interval", 60, "Seconds between screenshots")
+	fs.StringVar(&f.calendar, "calendar", "", "ICS file to title the notes after the meeting the video was recorded in")
+}
+
+func runNotes(args []string) error {
+	fs := newCommandFlagSet("notes")
+	var flags notesFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice notes [options] <input-file>")
+	}
+	if flags.format != "md" && flags.format != "html" {
+		return fmt.Errorf("--format must be md or html")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(vttPath(inputFile)); os.IsNotExist(err) && transcribesWithOpenAI() {
+		if err := setupAPIKey(); err != nil {
+			return err
+		}
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+	if err != nil {
+		return err
+	}
+
+	var meeting calendarEvent
+	if flags.calendar != "" {
+		event, ok, err := meetingFor(flags.calendar, inputFile)
+		if err != nil {
+			return err
+		}
+		if ok {
+			meeting = event
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Recorded during "+event.Summary))
+		} else {
+			fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render("No meeting in the calendar matches when this was recorded."))
+		}
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Extracting screenshots with ffmpeg..."))
+	notesFile, err := exportNotes(inputFile, transcriptItems, flags.format, flags.interval, meeting)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved notes to "+notesFile))
+	return nil
+}
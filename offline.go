@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"net"
+)
+
+// offline is set with --offline or offline = true in the config. Everything
+// that would send audio or transcript text off this machine checks it first,
+// so the mode can't be bypassed by a feature that forgot to ask.
+var offline bool
+
+// requireOnline refuses what would leave the machine while offline.
+func requireOnline(what string) error {
+	if offline {
+		return fmt.Errorf("offline mode is on, refusing to send %s off this machine", what)
+	}
+	return nil
+}
+
+// isLoopbackAddr reports whether a listen address is only reachable from
+// this machine.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
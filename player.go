@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// previewPlayer is the program p previews lines in, set with --player or
+// preview_player in the config. It can be a path, as long as it's one of the
+// previewPlayers.
+var previewPlayer = "mpv"
+
+// previewPlayers are the arguments each player takes to play only a span of
+// a video and close at its end.
+var previewPlayers = map[string]func(start, end float64, inputFile string) []string{
+	"mpv": func(start, end float64, inputFile string) []string {
+		return []string{"--start=" + formatTimestamp(start), "--end=" + formatTimestamp(end), inputFile}
+	},
+	"vlc": func(start, end float64, inputFile string) []string {
+		return []string{"--start-time=" + formatSeconds(start), "--stop-time=" + formatSeconds(end), "--play-and-exit", inputFile}
+	},
+	"ffplay": func(start, end float64, inputFile string) []string {
+		return []string{"-hide_banner", "-loglevel", "error", "-ss", formatSeconds(start), "-t", formatSeconds(end - start), "-autoexit", inputFile}
+	},
+}
+
+// audioOnlyArgs keep each of the previewPlayers from opening a window, for
+// previews that only play the audio.
+var audioOnlyArgs = map[string][]string{
+	"mpv":    {"--no-video", "--really-quiet"},
+	"vlc":    {"--no-video", "--intf=dummy"},
+	"ffplay": {"-nodisp"},
+}
+
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
+
+// playerName is which of the previewPlayers a player is, going by its
+// program's name, like vlc for /Applications/VLC.app/Contents/MacOS/VLC.
+func playerName(player string) string {
+	return strings.TrimSuffix(strings.ToLower(filepath.Base(player)), ".exe")
+}
+
+// setPreviewPlayer picks the player for previews, failing on one tsplice
+// doesn't know how to play a span in.
+func setPreviewPlayer(player string) error {
+	if player == "" {
+		return nil
+	}
+	if _, ok := previewPlayers[playerName(player)]; !ok {
+		return fmt.Errorf("can't preview with %s, use one of %s", player, strings.Join(slices.Sorted(maps.Keys(previewPlayers)), ", "))
+	}
+	previewPlayer = player
+	return nil
+}
+
+// previewCommand plays a span of a video in the preview player.
+func previewCommand(inputFile, startTime, endTime string) *exec.Cmd {
+	start, _ := parseTimeToSeconds(startTime)
+	end, _ := parseTimeToSeconds(endTime)
+	return exec.Command(previewPlayer, previewPlayers[playerName(previewPlayer)](start, end, inputFile)...)
+}
+
+// audioPreviewCommand plays a span of a video without its picture, in the
+// preview player, or whichever of the others is installed when it isn't.
+func audioPreviewCommand(inputFile, startTime, endTime string) (*exec.Cmd, error) {
+	start, _ := parseTimeToSeconds(startTime)
+	end, _ := parseTimeToSeconds(endTime)
+
+	known := slices.Sorted(maps.Keys(previewPlayers))
+	players := []string{previewPlayer}
+	for _, name := range known {
+		if name != playerName(previewPlayer) {
+			players = append(players, name)
+		}
+	}
+	for _, player := range players {
+		if checkDependency(player) {
+			name := playerName(player)
+			return exec.Command(player, append(slices.Clone(audioOnlyArgs[name]), previewPlayers[name](start, end, inputFile)...)...), nil
+		}
+	}
+	return nil, fmt.Errorf("playing the audio needs one of %s, and none are installed", strings.Join(known, ", "))
+}
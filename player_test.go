@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// installPlayers puts stand-ins for players on an otherwise empty PATH.
+func installPlayers(t *testing.T, players ...string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stand-in players are shell scripts")
+	}
+	dir := t.TempDir()
+	for _, player := range players {
+		if err := os.WriteFile(filepath.Join(dir, player), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+
+	previous := previewPlayer
+	t.Cleanup(func() { previewPlayer = previous })
+}
+
+func TestAudioPreviewUsesThePreviewPlayer(t *testing.T) {
+	installPlayers(t, "mpv", "vlc")
+	previewPlayer = "vlc"
+
+	cmd, err := audioPreviewCommand("talk.mp4", "00:00:01.000", "00:00:03.000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(cmd.Path) != "vlc" || !slices.Contains(cmd.Args, "--no-video") || !slices.Contains(cmd.Args, "--stop-time=3.000") {
+		t.Errorf("audio preview runs %v, want vlc without video until 3s", cmd.Args)
+	}
+}
+
+func TestAudioPreviewFallsBackToAnInstalledPlayer(t *testing.T) {
+	installPlayers(t, "ffplay")
+	previewPlayer = "mpv"
+
+	cmd, err := audioPreviewCommand("talk.mp4", "00:00:01.000", "00:00:03.000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(cmd.Path) != "ffplay" || !slices.Contains(cmd.Args, "-nodisp") {
+		t.Errorf("audio preview runs %v, want ffplay without a window", cmd.Args)
+	}
+}
+
+func TestAudioPreviewNeedsAPlayer(t *testing.T) {
+	installPlayers(t)
+
+	if _, err := audioPreviewCommand("talk.mp4", "00:00:01.000", "00:00:03.000"); err == nil {
+		t.Error("no error without a player installed")
+	}
+}
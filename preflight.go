@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// extractedAudioRate is roughly how many bytes a second the extracted mp3 takes
+	extractedAudioRate = 16_000
+	// stemRate is how many bytes a second a 16-bit 48kHz stereo WAV stem takes
+	stemRate = 192_000
+	// preflightMargin covers re-encodes coming out larger than the source
+	preflightMargin = 1.2
+)
+
+// checkWritable makes sure a file can be created in dir, since finding out
+// from ffmpeg only happens after the slow part is done.
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".tsplice-")
+	if err != nil {
+		return fmt.Errorf("can't write to %s: %w", dir, err)
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return nil
+}
+
+// checkFreeSpace fails when dir has less than need bytes free. Filesystems
+// that can't report their free space are assumed to have enough.
+func checkFreeSpace(dir string, need int64) error {
+	free, err := freeSpace(dir)
+	if err != nil || free >= need {
+		return nil
+	}
+	return fmt.Errorf("not enough disk space in %s, about %s is needed but only %s is free", dir, formatBytes(need), formatBytes(free))
+}
+
+func checkOutputDir(dir string, need int64) error {
+	if err := checkWritable(dir); err != nil {
+		return err
+	}
+	return checkFreeSpace(dir, need)
+}
+
+// preflightExtract checks there's room for the extracted audio and the
+// transcript, in the folder the transcript is saved to next to the video.
+func preflightExtract(inputFile string, vttFile string) error {
+	duration, err := probeDuration(inputFile)
+	if err != nil {
+		return nil
+	}
+	return checkOutputDir(filepath.Dir(vttFile), int64(duration*extractedAudioRate))
+}
+
+// preflightCompile estimates the size of the compiled video, and stems if
+// asked for, from the source's bitrate and the length of the selection.
+func preflightCompile(inputFile string, segments []segment, opts compileOptions) error {
+	dir := compiledDir(inputFile)
+
+	var duration float64
+	for _, s := range segments {
+		duration += s.end - s.start
+	}
+
+	need := opts.MaxSize
+	if need == 0 {
+		info, err := os.Stat(inputFile)
+		sourceDuration, probeErr := probeDuration(inputFile)
+		if err != nil || probeErr != nil || sourceDuration <= 0 {
+			return checkWritable(dir)
+		}
+		need = int64(float64(info.Size()) / sourceDuration * duration * preflightMargin)
+	}
+
+	if opts.Stems {
+		tracks := 1
+		if probe, err := probeStreams(inputFile); err == nil {
+			tracks = max(1, countStreams(probe, "audio"))
+		}
+		need += int64(duration * stemRate * float64(tracks))
+	}
+
+	return checkOutputDir(dir, need)
+}
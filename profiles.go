@@ -0,0 +1,151 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// profile bundles the automatic editing settings that suit a kind of video,
+// picked with --profile for the editor and tsplice tighten alike.
+type profile struct {
+	// MaxGap and Pad are tsplice tighten's --max-gap and --pad
+	MaxGap float64 `toml:"max_gap"`
+	Pad    float64 `toml:"pad"`
+	// Fillers are cut by tsplice tighten and counted on the stats screen
+	Fillers []string `toml:"fillers"`
+	// TrimSilence turns on --trim-silence, trimming below SilenceThreshold
+	TrimSilence      bool   `toml:"trim_silence"`
+	SilenceThreshold string `toml:"silence_threshold"`
+	// Loudness normalizes the output to an integrated loudness in LUFS, 0
+	// leaves it as it was recorded
+	Loudness   float64 `toml:"loudness"`
+	VideoCodec string  `toml:"video_codec"`
+	AudioCodec string  `toml:"audio_codec"`
+	CRF        int     `toml:"crf"`
+}
+
+// builtinProfiles can be used without any config. A profile in the config
+// with the same name replaces one of these whole.
+var builtinProfiles = map[string]profile{
+	// Conversations run at podcast loudness, with room left for a breath
+	// between speakers
+	"podcast": {
+		MaxGap:           0.75,
+		Pad:              0.15,
+		Fillers:          []string{"um", "uh", "uhm", "erm", "ah", "hmm", "mm", "you know", "i mean"},
+		TrimSilence:      true,
+		SilenceThreshold: "-40dB",
+		Loudness:         -16,
+		CRF:              23,
+	},
+	// Screen recordings are cut tight, at a quality that keeps small text
+	// readable
+	"screencast": {
+		MaxGap:           0.4,
+		Pad:              0.08,
+		Fillers:          tightenFillers,
+		TrimSilence:      true,
+		SilenceThreshold: speechThreshold,
+		Loudness:         -14,
+		CRF:              18,
+	},
+	// Lectures keep their pauses for emphasis, and are picked up by room
+	// mics with more noise under the speech
+	"lecture": {
+		MaxGap:           1.5,
+		Pad:              0.25,
+		Fillers:          []string{"um", "uh", "erm", "ah"},
+		SilenceThreshold: "-45dB",
+		Loudness:         -18,
+		CRF:              26,
+	},
+}
+
+// profiles are the built-in profiles and the config's, set once the config
+// is read.
+var profiles = builtinProfiles
+
+// activeProfile is the name of the profile picked with --profile or profile
+// in the config, empty for none.
+var activeProfile string
+
+// baseEncoding is how compiles encode when their profile doesn't say, from
+// video_codec, audio_codec, and crf in the config.
+var baseEncoding profile
+
+// setProfiles adds the config's profiles to the built-in ones and picks the
+// active one, failing on a name that isn't either.
+func setProfiles(configs map[string]profile, name string) error {
+	profiles = maps.Clone(builtinProfiles)
+	for profileName, p := range configs {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("profile %s in %s %w", profileName, configPath(), err)
+		}
+		profiles[strings.ToLower(profileName)] = p
+	}
+
+	if _, err := findProfile(name); err != nil {
+		return err
+	}
+	activeProfile = strings.ToLower(name)
+	return nil
+}
+
+// findProfile looks up a profile by name, encoding the way the config does
+// where it doesn't say, and only that when the name is empty.
+func findProfile(name string) (profile, error) {
+	if name == "" {
+		return baseEncoding, nil
+	}
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return profile{}, fmt.Errorf("there's no %s profile, use one of %s", name, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+	}
+	p.VideoCodec = cmp.Or(p.VideoCodec, baseEncoding.VideoCodec)
+	p.AudioCodec = cmp.Or(p.AudioCodec, baseEncoding.AudioCodec)
+	p.CRF = cmp.Or(p.CRF, baseEncoding.CRF)
+	return p, nil
+}
+
+func (p profile) validate() error {
+	if p.MaxGap < 0 || p.Pad < 0 {
+		return fmt.Errorf("can't have a negative max_gap or pad")
+	}
+	if p.SilenceThreshold != "" {
+		level, ok := strings.CutSuffix(p.SilenceThreshold, "dB")
+		if _, err := strconv.ParseFloat(level, 64); !ok || err != nil {
+			return fmt.Errorf("has silence_threshold %q, it should be in decibels like -35dB", p.SilenceThreshold)
+		}
+	}
+	// The range loudnorm accepts
+	if p.Loudness != 0 && (p.Loudness < -70 || p.Loudness > -5) {
+		return fmt.Errorf("has loudness %g, it should be between -70 and -5 LUFS", p.Loudness)
+	}
+	if p.CRF < 0 || p.CRF > 51 {
+		return fmt.Errorf("has crf %d, it should be between 0 and 51", p.CRF)
+	}
+	return nil
+}
+
+// checkEncoders fails when ffmpeg can't encode with the codecs the profile
+// names, once the encoders have been probed.
+func (p profile) checkEncoders() error {
+	for _, codec := range []string{p.VideoCodec, p.AudioCodec} {
+		if codec != "" && !hasEncoder(codec) {
+			return fmt.Errorf("ffmpeg wasn't built with %s, which compiles are set to encode with", codec)
+		}
+	}
+	return nil
+}
+
+// apply sets how the profile encodes a compile.
+func (p profile) apply(opts *compileOptions) {
+	opts.Loudness = p.Loudness
+	opts.VideoCodec = p.VideoCodec
+	opts.AudioCodec = p.AudioCodec
+	opts.CRF = p.CRF
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+// renderProgress follows a step that renders with ffmpeg, going by the media
+// time each run reports with -progress. A step can take several runs, like
+// both passes of a size limited compile, so total is the time of them all.
+type renderProgress struct {
+	mu sync.Mutex
+	// total is how many seconds of media the step renders, 0 until known
+	total float64
+	// done is the seconds rendered by runs that finished, and current the
+	// seconds the one going now is at
+	done    float64
+	current float64
+	started time.Time
+}
+
+// expect sets how many seconds of media the step renders, starting its clock.
+// Nothing is shown for the step until it's set.
+func (p *renderProgress) expect(total float64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total, p.done, p.current = total, 0, 0
+	p.started = time.Now()
+}
+
+func (p *renderProgress) report(seconds float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = seconds
+}
+
+// add counts seconds as rendered without a run, like a segment that's
+// already in the cache.
+func (p *renderProgress) add(seconds float64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += seconds
+	p.current = 0
+}
+
+// finishRun moves a run that ended into the step's done time.
+func (p *renderProgress) finishRun() {
+	p.add(p.current)
+}
+
+// status is how far through the step is and how long it should take
+// still, going by how fast it's been so far. ok is false until there's
+// enough to tell.
+func (p *renderProgress) status() (fraction float64, eta time.Duration, ok bool) {
+	if p == nil {
+		return 0, 0, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total <= 0 {
+		return 0, 0, false
+	}
+
+	fraction = min((p.done+p.current)/p.total, 1)
+	if elapsed := time.Since(p.started); fraction > 0 {
+		eta = time.Duration(float64(elapsed) * (1 - fraction) / fraction)
+	}
+	return fraction, eta, true
+}
+
+// progressRunner is an Executor that can report how far an ffmpeg run has
+// got, in seconds of media rendered.
+type progressRunner interface {
+	RunProgress(report func(seconds float64), name string, args ...string) error
+}
+
+// runFFmpeg runs ffmpeg as part of a step, reporting to its progress when
+// there is one to report to.
+func runFFmpeg(p *renderProgress, args ...string) error {
+	runner, ok := execute.(progressRunner)
+	if p == nil || !ok {
+		return execute.Run("ffmpeg", args...)
+	}
+	err := runner.RunProgress(p.report, "ffmpeg", args...)
+	p.finishRun()
+	return err
+}
+
+// RunProgress has ffmpeg write its progress to stdout, which nothing else
+// in a Run reads, and passes on the output time of each update.
+func (systemExecutor) RunProgress(report func(seconds float64), name string, args ...string) error {
+	if err := checkFFmpegArgs(args); err != nil {
+		return err
+	}
+	cmd := exec.Command(name, append([]string{"-progress", "pipe:1", "-nostats"}, args...)...)
+	cmd.Stderr = toolLog(name, args)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := startChild(cmd); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// Microseconds despite the name, and N/A before the first frame
+		value, ok := strings.CutPrefix(scanner.Text(), "out_time_us=")
+		if microseconds, err := strconv.ParseInt(value, 10, 64); ok && err == nil && microseconds >= 0 {
+			report(float64(microseconds) / 1e6)
+		}
+	}
+	return waitChild(cmd)
+}
+
+// newProgressBar is the bar shown under the spinner while ffmpeg renders.
+func newProgressBar() progress.Model {
+	return progress.New(progress.WithDefaultGradient(), progress.WithWidth(40))
+}
+
+// progressView is the bar for what's rendering, with the time it has left,
+// or nothing while there's no telling yet.
+func (m model) progressView() string {
+	fraction, eta, ok := m.progress.status()
+	if !ok {
+		return ""
+	}
+	view := "\n  " + m.progressBar.ViewAs(fraction)
+	if fraction > 0 && fraction < 1 {
+		view += DimTextStyle.Render("  " + trf("%s left", formatDuration(eta.Seconds())))
+	}
+	return view
+}
@@ -0,0 +1,660 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transcriptionProvider is the speech to text backend set with --provider or
+// provider in the config. Empty is OpenAI.
+var transcriptionProvider string
+
+// transcriptionProviders are what --provider takes, whisper and command being
+// the same as --local and --stt-command.
+var transcriptionProviders = []string{"openai", "azure", "deepgram", "assemblyai", "whisper", "command"}
+
+// openAIBase is where transcription requests go unless --api-base says
+// otherwise.
+const openAIBase = "https://api.openai.com/v1"
+
+// apiBase and transcriptionModel are the server and model OpenAI-style
+// transcription requests are sent to, set with --api-base and --model, their
+// TSPLICE_API_BASE and TSPLICE_MODEL variables, or api_base and model in the
+// config. Any server with an OpenAI-compatible /audio/transcriptions endpoint
+// works, like LocalAI, go-whisper, or Groq.
+var (
+	apiBase            = openAIBase
+	transcriptionModel = "whisper-1"
+)
+
+// setAPIBase points transcription requests at another server, failing on
+// anything that isn't an http or https URL.
+func setAPIBase(base string) error {
+	if base == "" {
+		return nil
+	}
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q isn't a server URL, use one like http://localhost:8080/v1 for --api-base", base)
+	}
+	apiBase = strings.TrimSuffix(base, "/")
+	return nil
+}
+
+// transcriptionServer names where OpenAI-style transcription requests go,
+// the host of any server other than OpenAI's.
+func transcriptionServer() string {
+	u, err := url.Parse(apiBase)
+	if apiBase == openAIBase || err != nil {
+		return "OpenAI"
+	}
+	return u.Host
+}
+
+// providerKeys are the environment variables each hosted provider's key is
+// read from. OpenAI's is also kept in the system keyring.
+var providerKeys = map[string]string{
+	"openai":     "OPENAI_API_KEY",
+	"azure":      "AZURE_SPEECH_KEY",
+	"deepgram":   "DEEPGRAM_API_KEY",
+	"assemblyai": "ASSEMBLYAI_API_KEY",
+}
+
+// assemblyAIPoll is how often a queued AssemblyAI transcript is checked on.
+const assemblyAIPoll = 3 * time.Second
+
+// checkProvider fails early when the provider can't be used, rather than
+// after the audio has been extracted.
+func checkProvider() error {
+	switch transcriptionProvider {
+	case "", "openai", "command":
+		return nil
+	case "whisper":
+		return checkWhisper()
+	case "azure":
+		if os.Getenv("AZURE_SPEECH_REGION") == "" {
+			return fmt.Errorf("AZURE_SPEECH_REGION environment variable is not set")
+		}
+	}
+	_, err := providerKey(transcriptionProvider)
+	return err
+}
+
+// providerSegment is a line of a transcript, however a provider splits them.
+type providerSegment struct {
+	start, end float64
+	text       string
+	speaker    string
+	// confidence is from 0 to 1, or -1 when the provider doesn't say
+	confidence float64
+}
+
+// buildTranscript turns what a provider returned into VTT and the details
+// that VTT can't hold, the same for every provider.
+func buildTranscript(language string, segments []providerSegment, words []Word) (string, transcriptDetails) {
+	details := transcriptDetails{Language: language, Confidence: map[string]float64{}}
+
+	var transcriptItems []TranscriptItem
+	for _, s := range segments {
+		text := strings.TrimSpace(s.text)
+		if text == "" {
+			continue
+		}
+		transcriptItem := TranscriptItem{
+			StartTime: formatTimestamp(s.start),
+			EndTime:   formatTimestamp(s.end),
+			Text:      text,
+			Speaker:   s.speaker,
+		}
+		transcriptItems = append(transcriptItems, transcriptItem)
+		if s.confidence >= 0 {
+			details.Confidence[transcriptItem.StartTime] = s.confidence
+		}
+	}
+
+	for _, w := range words {
+		if w.Text = strings.TrimSpace(w.Text); w.Text != "" {
+			details.Words = append(details.Words, w)
+		}
+	}
+
+	return formatVTT(transcriptItems), details
+}
+
+// speakerLabel names a diarized speaker the way OpenAI-style labels look, so
+// they can be named with N the same way.
+func speakerLabel(speaker int) string {
+	return fmt.Sprintf("SPEAKER_%02d", speaker)
+}
+
+// providerKey reads a provider's API key, saying where to set it if it's
+// missing.
+func providerKey(provider string) (string, error) {
+	name := providerKeys[provider]
+	if key := os.Getenv(name); key != "" {
+		return key, nil
+	}
+	return "", fmt.Errorf("%s environment variable is not set", name)
+}
+
+// multipartAudio writes an audio file and form fields as a multipart body,
+// returning it with its content type.
+func multipartAudio(fileField, audioFile string, fields [][2]string) (*bytes.Buffer, string, error) {
+	file, err := os.Open(audioFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	var b bytes.Buffer
+	writer := multipart.NewWriter(&b)
+
+	part, err := writer.CreateFormFile(fileField, filepath.Base(audioFile))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, "", fmt.Errorf("failed to copy file: %w", err)
+	}
+	for _, field := range fields {
+		writer.WriteField(field[0], field[1])
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close writer: %w", err)
+	}
+	return &b, writer.FormDataContentType(), nil
+}
+
+// sendAudio makes a request that uploads audio and decodes the JSON answer.
+// The upload is over once the request is written, and the API is done once
+// the response starts coming back, which are timed separately.
+func sendAudio(req *http.Request, out any) error {
+	started := time.Now()
+	var uploaded, answered time.Time
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		WroteRequest:         func(httptrace.WroteRequestInfo) { uploaded = time.Now() },
+		GotFirstResponseByte: func() { answered = time.Now() },
+	}))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if !uploaded.IsZero() && !answered.IsZero() {
+		addTiming("upload", uploaded.Sub(started))
+		addTiming("transcription api", answered.Sub(uploaded))
+	}
+	defer recordTiming("parse", time.Now())
+
+	return decodeResponse(resp, out)
+}
+
+// getJSON fetches and decodes a JSON answer, for providers that are polled.
+func getJSON(url string, headers map[string]string, out any) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	return decodeResponse(resp, out)
+}
+
+// apiError is a provider answering with anything but a 200, keeping the
+// status for the error screen to tell what went wrong.
+type apiError struct {
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.status, e.body)
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &apiError{status: resp.StatusCode, body: string(body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	return nil
+}
+
+type openAITranscriber struct {
+	language string
+	prompt   string
+}
+
+type openAITranscription struct {
+	Language string `json:"language"`
+	Segments []struct {
+		Start      float64 `json:"start"`
+		End        float64 `json:"end"`
+		Text       string  `json:"text"`
+		AvgLogprob float64 `json:"avg_logprob"`
+	} `json:"segments"`
+	Words []struct {
+		Word  string  `json:"word"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	} `json:"words"`
+}
+
+func (t openAITranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	if err := requireOnline("audio to " + transcriptionServer() + " for transcription"); err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	info, err := os.Stat(audioFile)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	var transcription openAITranscription
+	if info.Size() > uploadLimit {
+		transcription, err = t.transcribeChunks(audioFile)
+	} else {
+		transcription, err = t.request(audioFile)
+	}
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	var segments []providerSegment
+	for _, s := range transcription.Segments {
+		segments = append(segments, providerSegment{start: s.Start, end: s.End, text: s.Text, confidence: math.Exp(s.AvgLogprob)})
+	}
+	var words []Word
+	for _, w := range transcription.Words {
+		words = append(words, Word{Start: w.Start, End: w.End, Text: w.Word})
+	}
+	vttContent, details := buildTranscript(transcription.Language, segments, words)
+	return vttContent, details, nil
+}
+
+// request uploads one file, no bigger than uploadLimit.
+func (t openAITranscriber) request(audioFile string) (openAITranscription, error) {
+	var transcription openAITranscription
+	// Other servers get their own key, if they need one at all, so OpenAI's
+	// is never sent to them
+	apiKey := os.Getenv("TSPLICE_API_KEY")
+	if apiBase == openAIBase {
+		key, err := providerKey("openai")
+		if err != nil {
+			return transcription, err
+		}
+		apiKey = key
+	}
+
+	// verbose_json is the only format that includes word timestamps
+	fields := [][2]string{
+		{"model", transcriptionModel},
+		{"response_format", "verbose_json"},
+		{"timestamp_granularities[]", "word"},
+		{"timestamp_granularities[]", "segment"},
+	}
+	if t.language != "" && t.language != "auto" {
+		fields = append(fields, [2]string{"language", t.language})
+	}
+	if t.prompt != "" {
+		fields = append(fields, [2]string{"prompt", t.prompt})
+	}
+	body, contentType, err := multipartAudio("file", audioFile, fields)
+	if err != nil {
+		return transcription, err
+	}
+
+	req, err := http.NewRequest("POST", apiBase+"/audio/transcriptions", body)
+	if err != nil {
+		return transcription, fmt.Errorf("failed to create request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	err = sendAudio(req, &transcription)
+	return transcription, err
+}
+
+func (t openAITranscriber) WithLanguage(language string) Transcriber {
+	t.language = language
+	return t
+}
+
+// azureTranscriber uses Azure's fast transcription API, in the region from
+// AZURE_SPEECH_REGION.
+type azureTranscriber struct {
+	language string
+}
+
+type azureTranscription struct {
+	Phrases []struct {
+		Speaker            int     `json:"speaker"`
+		OffsetMilliseconds float64 `json:"offsetMilliseconds"`
+		DurationMs         float64 `json:"durationMilliseconds"`
+		Text               string  `json:"text"`
+		Locale             string  `json:"locale"`
+		Confidence         float64 `json:"confidence"`
+		Words              []struct {
+			Text               string  `json:"text"`
+			OffsetMilliseconds float64 `json:"offsetMilliseconds"`
+			DurationMs         float64 `json:"durationMilliseconds"`
+		} `json:"words"`
+	} `json:"phrases"`
+}
+
+func (t azureTranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	if err := requireOnline("audio to Azure for transcription"); err != nil {
+		return "", transcriptDetails{}, err
+	}
+	apiKey, err := providerKey("azure")
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+	region := os.Getenv("AZURE_SPEECH_REGION")
+	if region == "" {
+		return "", transcriptDetails{}, fmt.Errorf("AZURE_SPEECH_REGION environment variable is not set")
+	}
+
+	definition := map[string]any{"diarization": map[string]any{"enabled": true, "maxSpeakers": 10}}
+	// Azure takes locales like en-US, and identifies the language itself
+	// when none is given
+	if strings.Contains(t.language, "-") {
+		definition["locales"] = []string{t.language}
+	}
+	definitionJSON, err := json.Marshal(definition)
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+	body, contentType, err := multipartAudio("audio", audioFile, [][2]string{{"definition", string(definitionJSON)}})
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	endpoint := fmt.Sprintf("https://%s.api.cognitive.microsoft.com/speechtotext/transcriptions:transcribe?api-version=2024-11-15", region)
+	req, err := http.NewRequest("POST", endpoint, body)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", apiKey)
+	req.Header.Set("Content-Type", contentType)
+
+	var transcription azureTranscription
+	if err := sendAudio(req, &transcription); err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	language := ""
+	var segments []providerSegment
+	var words []Word
+	for _, p := range transcription.Phrases {
+		start := p.OffsetMilliseconds / 1000
+		segments = append(segments, providerSegment{start: start, end: start + p.DurationMs/1000, text: p.Text, speaker: speakerLabel(p.Speaker), confidence: p.Confidence})
+		for _, w := range p.Words {
+			wordStart := w.OffsetMilliseconds / 1000
+			words = append(words, Word{Start: wordStart, End: wordStart + w.DurationMs/1000, Text: w.Text})
+		}
+		if language == "" {
+			language = p.Locale
+		}
+	}
+	vttContent, details := buildTranscript(language, segments, words)
+	return vttContent, details, nil
+}
+
+func (t azureTranscriber) WithLanguage(language string) Transcriber {
+	t.language = language
+	return t
+}
+
+// deepgramTranscriber uses Deepgram's pre-recorded API, with its utterances
+// as lines.
+type deepgramTranscriber struct {
+	language string
+	prompt   string
+}
+
+type deepgramTranscription struct {
+	Results struct {
+		Channels []struct {
+			DetectedLanguage string `json:"detected_language"`
+			Alternatives     []struct {
+				Words []struct {
+					Word           string  `json:"word"`
+					PunctuatedWord string  `json:"punctuated_word"`
+					Start          float64 `json:"start"`
+					End            float64 `json:"end"`
+				} `json:"words"`
+			} `json:"alternatives"`
+		} `json:"channels"`
+		Utterances []struct {
+			Start      float64 `json:"start"`
+			End        float64 `json:"end"`
+			Transcript string  `json:"transcript"`
+			Confidence float64 `json:"confidence"`
+			Speaker    int     `json:"speaker"`
+		} `json:"utterances"`
+	} `json:"results"`
+}
+
+func (t deepgramTranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	if err := requireOnline("audio to Deepgram for transcription"); err != nil {
+		return "", transcriptDetails{}, err
+	}
+	apiKey, err := providerKey("deepgram")
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	query := url.Values{"model": {"nova-3"}, "smart_format": {"true"}, "utterances": {"true"}, "diarize": {"true"}}
+	if t.language != "" && t.language != "auto" {
+		query.Set("language", t.language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+	// The prompt's words are the closest thing Deepgram has to a prompt
+	for _, term := range strings.Fields(t.prompt) {
+		query.Add("keyterm", strings.Trim(term, ",.;:"))
+	}
+
+	file, err := os.Open(audioFile)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	req, err := http.NewRequest("POST", "https://api.deepgram.com/v1/listen?"+query.Encode(), file)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+apiKey)
+	req.Header.Set("Content-Type", "audio/mpeg")
+
+	var transcription deepgramTranscription
+	if err := sendAudio(req, &transcription); err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	var segments []providerSegment
+	for _, u := range transcription.Results.Utterances {
+		segments = append(segments, providerSegment{start: u.Start, end: u.End, text: u.Transcript, speaker: speakerLabel(u.Speaker), confidence: u.Confidence})
+	}
+	language := ""
+	var words []Word
+	if channels := transcription.Results.Channels; len(channels) > 0 {
+		language = channels[0].DetectedLanguage
+		if len(channels[0].Alternatives) > 0 {
+			for _, w := range channels[0].Alternatives[0].Words {
+				text := w.PunctuatedWord
+				if text == "" {
+					text = w.Word
+				}
+				words = append(words, Word{Start: w.Start, End: w.End, Text: text})
+			}
+		}
+	}
+	if language == "" {
+		language = t.language
+	}
+	vttContent, details := buildTranscript(language, segments, words)
+	return vttContent, details, nil
+}
+
+func (t deepgramTranscriber) WithLanguage(language string) Transcriber {
+	t.language = language
+	return t
+}
+
+// assemblyAITranscriber uploads the audio to AssemblyAI, then waits for the
+// transcript, which is split into sentences for its lines.
+type assemblyAITranscriber struct {
+	language string
+	prompt   string
+}
+
+type assemblyAITranscript struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Error        string `json:"error"`
+	LanguageCode string `json:"language_code"`
+	Words        []struct {
+		Text  string  `json:"text"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	} `json:"words"`
+}
+
+type assemblyAISentences struct {
+	Sentences []struct {
+		Text       string  `json:"text"`
+		Start      float64 `json:"start"`
+		End        float64 `json:"end"`
+		Confidence float64 `json:"confidence"`
+		Speaker    string  `json:"speaker"`
+	} `json:"sentences"`
+}
+
+func (t assemblyAITranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	if err := requireOnline("audio to AssemblyAI for transcription"); err != nil {
+		return "", transcriptDetails{}, err
+	}
+	apiKey, err := providerKey("assemblyai")
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+	headers := map[string]string{"Authorization": apiKey}
+
+	file, err := os.Open(audioFile)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	req, err := http.NewRequest("POST", "https://api.assemblyai.com/v2/upload", file)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", apiKey)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	var upload struct {
+		UploadURL string `json:"upload_url"`
+	}
+	if err := sendAudio(req, &upload); err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	request := map[string]any{"audio_url": upload.UploadURL, "speaker_labels": true}
+	if t.language != "" && t.language != "auto" {
+		request["language_code"] = t.language
+	} else {
+		request["language_detection"] = true
+	}
+	if terms := strings.Fields(t.prompt); len(terms) > 0 {
+		request["word_boost"] = terms
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return "", transcriptDetails{}, err
+	}
+	req, err = http.NewRequest("POST", "https://api.assemblyai.com/v2/transcript", bytes.NewReader(requestJSON))
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", transcriptDetails{}, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	var transcript assemblyAITranscript
+	if err := decodeResponse(resp, &transcript); err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	waiting := time.Now()
+	for transcript.Status != "completed" {
+		if transcript.Status == "error" {
+			return "", transcriptDetails{}, fmt.Errorf("AssemblyAI couldn't transcribe the audio: %s", transcript.Error)
+		}
+		time.Sleep(assemblyAIPoll)
+		if err := getJSON("https://api.assemblyai.com/v2/transcript/"+transcript.ID, headers, &transcript); err != nil {
+			return "", transcriptDetails{}, err
+		}
+	}
+	addTiming("transcription api", time.Since(waiting))
+
+	var sentences assemblyAISentences
+	if err := getJSON("https://api.assemblyai.com/v2/transcript/"+transcript.ID+"/sentences", headers, &sentences); err != nil {
+		return "", transcriptDetails{}, err
+	}
+
+	// Speakers come back as A, B, and so on
+	var segments []providerSegment
+	for _, s := range sentences.Sentences {
+		speaker := ""
+		if s.Speaker != "" {
+			if n, err := strconv.Atoi(s.Speaker); err == nil {
+				speaker = speakerLabel(n)
+			} else {
+				speaker = speakerLabel(int(strings.ToUpper(s.Speaker)[0] - 'A'))
+			}
+		}
+		segments = append(segments, providerSegment{start: s.Start / 1000, end: s.End / 1000, text: s.Text, speaker: speaker, confidence: s.Confidence})
+	}
+	var words []Word
+	for _, w := range transcript.Words {
+		words = append(words, Word{Start: w.Start / 1000, End: w.End / 1000, Text: w.Text})
+	}
+	vttContent, details := buildTranscript(transcript.LanguageCode, segments, words)
+	return vttContent, details, nil
+}
+
+func (t assemblyAITranscriber) WithLanguage(language string) Transcriber {
+	t.language = language
+	return t
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// proxyHeight is how tall preview proxies are, small enough for players to start
+// and seek instantly. Sources no taller than proxyMinHeight play as is.
+const (
+	proxyHeight    = 540
+	proxyMinHeight = 720
+)
+
+type proxyReadyMsg struct {
+	file string
+	err  error
+}
+
+// cacheDir is where files that can always be made again are kept.
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tsplice"), nil
+}
+
+// cacheKey names a source's files in the cache, from its path, size, and
+// modification time so an edited source gets new ones.
+func cacheKey(inputFile string) (string, error) {
+	absolute, err := filepath.Abs(inputFile)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(absolute)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%d\x00%d", absolute, info.Size(), info.ModTime().UnixNano()))
+	return hex.EncodeToString(sum[:12]), nil
+}
+
+// proxyPath is where a video's preview proxy is cached.
+func proxyPath(inputFile string) (string, error) {
+	key, err := cacheKey(inputFile)
+	if err != nil {
+		return "", err
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "proxies", key+".mp4"), nil
+}
+
+// cachedProxy returns the video's proxy if one has been made, otherwise the
+// video itself. One left from before encrypting isn't used.
+func cachedProxy(inputFile string) string {
+	if encryptAtRest {
+		return inputFile
+	}
+	if proxyFile, err := proxyPath(inputFile); err == nil {
+		if _, err := os.Stat(proxyFile); err == nil {
+			return proxyFile
+		}
+	}
+	return inputFile
+}
+
+// needsProxy reports whether the first video stream is big enough that
+// previewing it directly would be slow.
+func needsProxy(probe probeResult) bool {
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "video" {
+			return stream.Height > proxyMinHeight
+		}
+	}
+	return false
+}
+
+// makeProxyCmd encodes the proxy in the background, with a keyframe every
+// second so seeks land right away. It's written under another name until
+// it's done, so a proxy in the cache is never a partial one.
+func makeProxyCmd(inputFile, proxyFile string) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := os.Stat(proxyFile); err == nil {
+			return proxyReadyMsg{file: proxyFile}
+		}
+		if err := os.MkdirAll(filepath.Dir(proxyFile), 0755); err != nil {
+			return proxyReadyMsg{err: err}
+		}
+
+		args := []string{"-y", "-i", inputFile,
+			"-map", "0:v:0", "-map", "0:a:0?",
+			"-vf", fmt.Sprintf("scale=-2:%d,format=yuv420p", proxyHeight),
+			"-force_key_frames", "expr:gte(t,n_forced)",
+		}
+		args = append(args, videoEncoderArgs(false)...)
+		if videoEncoder == "libx264" {
+			args = append(args, "-preset", "veryfast", "-crf", "28")
+		} else {
+			args = append(args, "-b:v", "2M")
+		}
+		args = append(args, "-c:a", "aac", "-b:a", "96k", "-movflags", "+faststart")
+		err := renderTo(proxyFile, func(partial string) error {
+			if err := execute.Run("ffmpeg", append(args, partial)...); err != nil {
+				return fmt.Errorf("failed to make preview proxy: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return proxyReadyMsg{err: err}
+		}
+		return proxyReadyMsg{file: proxyFile}
+	}
+}
+
+// previewFile is what previews play, the proxy once it's ready.
+func (m model) previewFile() string {
+	if m.proxyFile != "" {
+		return m.proxyFile
+	}
+	return m.mediaFile()
+}
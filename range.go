@@ -0,0 +1,124 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rangeBounds is the start times of the first and last lines of a range
+// marked from the line starting at from to the cursor, in either direction.
+func rangeBounds(from float64, l list.Model) (low, high float64, ok bool) {
+	cursor, ok := l.SelectedItem().(item)
+	if !ok {
+		return 0, 0, false
+	}
+	return min(from, cursor.start), max(from, cursor.start), true
+}
+
+// inRange reports whether a line is part of the range being marked.
+func (d itemDelegate) inRange(l list.Model, i item) bool {
+	if !d.ranging {
+		return false
+	}
+	low, high, ok := rangeBounds(d.rangeFrom, l)
+	return ok && i.start >= low && i.start <= high
+}
+
+// startRange starts marking a range of lines at the cursor, which grows as
+// the cursor moves away from it.
+func (m model) startRange() model {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return m
+	}
+	m.ranging, m.rangeFrom = true, i.start
+	m.list.SetDelegate(m.newItemDelegate())
+	return m
+}
+
+func (m model) stopRange() model {
+	m.ranging = false
+	m.list.SetDelegate(m.newItemDelegate())
+	return m
+}
+
+// rangeIndexes is every visible line in the range being marked, so lines a
+// filter hides in between are left alone.
+func (m model) rangeIndexes() []int {
+	low, high, ok := rangeBounds(m.rangeFrom, m.list)
+	if !ok {
+		return nil
+	}
+	items := m.list.Items()
+	var indexes []int
+	for _, index := range visibleIndexes(m.list) {
+		if i, ok := items[index].(item); ok && i.start >= low && i.start <= high {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// selectAction is what a, A, and i do to each line they act on, and the
+// status once they have, for every visible line or only a range.
+func selectAction(keyName string, ranged bool) (apply func(i item) item, done func(count int) string, ok bool) {
+	switch keyName {
+	case "a":
+		apply = func(i item) item { i.selected = true; return i }
+		done = func(count int) string { return trf("Selected %d visible segments.", count) }
+		if ranged {
+			done = func(count int) string { return trf("Selected %d segments.", count) }
+		}
+	case "A":
+		apply = func(i item) item { i.selected = false; return i }
+		done = func(count int) string { return trf("Deselected %d visible segments.", count) }
+		if ranged {
+			done = func(count int) string { return trf("Deselected %d segments.", count) }
+		}
+	case "i":
+		apply = func(i item) item { i.selected = !i.selected; return i }
+		done = func(count int) string { return trf("Inverted the selection of %d visible segments.", count) }
+		if ranged {
+			done = func(count int) string { return trf("Inverted the selection of %d segments.", count) }
+		}
+	default:
+		return nil, nil, false
+	}
+	return apply, done, true
+}
+
+// updateSelectAll selects, deselects, or inverts every visible line.
+func (m model) updateSelectAll(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	apply, done, _ := selectAction(msg.String(), false)
+	indexes := visibleIndexes(m.list)
+	m.statuses = append(m.statuses, done(len(indexes)))
+	return m.applyTo(indexes, apply)
+}
+
+// updateRange moves the cursor to mark a range of lines, until it's selected,
+// deselected, or inverted in one go, or esc lets go of it.
+func (m model) updateRange(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keyName := msg.String()
+	switch keyName {
+	case "esc":
+		return m.stopRange(), nil
+	case "enter", " ", "V":
+		keyName = "a"
+	}
+
+	if apply, done, ok := selectAction(keyName, true); ok {
+		indexes := m.rangeIndexes()
+		m = m.stopRange()
+		m.statuses = append(m.statuses, done(len(indexes)))
+		return m.applyTo(indexes, apply)
+	}
+
+	keys := m.list.KeyMap
+	if key.Matches(msg, keys.CursorUp, keys.CursorDown, keys.PrevPage, keys.NextPage, keys.GoToStart, keys.GoToEnd) {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const redactedText = "[redacted]"
+
+var (
+	emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phoneRegex = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)|\b\d{3})[\s.-]?\d{3}[\s.-]?\d{4}\b`)
+)
+
+type redactionsFoundMsg struct {
+	terms []string
+	err   error
+}
+
+// findContacts returns every email address and phone number in the transcript.
+func findContacts(transcriptItems []TranscriptItem) []string {
+	var terms []string
+	for _, transcriptItem := range transcriptItems {
+		terms = append(terms, emailRegex.FindAllString(transcriptItem.Text, -1)...)
+		terms = append(terms, phoneRegex.FindAllString(transcriptItem.Text, -1)...)
+	}
+	slices.Sort(terms)
+	return slices.Compact(terms)
+}
+
+// findNames asks the chat API for the names of people in the transcript,
+// which no regex finds reliably.
+func findNames(transcriptItems []TranscriptItem) ([]string, error) {
+	var b strings.Builder
+	for _, transcriptItem := range transcriptItems {
+		if transcriptItem.Speaker != "" {
+			b.WriteString(transcriptItem.Speaker + ": ")
+		}
+		b.WriteString(transcriptItem.Text + "\n")
+	}
+
+	system := "You find personal information in transcripts. Reply with a JSON object with \"names\", an array of every " +
+		"name of a person mentioned or speaking, exactly as written in the transcript, including first names on their own."
+	reply, err := chatCompletion(system, b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Names []string `json:"names"`
+	}
+	if err := json.Unmarshal([]byte(reply), &result); err != nil {
+		return nil, fmt.Errorf("could not read names: %w", err)
+	}
+
+	var names []string
+	for _, name := range result.Names {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func findRedactionsCmd(transcriptItems []TranscriptItem, contacts []string) tea.Cmd {
+	return func() tea.Msg {
+		names, err := findNames(transcriptItems)
+		return redactionsFoundMsg{terms: append(contacts, names...), err: err}
+	}
+}
+
+// findRedactions looks for personal information once the transcript is
+// ready. Without --redact it only points out what it found.
+func (m model) findRedactions(transcriptItems []TranscriptItem) (model, tea.Cmd) {
+	contacts := findContacts(transcriptItems)
+	if !m.redact {
+		if len(contacts) > 0 {
+			m.statuses = append(m.statuses, trf("Found %d email addresses or phone numbers, run with --redact to remove them from exports.", len(contacts)))
+		}
+		return m, nil
+	}
+	m.redactions = contacts
+	if m.redactNames {
+		return m, findRedactionsCmd(transcriptItems, contacts)
+	}
+
+	m.statuses = append(m.statuses, trf("Redacting %d email addresses and phone numbers from exports and the compiled audio.", len(contacts)))
+	return m, nil
+}
+
+// redactText replaces every term in the text, ignoring case. Terms only
+// match whole words, so redacting "Al" leaves "also" alone.
+func redactText(text string, terms []string) string {
+	for _, term := range terms {
+		termRegex := regexp.MustCompile(`(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(term) + `($|[^\pL\pN])`)
+		text = termRegex.ReplaceAllString(text, "${1}"+redactedText+"${2}")
+	}
+	return text
+}
+
+// redactItems redacts the text of each line, and drops speaker names that
+// are themselves redacted.
+func redactItems(transcriptItems []TranscriptItem, terms []string) []TranscriptItem {
+	if len(terms) == 0 {
+		return transcriptItems
+	}
+	redacted := make([]TranscriptItem, len(transcriptItems))
+	for index, transcriptItem := range transcriptItems {
+		transcriptItem.Text = redactText(transcriptItem.Text, terms)
+		if redactText(transcriptItem.Speaker, terms) != transcriptItem.Speaker {
+			transcriptItem.Speaker = ""
+		}
+		redacted[index] = transcriptItem
+	}
+	return redacted
+}
+
+func redactionToken(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
+
+// matchTerms finds each run of words that spells out a term.
+func matchTerms(words []Word, terms []string, found func(first, last int)) {
+	for _, term := range terms {
+		var tokens []string
+		for _, field := range strings.Fields(term) {
+			if token := redactionToken(field); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		for first := 0; first+len(tokens) <= len(words); first++ {
+			matched := true
+			for offset, token := range tokens {
+				if redactionToken(words[first+offset].Text) != token {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				found(first, first+len(tokens)-1)
+			}
+		}
+	}
+}
+
+// redactionRanges returns the source time ranges to bleep for every term.
+func redactionRanges(words []Word, terms []string) []timeRange {
+	var ranges []timeRange
+	matchTerms(words, terms, func(first, last int) {
+		ranges = append(ranges, timeRange{Start: words[first].Start, End: words[last].End})
+	})
+	return ranges
+}
+
+// redactWords replaces the words spelling out any term.
+func redactWords(words []Word, terms []string) []Word {
+	if len(terms) == 0 {
+		return words
+	}
+	redacted := slices.Clone(words)
+	matchTerms(words, terms, func(first, last int) {
+		for index := first; index <= last; index++ {
+			redacted[index].Text = ""
+		}
+		redacted[first].Text = redactedText
+	})
+
+	kept := redacted[:0]
+	for _, word := range redacted {
+		if word.Text != "" {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
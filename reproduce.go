@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// reproduceFlags are the options of tsplice reproduce.
+type reproduceFlags struct {
+	force bool
+}
+
+func (f *reproduceFlags) define(fs *flag.FlagSet) {
+	fs.BoolVar(&f.force, "force", false, "Compile even if the source no longer matches its recorded checksum")
+}
+
+func runReproduce(args []string) error {
+	fs := newCommandFlagSet("reproduce")
+	var flags reproduceFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice reproduce [options] <manifest.json>")
+	}
+
+	content, err := os.ReadFile(positional[0])
+	if err != nil {
+		return fmt.Errorf("could not read manifest: %w", err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return fmt.Errorf("could not parse manifest: %w", err)
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Verifying source "+m.Source.Path+"..."))
+	source, err := hashFile(m.Source.Path)
+	if err != nil {
+		return fmt.Errorf("could not read source: %w", err)
+	}
+	if source.SHA256 != m.Source.SHA256 {
+		if !flags.force {
+			return fmt.Errorf("source checksum does not match the manifest, use --force to compile anyway")
+		}
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Source checksum does not match the manifest, continuing anyway."))
+	}
+
+	if m.TspliceVersion != VERSION {
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render(fmt.Sprintf("Manifest was written by tsplice %s, this is %s.", m.TspliceVersion, VERSION)))
+	}
+
+	outputFile, err := compileFromManifest(m.Source.Path, m)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved output to "+outputFile))
+	return nil
+}
+
+// compileFromManifest compiles a source again with the segments and options
+// a manifest recorded.
+func compileFromManifest(inputFile string, m manifest) (string, error) {
+	var segments []segment
+	for _, s := range m.Segments {
+		segments = append(segments, segment{start: s.Start, end: s.End})
+	}
+
+	if err := preflightCompile(inputFile, segments, m.Options); err != nil {
+		return "", err
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Compiling %d segments with ffmpeg...", len(segments))))
+	outputFile, err := compileSegments(inputFile, segments, m.Options)
+	if err != nil {
+		return "", err
+	}
+
+	if m.Options.Verify {
+		warnings, err := verifyOutput(outputFile, expectedDuration(segments, m.Options))
+		if err != nil {
+			return "", err
+		}
+		for _, warning := range warnings {
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Warning: "+warning))
+		}
+	}
+
+	return outputFile, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// applyRules runs a Starlark script's rule(segment) function against every
+// list item. Returning True selects the segment, False deselects it, and None
+// leaves it alone.
+func applyRules(rulesFile string, items []list.Item) ([]list.Item, int, error) {
+	thread := &starlark.Thread{
+		Name:  "rules",
+		Print: func(_ *starlark.Thread, _ string) {},
+	}
+
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, rulesFile, nil, nil)
+	if err != nil {
+		return items, 0, fmt.Errorf("could not load rules: %w", err)
+	}
+
+	rule, ok := globals["rule"].(starlark.Callable)
+	if !ok {
+		return items, 0, fmt.Errorf("rules file must define a rule(segment) function")
+	}
+
+	changed := 0
+	for index, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok {
+			continue
+		}
+
+		s := segmentFromItem(i)
+
+		segment := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"index":    starlark.MakeInt(index),
+			"text":     starlark.String(i.title),
+			"start":    starlark.Float(s.start),
+			"end":      starlark.Float(s.end),
+			"duration": starlark.Float(s.end - s.start),
+			"selected": starlark.Bool(i.selected),
+		})
+
+		result, err := starlark.Call(thread, rule, starlark.Tuple{segment}, nil)
+		if err != nil {
+			return items, changed, fmt.Errorf("rule failed on segment %d: %w", index, err)
+		}
+
+		switch result := result.(type) {
+		case starlark.NoneType:
+			continue
+		case starlark.Bool:
+			if bool(result) != i.selected {
+				i.selected = bool(result)
+				items[index] = i
+				changed++
+			}
+		default:
+			return items, changed, fmt.Errorf("rule must return True, False, or None, got %s", result.Type())
+		}
+	}
+
+	return items, changed, nil
+}
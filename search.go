@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// searchResult is a match along with the video of the store it came from.
+type searchResult struct {
+	storeMatch
+	source string
+}
+
+// searchFlags are the options of tsplice search.
+type searchFlags struct {
+	limit    int
+	supercut bool
+	semantic bool
+}
+
+func (f *searchFlags) define(fs *flag.FlagSet) {
+	fs.IntVar(&f.limit, "limit", 0, "Most matches to list (default 100, or 10 with --semantic)")
+	fs.BoolVar(&f.supercut, "supercut", false, "Compile matches from every video into one supercut, instead of opening one")
+	fs.BoolVar(&f.semantic, "semantic", false, "Rank lines by how close they are in meaning to the query, using the embeddings saved with --embed")
+}
+
+func runSearch(args []string) error {
+	fs := newCommandFlagSet("search")
+	var flags searchFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 1 || len(positional) > 2 || strings.TrimSpace(positional[0]) == "" {
+		return fmt.Errorf("usage: tsplice search [options] <query> [folder]")
+	}
+	query := positional[0]
+	folder := "."
+	if len(positional) == 2 {
+		folder = positional[1]
+	}
+	if flags.limit <= 0 {
+		flags.limit = 100
+		if flags.semantic {
+			flags.limit = 10
+		}
+	}
+
+	storeFiles, err := findStores(folder)
+	if err != nil {
+		return err
+	}
+	if len(storeFiles) == 0 {
+		return fmt.Errorf("no transcripts in %s have been indexed yet, open a video in tsplice to index it", folder)
+	}
+
+	var embedder Embedder
+	var queryVector []float32
+	if flags.semantic {
+		embedder = defaultEmbedder()
+		if _, ok := embedder.(openAIEmbedder); ok {
+			if err := setupAPIKey(); err != nil {
+				return err
+			}
+		}
+		vectors, err := embedder.Embed([]string{query})
+		if err != nil {
+			return fmt.Errorf("could not embed the query: %w", err)
+		}
+		queryVector = decodeVector(encodeVector(vectors[0]))
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Searching transcripts in %s for %q...", folder, query)))
+
+	var results []searchResult
+	unembedded := 0
+	for _, storeFile := range storeFiles {
+		// Keyword matches come in order, so the rest can't make the list
+		if !flags.semantic && len(results) > flags.limit {
+			break
+		}
+		matches, source, err := searchStore(storeFile, query, embedder, queryVector)
+		if errors.Is(err, errNotStore) {
+			continue
+		} else if errors.Is(err, errNoEmbeddings) {
+			unembedded++
+			continue
+		} else if err != nil {
+			fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render("Skipped "+storeFile+": "+err.Error()))
+			continue
+		}
+		for _, match := range matches {
+			results = append(results, searchResult{storeMatch: match, source: source})
+		}
+	}
+	if flags.semantic {
+		sort.SliceStable(results, func(a, b int) bool { return results[a].score > results[b].score })
+	}
+	truncated := len(results) > flags.limit
+	results = groupResults(results[:min(len(results), flags.limit)])
+
+	videos := 0
+	for index, result := range results {
+		if index == 0 || result.source != results[index-1].source {
+			videos++
+			fmt.Println(BulletStyle.Render("│"))
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render(filepath.Base(result.source)) + DimTextStyle.Render("  "+filepath.Dir(result.source)))
+		}
+		label := fmt.Sprintf("%3d. %s  ", index+1, formatDuration(result.start))
+		if flags.semantic {
+			label += fmt.Sprintf("%3.0f%%  ", result.score*100)
+		}
+		if result.speaker != "" {
+			label += result.speaker + ": "
+		}
+		fmt.Println(BulletStyle.Render("├────") + DimTextStyle.Render(label) + highlightMatch(result.text))
+	}
+
+	fmt.Println(BulletStyle.Render("│"))
+	if unembedded > 0 {
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render(fmt.Sprintf("%d transcripts have no embeddings yet, open their videos with --embed to search them by meaning.", unembedded)))
+	}
+	if len(results) == 0 {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("No matches."))
+		return nil
+	}
+	if truncated {
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render(fmt.Sprintf("Listing the first %d matches, raise --limit for more.", flags.limit)))
+	}
+
+	if flags.supercut {
+		return compileSearchResults(results, query)
+	}
+
+	// Nothing to pick from when the list is being piped somewhere
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Found %d matches in %d videos.", len(results), videos)))
+		return nil
+	}
+	fmt.Print(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Found %d matches in %d videos, open one (1-%d) or press enter to quit: ", len(results), videos, len(results))))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil
+	}
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > len(results) {
+		return fmt.Errorf("pick a match between 1 and %d", len(results))
+	}
+
+	chosen := results[choice-1]
+	if _, err := os.Stat(chosen.source); err != nil {
+		return fmt.Errorf("could not find %s, it may have been moved since it was indexed", chosen.source)
+	}
+	return openInEditor(chosen.source, "--at="+formatTimestamp(chosen.start))
+}
+
+// findStores walks a folder for the stores saved next to transcripts,
+// leaving out hidden folders.
+func findStores(folder string) ([]string, error) {
+	var storeFiles []string
+	err := filepath.WalkDir(folder, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			// A folder that can't be read shouldn't stop the rest being searched
+			if entry != nil && entry.IsDir() && path != folder {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() && path != folder && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() && filepath.Ext(path) == ".db" {
+			storeFiles = append(storeFiles, path)
+		}
+		return nil
+	})
+	return storeFiles, err
+}
+
+// compileSearchResults asks which of the matches go in the supercut, or takes
+// them all when there's no one to ask.
+func compileSearchResults(results []searchResult, query string) error {
+	picks := make([]int, len(results))
+	for index := range picks {
+		picks[index] = index
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Print(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Matches to compile, like 1,3-5, or press enter for all %d: ", len(results))))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer != "" {
+			var err error
+			if picks, err = parsePicks(answer, len(results)); err != nil {
+				return err
+			}
+		}
+	}
+
+	var clips []supercutClip
+	for _, pick := range picks {
+		result := results[pick]
+		if _, err := os.Stat(result.source); err != nil {
+			return fmt.Errorf("could not find %s, it may have been moved since it was indexed", result.source)
+		}
+		clips = append(clips, supercutClip{source: result.source, start: result.start, end: result.end})
+	}
+
+	outputFile := supercutPath(query)
+	if err := compileSupercut(clips, outputFile); err != nil {
+		return err
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Saved a supercut of %d clips to %s", len(clips), outputFile)))
+	return nil
+}
+
+// groupResults keeps each video's matches together, in the order its best
+// match ranked.
+func groupResults(results []searchResult) []searchResult {
+	rank := map[string]int{}
+	for _, result := range results {
+		if _, ok := rank[result.source]; !ok {
+			rank[result.source] = len(rank)
+		}
+	}
+	sort.SliceStable(results, func(a, b int) bool { return rank[results[a].source] < rank[results[b].source] })
+	return results
+}
+
+// searchStore searches one store for the query's words, or by meaning when
+// there's an embedder and the query's embedding.
+func searchStore(storeFile, query string, embedder Embedder, queryVector []float32) ([]storeMatch, string, error) {
+	s, err := openStoreReadOnly(storeFile)
+	if err != nil {
+		return nil, "", err
+	}
+	defer s.Close()
+
+	source, err := s.source()
+	if err != nil {
+		return nil, "", errNotStore
+	}
+	var matches []storeMatch
+	if embedder != nil {
+		matches, err = s.semanticSearch(embedder.Name(), queryVector)
+	} else {
+		matches, err = s.search(query)
+	}
+	return matches, source, err
+}
+
+// highlightMatch styles the words a search matched, which the store marks
+// between \x02 and \x03.
+func highlightMatch(text string) string {
+	var b strings.Builder
+	for index, part := range strings.Split(text, "\x02") {
+		if index == 0 {
+			b.WriteString(TextStyle.Render(part))
+			continue
+		}
+		matched, rest, _ := strings.Cut(part, "\x03")
+		b.WriteString(TagStyle.Render(matched) + TextStyle.Render(rest))
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// segmentCacheAge is how long an encoded segment is kept after it was last
+// used in a compile.
+const segmentCacheAge = 14 * 24 * time.Hour
+
+// canCacheSegments reports whether a compile can be put together from
+// segments encoded on their own. Extra streams, clips, two-pass encodes, and
+// loudness, which is measured over all of it, need the whole selection in
+// one filter graph.
+func canCacheSegments(opts compileOptions) bool {
+	return opts.CacheSegments && !encryptAtRest && !opts.KeepStreams && opts.Intro == "" && opts.Outro == "" && (opts.MaxSize == 0 || opts.Draft) && opts.Loudness == 0
+}
+
+// segmentArgs encode one segment, seeked to on the input so nothing before it
+// is decoded. The audio edits are mapped onto the segment alone, which starts
+// at zero once seeked.
+func segmentArgs(s segment, opts compileOptions) []string {
+	selectFilter := fmt.Sprintf("between(t,0,%.3f)", s.end-s.start)
+	frameRate := ""
+	if opts.ConstantFrameRate != "" {
+		frameRate = fmt.Sprintf("fps=%s,", opts.ConstantFrameRate)
+	}
+	overlays := ""
+	if opts.LowerThird != nil {
+		var thirds []lowerThird
+		for _, third := range opts.LowerThirds {
+			if third.Start >= s.start && third.Start < s.end {
+				thirds = append(thirds, third)
+			}
+		}
+		overlays = drawLowerThirds(thirds, *opts.LowerThird, s.start)
+	}
+	if opts.Captions != nil {
+		var captions []burnedCaption
+		for _, caption := range opts.BurnedCaptions {
+			if caption.End > s.start && caption.Start < s.end {
+				captions = append(captions, caption)
+			}
+		}
+		overlays += drawCaptions(captions, *opts.Captions, s.start)
+	}
+	video := fmt.Sprintf("[0:v:0]%s%sselect='%s',setpts=N/FRAME_RATE/TB", frameRate, overlays, selectFilter)
+	if opts.Draft {
+		video += fmt.Sprintf(",scale=-2:%d", draftHeight)
+	}
+
+	filters := append([]string{video + "[v]"}, audioFilters(0, selectFilter, audioEditsFor(opts, []segment{s}))...)
+	args := []string{"-filter_complex", strings.Join(filters, ";"), "-map", "[v]", "-map", "[a0]"}
+	if opts.Draft {
+		args = append(args, draftEncoderArgs()...)
+	} else {
+		args = append(args, compileEncoderArgs(opts)...)
+	}
+	args = append(args, "-c:a", cmp.Or(opts.AudioCodec, "aac"))
+	return append(args, audioOutputArgs(opts)...)
+}
+
+// cachedSegment encodes a segment into the cache, unless a compile with the
+// same source, range, and settings already did.
+func cachedSegment(source string, s segment, opts compileOptions) (string, error) {
+	key, err := cacheKey(source)
+	if err != nil {
+		return "", err
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	seek := []string{"-ss", fmt.Sprintf("%.3f", s.start), "-t", fmt.Sprintf("%.3f", s.end-s.start)}
+	args := segmentArgs(s, opts)
+	sum := sha256.Sum256([]byte(key + "\x00" + strings.Join(seek, "\x00") + "\x00" + strings.Join(args, "\x00")))
+	segmentFile := filepath.Join(dir, "segments", hex.EncodeToString(sum[:12])+".mp4")
+
+	// Touched on every use, so segments still being recompiled aren't pruned
+	if _, err := os.Stat(segmentFile); err == nil {
+		now := time.Now()
+		os.Chtimes(segmentFile, now, now)
+		opts.Progress.add(s.end - s.start)
+		return segmentFile, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(segmentFile), 0755); err != nil {
+		return "", err
+	}
+
+	command := append(append(append([]string{"-y"}, seek...), "-i", source), args...)
+	err = renderTo(segmentFile, func(partial string) error {
+		if err := runFFmpeg(opts.Progress, append(command, partial)...); err != nil {
+			return fmt.Errorf("failed to encode segment at %s: %w", formatDuration(s.start), err)
+		}
+		return nil
+	})
+	return segmentFile, err
+}
+
+// compileCachedSegments encodes only the segments that changed since the last
+// compile and joins them all without encoding again, returning the args of
+// the join.
+func compileCachedSegments(source string, segments []segment, opts compileOptions, metadata []string, outputFile string) ([]string, error) {
+	listFile, err := os.CreateTemp("", "tsplice-segments-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	removeOnExit(listFile.Name())
+	defer removeTemp(listFile.Name())
+
+	for _, s := range segments {
+		segmentFile, err := cachedSegment(source, s, opts)
+		if err != nil {
+			listFile.Close()
+			return nil, err
+		}
+		fmt.Fprintf(listFile, "file '%s'\n", strings.ReplaceAll(segmentFile, "'", `'\''`))
+	}
+	if err := listFile.Close(); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"-y", "-f", "concat", "-safe", "0", "-i", listFile.Name(), "-map", "0", "-c", "copy"}, metadata...)
+	args = append(args, outputFile)
+	if err := runFFmpeg(opts.Progress, args...); err != nil {
+		return nil, fmt.Errorf("failed to join segments: %w", err)
+	}
+
+	pruneSegmentCache()
+	return args, nil
+}
+
+// pruneSegmentCache removes segments no compile has used for a while.
+func pruneSegmentCache() {
+	dir, err := cacheDir()
+	if err != nil {
+		return
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "segments", "*.mp4"))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) > segmentCacheAge {
+			os.Remove(file)
+		}
+	}
+}
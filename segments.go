@@ -0,0 +1,212 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mergeNext joins the line at the cursor with the one after it, for a
+// sentence the transcription broke in two, so it can be cut as one.
+func (m model) mergeNext() (model, tea.Cmd) {
+	index := m.list.GlobalIndex()
+	items := m.list.Items()
+	if index < 0 || index+1 >= len(items) || index+1 >= len(m.transcriptItems) {
+		m.statuses = append(m.statuses, tr("There's no line after this one to merge with."))
+		return m, nil
+	}
+	if !slices.Contains(visibleIndexes(m.list), index+1) {
+		m.statuses = append(m.statuses, tr("The next line is hidden by the filter, clear it to merge them."))
+		return m, nil
+	}
+	first, ok := items[index].(item)
+	second, secondOK := items[index+1].(item)
+	if !ok || !secondOK {
+		return m, nil
+	}
+	if first.speaker != second.speaker {
+		m.statuses = append(m.statuses, tr("Lines from different speakers can't be merged."))
+		return m, nil
+	}
+
+	transcriptItems := slices.Clone(m.transcriptItems)
+	transcriptItems[index] = mergeTranscriptItems(transcriptItems[index], transcriptItems[index+1])
+	transcriptItems = slices.Delete(transcriptItems, index+1, index+2)
+	merged := slices.Clone(items)
+	merged[index] = mergeItems(first, second)
+	merged = slices.Delete(merged, index+1, index+2)
+	return m.saveSegments(transcriptItems, merged)
+}
+
+// mergeTranscriptItems is two lines of the transcript as one, as sure of its
+// words as the less sure of the two was.
+func mergeTranscriptItems(first, second TranscriptItem) TranscriptItem {
+	first.EndTime = second.EndTime
+	first.Text = first.Text + " " + second.Text
+	if second.Confidence > 0 && (first.Confidence == 0 || second.Confidence < first.Confidence) {
+		first.Confidence = second.Confidence
+	}
+	return first
+}
+
+// mergeItems is two lines of the list as one, selected and tagged if either
+// of them was.
+func mergeItems(first, second item) item {
+	first.title = first.title + " " + second.title
+	first.end = second.end
+	first.selected = first.selected || second.selected
+	first.quiet = first.quiet || second.quiet
+	first.tags = slices.Clone(first.tags)
+	for _, tag := range second.tags {
+		if !slices.Contains(first.tags, tag) {
+			first.tags = append(first.tags, tag)
+		}
+	}
+	if second.confidence > 0 && (first.confidence == 0 || second.confidence < first.confidence) {
+		first.confidence = second.confidence
+	}
+	first.removal = ""
+	return first
+}
+
+// startSplit starts picking where to split the line at the cursor, starting
+// after its first sentence when it has more than one, or in its middle.
+func (m model) startSplit() model {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return m
+	}
+	fields := strings.Fields(i.title)
+	if len(fields) < 2 {
+		m.statuses = append(m.statuses, tr("A line needs at least two words to be split."))
+		return m
+	}
+
+	m.splitAt = len(fields) / 2
+	for index, field := range fields[:len(fields)-1] {
+		if strings.LastIndexAny(field, ".?!") == len(field)-1 {
+			m.splitAt = index + 1
+			break
+		}
+	}
+	m.splitting = true
+	m.list.SetDelegate(m.newItemDelegate())
+	return m
+}
+
+func (m model) stopSplit() model {
+	m.splitting = false
+	m.list.SetDelegate(m.newItemDelegate())
+	return m
+}
+
+// updateSplit moves where the line at the cursor is split a word at a time,
+// until enter splits it there.
+func (m model) updateSplit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return m.stopSplit(), nil
+	}
+	switch msg.String() {
+	case "esc":
+		return m.stopSplit(), nil
+	case "left", "h":
+		m.splitAt = max(1, m.splitAt-1)
+	case "right", "l":
+		m.splitAt = min(len(strings.Fields(i.title))-1, m.splitAt+1)
+	case "enter", "s":
+		return m.stopSplit().splitLine()
+	}
+	m.list.SetDelegate(m.newItemDelegate())
+	return m, nil
+}
+
+// splitTime is when the word the line at the cursor is split before starts,
+// from the word timings when they line up with the line's text, or estimated
+// the way it is for transcripts without them.
+func (m model) splitTime() (float64, bool) {
+	index := m.list.GlobalIndex()
+	if index < 0 || index >= len(m.transcriptItems) {
+		return 0, false
+	}
+	transcriptItem := m.transcriptItems[index]
+	start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+	end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+	fields := strings.Fields(transcriptItem.Text)
+	if m.splitAt < 1 || m.splitAt >= len(fields) {
+		return 0, false
+	}
+
+	var words []Word
+	for _, word := range m.details.Words {
+		if word.Start >= start && word.Start < end {
+			words = append(words, word)
+		}
+	}
+	if len(words) != len(fields) {
+		words = estimateWords([]TranscriptItem{transcriptItem})
+	}
+	at := start + (end-start)*float64(m.splitAt)/float64(len(fields))
+	if m.splitAt < len(words) {
+		at = words[m.splitAt].Start
+	}
+	// Cues are saved to the millisecond
+	at, _ = parseTimeToSeconds(formatTimestamp(at))
+	return at, at > start && at < end
+}
+
+// splitLine splits the line at the cursor in two where it was picked, both
+// keeping its selection and tags.
+func (m model) splitLine() (model, tea.Cmd) {
+	index := m.list.GlobalIndex()
+	i, ok := m.list.SelectedItem().(item)
+	at, inside := m.splitTime()
+	if !ok {
+		return m, nil
+	}
+	if !inside {
+		m.statuses = append(m.statuses, tr("This line is too short to split there."))
+		return m, nil
+	}
+
+	fields := strings.Fields(m.transcriptItems[index].Text)
+	first, second := m.transcriptItems[index], m.transcriptItems[index]
+	first.EndTime, second.StartTime = formatTimestamp(at), formatTimestamp(at)
+	first.Text, second.Text = strings.Join(fields[:m.splitAt], " "), strings.Join(fields[m.splitAt:], " ")
+	transcriptItems := slices.Insert(slices.Clone(m.transcriptItems), index+1, second)
+	transcriptItems[index] = first
+
+	firstItem, secondItem := i, i
+	firstItem.end, secondItem.start = at, at
+	firstItem.title, secondItem.title = first.Text, second.Text
+	secondItem.tags = slices.Clone(i.tags)
+	items := slices.Insert(slices.Clone(m.list.Items()), index+1, list.Item(secondItem))
+	items[index] = firstItem
+	return m.saveSegments(transcriptItems, items)
+}
+
+// splitView is the line at the cursor with a marker where it'll be split.
+func (m model) splitView() string {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(i.title)
+	at := min(m.splitAt, len(fields))
+	return strings.Join(fields[:at], " ") + " ┃ " + strings.Join(fields[at:], " ")
+}
+
+// saveSegments saves the lines once they're merged or split, which can be
+// undone like any other change.
+func (m model) saveSegments(transcriptItems []TranscriptItem, items []list.Item) (model, tea.Cmd) {
+	m = m.pushUndo()
+	m, saved := m.saveTranscript(transcriptItems)
+	if !saved {
+		m.undoStack = m.undoStack[:len(m.undoStack)-1]
+		return m, nil
+	}
+	m = m.saveItems(items)
+	return m, tea.Batch(m.list.SetItems(items), m.embedCmd())
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	selftestDuration = 10.0
+	selftestVTT      = `WEBVTT
+
+00:00:00.000 --> 00:00:02.000
+<v Alice>First line of the fixture
+
+00:00:02.000 --> 00:00:05.000
+<v Bob>Second line of the fixture
+
+00:00:05.000 --> 00:00:10.000
+<v Alice>Third line of the fixture
+`
+)
+
+type selftestCheck struct {
+	name string
+	run  func(dir string) error
+}
+
+// selftestChecks run in order, each relying on the files written by the
+// ones before it.
+var selftestChecks = []selftestCheck{
+	{"generate fixture", generateFixture},
+	{"extract audio", func(dir string) error {
+		audioFile, err := extractAudio(filepath.Join(dir, "fixture.mp4"), false, "", nil)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(audioFile)
+		return expectDuration(audioFile, selftestDuration)
+	}},
+	{"parse transcript", func(dir string) error {
+		items, err := parseVTT(selftestVTT)
+		if err != nil {
+			return err
+		}
+		if len(items) != 3 {
+			return fmt.Errorf("expected 3 segments, got %d", len(items))
+		}
+		if items[1].Speaker != "Bob" || items[1].Text != "Second line of the fixture" {
+			return fmt.Errorf("unexpected second segment %+v", items[1])
+		}
+		return os.WriteFile(vttPath(filepath.Join(dir, "fixture.mp4")), []byte(selftestVTT), 0644)
+	}},
+	{"compile selection", func(dir string) error {
+		outputFile, err := selftestCompile(dir, compileOptions{})
+		if err != nil {
+			return err
+		}
+		if err := expectStreams(outputFile, 1, 1); err != nil {
+			return err
+		}
+		return expectInSync(outputFile, 7)
+	}},
+	{"compile keeping streams", func(dir string) error {
+		outputFile, err := selftestCompile(dir, compileOptions{KeepStreams: true})
+		if err != nil {
+			return err
+		}
+		if err := expectStreams(outputFile, 1, 2); err != nil {
+			return err
+		}
+		return expectInSync(outputFile, 7)
+	}},
+	{"export stems", func(dir string) error {
+		if _, err := selftestCompile(dir, compileOptions{Stems: true}); err != nil {
+			return err
+		}
+		stems, _ := filepath.Glob(filepath.Join(dir, "fixture_compiled_stem*.wav"))
+		if len(stems) != 2 {
+			return fmt.Errorf("expected 2 stems, got %d", len(stems))
+		}
+		for _, stem := range stems {
+			if err := expectDuration(stem, 7); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+}
+
+// generateFixture writes a short synthetic video with two tone audio tracks,
+// for the checks to work on.
+func generateFixture(dir string) error {
+	cmd := exec.Command("ffmpeg", "-y",
+		"-f", "lavfi", "-i", fmt.Sprintf("testsrc=size=320x240:rate=30:duration=%g", selftestDuration),
+		"-f", "lavfi", "-i", fmt.Sprintf("sine=frequency=440:duration=%g", selftestDuration),
+		"-f", "lavfi", "-i", fmt.Sprintf("sine=frequency=880:duration=%g", selftestDuration),
+		"-map", "0:v", "-map", "1:a", "-map", "2:a",
+		"-c:v", "libx264", "-pix_fmt", "yuv420p", "-c:a", "aac",
+		filepath.Join(dir, "fixture.mp4"),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, lastLine(out))
+	}
+	return nil
+}
+
+// selftestCompile compiles the first and last segment of the fixture transcript.
+func selftestCompile(dir string, opts compileOptions) (string, error) {
+	inputFile := filepath.Join(dir, "fixture.mp4")
+	transcriptItems, err := loadTranscript(vttPath(inputFile))
+	if err != nil {
+		return "", err
+	}
+
+	items := toListItems(transcriptItems)
+	for _, index := range []int{0, 2} {
+		i := items[index].(item)
+		i.selected = true
+		items[index] = i
+	}
+
+	return compileSegments(inputFile, selectedSegments(items), opts)
+}
+
+func expectDuration(file string, expected float64) error {
+	duration, err := probeDuration(file)
+	if err != nil {
+		return err
+	}
+	if math.Abs(duration-expected) > syncTolerance {
+		return fmt.Errorf("%s is %.2fs long, expected %.2fs", filepath.Base(file), duration, expected)
+	}
+	return nil
+}
+
+func expectStreams(file string, video, audio int) error {
+	probe, err := probeStreams(file)
+	if err != nil {
+		return err
+	}
+	if got := countStreams(probe, "video"); got != video {
+		return fmt.Errorf("expected %d video streams, got %d", video, got)
+	}
+	if got := countStreams(probe, "audio"); got != audio {
+		return fmt.Errorf("expected %d audio streams, got %d", audio, got)
+	}
+	return nil
+}
+
+func expectInSync(file string, expected float64) error {
+	warnings, err := verifyOutput(file, expected)
+	if err != nil {
+		return err
+	}
+	if len(warnings) > 0 {
+		return fmt.Errorf("%s", strings.Join(warnings, " "))
+	}
+	return nil
+}
+
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return lines[len(lines)-1]
+}
+
+// selftestFlags are the options of tsplice selftest.
+type selftestFlags struct {
+	keep bool
+}
+
+func (f *selftestFlags) define(fs *flag.FlagSet) {
+	fs.BoolVar(&f.keep, "keep", false, "Keep the generated fixtures and outputs for inspection")
+}
+
+func runSelftest(args []string) error {
+	fs := newCommandFlagSet("selftest")
+	var flags selftestFlags
+	flags.define(fs)
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	if !checkDependency("ffmpeg") || !checkDependency("ffprobe") {
+		return fmt.Errorf("the checks need ffmpeg and ffprobe, install them to run tsplice selftest")
+	}
+
+	// Fixtures are throwaway, so they're never worth a passphrase prompt
+	encryptAtRest = false
+
+	dir, err := os.MkdirTemp("", "tsplice-selftest-")
+	if err != nil {
+		return err
+	}
+	if flags.keep {
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Writing fixtures to "+dir))
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	// Intermediate files are written to the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	failed := 0
+	for _, check := range selftestChecks {
+		spaces := strings.Repeat(" ", max(2, 26-len(check.name)))
+		if err := check.run(dir); err != nil {
+			failed++
+			fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(check.name) + ErrorStyle.Render(spaces+"✗ "+err.Error()))
+			continue
+		}
+		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(check.name) + DimTextStyle.Render(spaces+"✔ passed"))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(selftestChecks))
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("All %d checks passed.", len(selftestChecks))))
+	return nil
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// presenceTimeout is how long a client is shown after it was last heard from
+const presenceTimeout = 30 * time.Second
+
+// sharedLine is a transcript line as clients see it. Version goes up with
+// every change, so an edit based on an outdated copy can be turned away.
+type sharedLine struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Text     string `json:"text"`
+	Speaker  string `json:"speaker,omitempty"`
+	Selected bool   `json:"selected"`
+	Version  int    `json:"version"`
+	EditedBy string `json:"edited_by,omitempty"`
+}
+
+type presence struct {
+	Name string    `json:"name"`
+	Line int       `json:"line"`
+	Seen time.Time `json:"-"`
+}
+
+// sharedProject is one video's selection, edited by any number of clients.
+type sharedProject struct {
+	mu        sync.Mutex
+	inputFile string
+	lines     []sharedLine
+	clients   map[string]presence
+}
+
+func sharedPath(inputFile string) string {
+	return strings.TrimSuffix(vttPath(inputFile), ".vtt") + ".shared.json"
+}
+
+func newSharedProject(inputFile string, transcriptItems []TranscriptItem) *sharedProject {
+	p := &sharedProject{inputFile: inputFile, clients: map[string]presence{}}
+	for _, transcriptItem := range transcriptItems {
+		p.lines = append(p.lines, sharedLine{
+			Start:   transcriptItem.StartTime,
+			End:     transcriptItem.EndTime,
+			Text:    transcriptItem.Text,
+			Speaker: transcriptItem.Speaker,
+		})
+	}
+
+	// Pick the selection back up from the last session. It's kept by when
+	// each line starts, so it stays on the same lines after merges and splits
+	selected := loadSharedSelection(inputFile)
+	for index, line := range p.lines {
+		if start, err := parseTimeToSeconds(line.Start); err == nil && selected[start] {
+			p.lines[index].Selected = true
+		}
+	}
+	return p
+}
+
+// loadSharedSelection returns the start times, in seconds, of the lines
+// selected in a shared session.
+func loadSharedSelection(inputFile string) map[float64]bool {
+	content, err := readProjectFile(sharedPath(inputFile))
+	if err != nil {
+		return nil
+	}
+	var starts []string
+	if err := json.Unmarshal(content, &starts); err != nil {
+		return nil
+	}
+	selected := map[float64]bool{}
+	for _, start := range starts {
+		if seconds, err := parseTimeToSeconds(start); err == nil {
+			selected[seconds] = true
+		}
+	}
+	return selected
+}
+
+// save writes the start times of the selected lines, called with the lock
+// held.
+func (p *sharedProject) save() error {
+	var selected []string
+	for _, line := range p.lines {
+		if line.Selected {
+			selected = append(selected, line.Start)
+		}
+	}
+	content, err := json.Marshal(selected)
+	if err != nil {
+		return err
+	}
+	return writeProjectFile(sharedPath(p.inputFile), content)
+}
+
+// selectedItems returns the selected lines, called with the lock held.
+func (p *sharedProject) selectedItems() []TranscriptItem {
+	var transcriptItems []TranscriptItem
+	for _, line := range p.lines {
+		if line.Selected {
+			transcriptItems = append(transcriptItems, TranscriptItem{StartTime: line.Start, EndTime: line.End, Text: line.Text, Speaker: line.Speaker})
+		}
+	}
+	return transcriptItems
+}
+
+func (p *sharedProject) handleState(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var clients []presence
+	for name, client := range p.clients {
+		if time.Since(client.Seen) > presenceTimeout {
+			delete(p.clients, name)
+			continue
+		}
+		clients = append(clients, client)
+	}
+	sort.Slice(clients, func(a, b int) bool { return clients[a].Name < clients[b].Name })
+
+	writeJSON(w, http.StatusOK, map[string]any{"lines": p.lines, "clients": clients})
+}
+
+// handleSelect changes one line if the client saw its latest version, and
+// otherwise answers with a conflict and the current line so it can refresh.
+func (p *sharedProject) handleSelect(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Client   string `json:"client"`
+		Line     int    `json:"line"`
+		Selected bool   `json:"selected"`
+		Version  int    `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if req.Line < 0 || req.Line >= len(p.lines) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "no such line"})
+		return
+	}
+
+	line := &p.lines[req.Line]
+	if req.Version != line.Version {
+		writeJSON(w, http.StatusConflict, line)
+		return
+	}
+
+	line.Selected = req.Selected
+	line.Version++
+	line.EditedBy = req.Client
+	p.clients[req.Client] = presence{Name: req.Client, Line: req.Line, Seen: time.Now()}
+
+	if err := p.save(); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, line)
+}
+
+func (p *sharedProject) handlePresence(w http.ResponseWriter, r *http.Request) {
+	var req presence
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "a name is required"})
+		return
+	}
+
+	p.mu.Lock()
+	req.Seen = time.Now()
+	p.clients[req.Name] = req
+	p.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *sharedProject) handleSelection(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	content := formatVTT(p.selectedItems())
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+	w.Write([]byte(content))
+}
+
+// requireToken turns away requests without the token, when there is one.
+// Opening the page with ?token= sets it as a cookie, which the page's own
+// requests then send.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := r.URL.Query().Get("token")
+		if given != "" {
+			http.SetCookie(w, &http.Cookie{Name: "tsplice-token", Value: given, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
+		} else if cookie, err := r.Cookie("tsplice-token"); err == nil {
+			given = cookie.Value
+		}
+		if !hmac.Equal([]byte(given), []byte(token)) {
+			http.Error(w, "open the page with ?token= and the token it was shared with", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+const sharedPageScript = `
+let name = localStorage.getItem("tsplice-name");
+while (!name) {
+  name = (prompt("Your name, shown to everyone else editing") || "").trim();
+}
+localStorage.setItem("tsplice-name", name);
+
+const list = document.querySelector("#lines");
+const who = document.querySelector("#clients");
+let state = { lines: [], clients: [] };
+let cursor = 0;
+
+function render() {
+  who.textContent = state.clients.map((c) => c.name).join(", ");
+  list.innerHTML = "";
+  state.lines.forEach((line, index) => {
+    const row = document.createElement("label");
+    row.className = "line" + (line.selected ? " selected" : "");
+    const box = document.createElement("input");
+    box.type = "checkbox";
+    box.checked = line.selected;
+    box.addEventListener("change", () => toggle(index, box.checked));
+    row.append(box);
+    const time = document.createElement("span");
+    time.className = "time";
+    time.textContent = line.start;
+    row.append(time, (line.speaker ? line.speaker + ": " : "") + line.text);
+    for (const client of state.clients) {
+      if (client.line === index && client.name !== name) {
+        const tag = document.createElement("span");
+        tag.className = "presence";
+        tag.textContent = client.name;
+        row.append(tag);
+      }
+    }
+    if (line.edited_by && line.edited_by !== name) {
+      row.title = "Last changed by " + line.edited_by;
+    }
+    row.addEventListener("mouseenter", () => { cursor = index; });
+    list.append(row);
+  });
+}
+
+async function refresh() {
+  const response = await fetch("api/state");
+  state = await response.json();
+  state.clients = state.clients || [];
+  render();
+}
+
+async function toggle(index, selected) {
+  const response = await fetch("api/select", {
+    method: "POST",
+    body: JSON.stringify({ client: name, line: index, selected, version: state.lines[index].version }),
+  });
+  if (response.status === 409) {
+    alert("Someone else just changed this line, it's been refreshed.");
+  }
+  refresh();
+}
+
+setInterval(() => {
+  fetch("api/presence", { method: "POST", body: JSON.stringify({ name, line: cursor }) });
+  refresh();
+}, 2000);
+refresh();
+`
+
+func sharedPage(title string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+#clients { color: #888; }
+.line { display: block; padding: 0.25rem 0.5rem; border-radius: 4px; cursor: pointer; }
+.line:hover { background: #f2f2f2; }
+.line.selected { background: #fff3c4; }
+.time { color: #888; font-variant-numeric: tabular-nums; margin: 0 0.5rem; }
+.presence { margin-left: 0.5rem; padding: 0 0.4rem; border-radius: 4px; background: #5c5cff; color: #fff; font-size: 0.8rem; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<p>Editing now: <span id="clients"></span> &middot; <a href="selection.vtt">selected lines</a></p>
+<div id="lines"></div>
+<script>%s</script>
+</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(title), sharedPageScript)
+}
+
+// serveFlags are the options of tsplice serve.
+type serveFlags struct {
+	addr  string
+	token string
+}
+
+func (f *serveFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.addr, "addr", "127.0.0.1:8420", "Address to listen on, like :8420 for every interface")
+	fs.StringVar(&f.token, "token", "", "Token everyone opening the page needs, required to listen beyond this machine")
+}
+
+func runServe(args []string) error {
+	fs := newCommandFlagSet("serve")
+	var flags serveFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice serve [options] <input-file>")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+	if offline && !isLoopbackAddr(flags.addr) {
+		return fmt.Errorf("offline mode is on, listen on localhost so the transcript stays on this machine, like --addr=127.0.0.1:8420")
+	}
+	if flags.token == "" && !isLoopbackAddr(flags.addr) {
+		return fmt.Errorf("set --token to share the transcript beyond this machine, so only the people you give it to can change the selection")
+	}
+
+	// The editor or tsplice batch working on the same video would write over
+	// the transcript while it's shared
+	release, pid, err := lockVideo(vttPath(inputFile))
+	if err != nil {
+		return fmt.Errorf("this video is already being edited by %s, close it to share the video", lockHolder(pid))
+	}
+	defer release()
+	atShutdown(release)
+
+	if _, err := os.Stat(vttPath(inputFile)); os.IsNotExist(err) && transcribesWithOpenAI() {
+		if err := setupAPIKey(); err != nil {
+			return err
+		}
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+	if err != nil {
+		return err
+	}
+
+	project := newSharedProject(inputFile, transcriptItems)
+	title := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(sharedPage(title)))
+	})
+	mux.HandleFunc("GET /api/state", project.handleState)
+	mux.HandleFunc("POST /api/select", project.handleSelect)
+	mux.HandleFunc("POST /api/presence", project.handlePresence)
+	mux.HandleFunc("GET /selection.vtt", project.handleSelection)
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Sharing %d lines of %s", len(transcriptItems), filepath.Base(inputFile))))
+	if flags.token != "" {
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Open it with ?token="+url.QueryEscape(flags.token)+" on the end of the address"))
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Listening on "+flags.addr+", selections are saved to "+sharedPath(inputFile)))
+	return http.ListenAndServe(flags.addr, requireToken(flags.token, mux))
+}
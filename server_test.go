@@ -0,0 +1,39 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSharedSelectionFollowsLinesAfterAMerge(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "talk.mp4")
+	transcriptItems := []TranscriptItem{
+		{StartTime: "00:00:00.000", EndTime: "00:00:02.000", Text: "First"},
+		{StartTime: "00:00:02.000", EndTime: "00:00:04.000", Text: "Second"},
+		{StartTime: "00:00:04.000", EndTime: "00:00:06.000", Text: "Third"},
+	}
+
+	p := newSharedProject(inputFile, transcriptItems)
+	p.lines[2].Selected = true
+	if err := p.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Merging the first two lines moves the third up to the second
+	merged := []TranscriptItem{
+		{StartTime: "00:00:00.000", EndTime: "00:00:04.000", Text: "First Second"},
+		transcriptItems[2],
+	}
+	p = newSharedProject(inputFile, merged)
+	if p.lines[0].Selected || !p.lines[1].Selected {
+		t.Errorf("selection after the merge is %v and %v, want only the line starting at 00:00:04.000", p.lines[0].Selected, p.lines[1].Selected)
+	}
+
+	items := toListItems(merged)
+	shared := loadSharedSelection(inputFile)
+	for index, listItem := range items {
+		if got, want := shared[listItem.(item).start], index == 1; got != want {
+			t.Errorf("line %d selected is %v in the editor, want %v", index, got, want)
+		}
+	}
+}
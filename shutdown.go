@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// childStopGrace is how long a child has to exit on its own after being
+// asked to, before it's killed.
+const childStopGrace = 2 * time.Second
+
+// errShuttingDown is returned for a command started once tsplice is
+// stopping, which would otherwise outlive it.
+var errShuttingDown = errors.New("tsplice is shutting down")
+
+// running holds what shutdown has to clean up: the programs tsplice started
+// that haven't exited, the temp files and folders still in use, and
+// anything else that has to be done before exiting.
+var running struct {
+	mu         sync.Mutex
+	stopping   bool
+	children   map[*exec.Cmd]bool
+	temporary  map[string]bool
+	atShutdown []func()
+}
+
+// tuiRunning is set while the editor is open. Bubble Tea takes the signals
+// then, restoring the terminal before main shuts down.
+var tuiRunning atomic.Bool
+
+// startChild starts a command that's stopped on shutdown if it's still
+// running. waitChild has to be called for it after.
+func startChild(cmd *exec.Cmd) error {
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	if running.stopping {
+		return errShuttingDown
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if running.children == nil {
+		running.children = map[*exec.Cmd]bool{}
+	}
+	running.children[cmd] = true
+	return nil
+}
+
+// waitChild waits for a command from startChild to exit.
+func waitChild(cmd *exec.Cmd) error {
+	err := cmd.Wait()
+	running.mu.Lock()
+	delete(running.children, cmd)
+	running.mu.Unlock()
+	return err
+}
+
+// runChild runs a command to the end, like cmd.Run, unless shutdown stops
+// it first.
+func runChild(cmd *exec.Cmd) error {
+	if err := startChild(cmd); err != nil {
+		return err
+	}
+	return waitChild(cmd)
+}
+
+// outputChild is runChild returning what the command printed, like
+// cmd.Output.
+func outputChild(cmd *exec.Cmd) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := runChild(cmd)
+	return stdout.Bytes(), err
+}
+
+// removeOnExit marks a temp file or folder to be removed if tsplice is
+// stopped before whatever made it removes it with removeTemp.
+func removeOnExit(path string) {
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	if running.temporary == nil {
+		running.temporary = map[string]bool{}
+	}
+	running.temporary[path] = true
+}
+
+// removeTemp removes a temp file or folder from removeOnExit once it's done
+// with.
+func removeTemp(path string) {
+	os.RemoveAll(path)
+	keepTemp(path)
+}
+
+// keepTemp stops a path from removeOnExit being removed, once it's been
+// moved into place or is kept on purpose.
+func keepTemp(path string) {
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	delete(running.temporary, path)
+}
+
+// tempDir makes a temp folder in the workspace, the way os.MkdirTemp does,
+// that's removed on shutdown if removeTemp hasn't removed it before.
+func tempDir(pattern string) (string, error) {
+	parent, err := workspaceDir()
+	if err != nil {
+		// The system's temp folder will do
+		parent = ""
+	}
+	dir, err := os.MkdirTemp(parent, pattern)
+	if err == nil {
+		removeOnExit(dir)
+	}
+	return dir, err
+}
+
+// atShutdown runs f when tsplice shuts down, after its children have
+// stopped, also when it's stopped by a signal.
+func atShutdown(f func()) {
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	running.atShutdown = append(running.atShutdown, f)
+}
+
+// shutdown stops every child still running, asking first and killing what
+// doesn't exit in time, then removes the temp files they were writing and
+// runs what was left to do. Nothing can be started once it's begun.
+func shutdown() {
+	running.mu.Lock()
+	if running.stopping {
+		running.mu.Unlock()
+		return
+	}
+	running.stopping = true
+	var children []*exec.Cmd
+	for cmd := range running.children {
+		children = append(children, cmd)
+	}
+	running.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, cmd := range children {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stopChild(cmd)
+		}()
+	}
+	wg.Wait()
+
+	running.mu.Lock()
+	defer running.mu.Unlock()
+	for path := range running.temporary {
+		os.RemoveAll(path)
+	}
+	running.temporary = nil
+	for _, f := range running.atShutdown {
+		f()
+	}
+	running.atShutdown = nil
+}
+
+// stopChild asks a child to exit, since ffmpeg finishes what it's writing
+// on SIGTERM, and kills it if it hasn't within childStopGrace.
+func stopChild(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		// Windows has no SIGTERM to send
+		cmd.Process.Kill()
+		return
+	}
+
+	deadline := time.Now().Add(childStopGrace)
+	for time.Now().Before(deadline) {
+		running.mu.Lock()
+		exited := !running.children[cmd]
+		running.mu.Unlock()
+		if exited {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	cmd.Process.Kill()
+}
+
+// handleSignals shuts down cleanly on ctrl+c or SIGTERM, except while the
+// editor is open and Bubble Tea gets them instead.
+func handleSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for s := range signals {
+			if tuiRunning.Load() {
+				continue
+			}
+			fmt.Println()
+			fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Stopping, cleaning up..."))
+			shutdown()
+			if s == syscall.SIGTERM {
+				os.Exit(143)
+			}
+			os.Exit(130)
+		}
+	}()
+}
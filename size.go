@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// sizeAudioBitrate is used for every audio track of a size constrained compile
+	sizeAudioBitrate = 128_000
+	// sizeOverhead leaves room for the container and any rate control overshoot
+	sizeOverhead = 0.96
+)
+
+// parseSize reads sizes like "50MB", "1.5GB", or "800k" as bytes.
+func parseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(value, "B")
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1e3
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1e9
+	}
+	value = strings.TrimRight(value, "KMG")
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid size %q, expected something like 50MB", s)
+	}
+
+	return int64(number * multiplier), nil
+}
+
+// videoBitrateFor works out the video bitrate that fits duration seconds of
+// output, with its audio tracks, into maxSize bytes.
+func videoBitrateFor(maxSize int64, duration float64, audioTracks int) (int, error) {
+	if duration <= 0 {
+		return 0, fmt.Errorf("selection has no duration")
+	}
+
+	total := float64(maxSize) * 8 * sizeOverhead / duration
+	video := int(total) - audioTracks*sizeAudioBitrate
+	if video < 100_000 {
+		return 0, fmt.Errorf("%s is too small for %s of video", formatBytes(maxSize), formatDuration(duration))
+	}
+
+	return video, nil
+}
+
+// twoPassEncode runs ffmpeg twice with the given args, first to analyze the
+// video and then to encode it at exactly the bitrate that hits the target size.
+// It returns the args of the second pass.
+func twoPassEncode(args []string, outputFile string, videoBitrate int, progress *renderProgress) ([]string, error) {
+	logDir, err := tempDir("tsplice-passlog-")
+	if err != nil {
+		return nil, err
+	}
+	defer removeTemp(logDir)
+
+	encoder := twoPassEncoder()
+	if encoder == "" {
+		return nil, fmt.Errorf("fitting a size limit needs ffmpeg built with libx264")
+	}
+
+	rateArgs := []string{
+		"-c:v", encoder,
+		"-b:v", strconv.Itoa(videoBitrate),
+		"-c:a", "aac",
+		"-b:a", strconv.Itoa(sizeAudioBitrate),
+		"-passlogfile", filepath.Join(logDir, "pass"),
+	}
+
+	firstPass := append(append(append([]string{}, args...), rateArgs...), "-pass", "1", "-f", "null", os.DevNull)
+	if err := runFFmpeg(progress, firstPass...); err != nil {
+		return nil, fmt.Errorf("failed on first encoding pass: %w", err)
+	}
+
+	secondPass := append(append(append([]string{}, args...), rateArgs...), "-pass", "2", outputFile)
+	if err := runFFmpeg(progress, secondPass...); err != nil {
+		return nil, fmt.Errorf("failed on second encoding pass: %w", err)
+	}
+
+	return secondPass, nil
+}
+
+// formatBytes renders a byte count the same way parseSize reads it.
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1e9:
+		return fmt.Sprintf("%.1fGB", float64(size)/1e9)
+	case size >= 1e6:
+		return fmt.Sprintf("%.1fMB", float64(size)/1e6)
+	case size >= 1e3:
+		return fmt.Sprintf("%.1fKB", float64(size)/1e3)
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
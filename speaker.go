@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// speakersOf lists everyone with a line in the transcript, in the order they
+// first speak.
+func speakersOf(items []list.Item) []string {
+	var speakers []string
+	for _, listItem := range items {
+		if i, ok := listItem.(item); ok && i.speaker != "" && !slices.Contains(speakers, i.speaker) {
+			speakers = append(speakers, i.speaker)
+		}
+	}
+	return speakers
+}
+
+// speakerItems narrows the selection down to a speaker's lines. When none of
+// their lines are selected, all of them are, so a reel can be made without
+// picking anything first.
+func speakerItems(items []list.Item, speaker string) ([]list.Item, int) {
+	narrowed := make([]list.Item, len(items))
+	selected := 0
+	for index, listItem := range items {
+		i, ok := listItem.(item)
+		if ok && (!strings.EqualFold(i.speaker, speaker) || !i.selected) {
+			i.selected = false
+		}
+		if ok && i.selected {
+			selected++
+		}
+		narrowed[index] = i
+	}
+	if selected > 0 {
+		return narrowed, selected
+	}
+
+	for index, listItem := range narrowed {
+		if i, ok := listItem.(item); ok && strings.EqualFold(i.speaker, speaker) {
+			i.selected = true
+			narrowed[index] = i
+			selected++
+		}
+	}
+	return narrowed, selected
+}
+
+// compileSpeakers compiles a reel of each speaker's lines, one after another,
+// with the same options as compiling from the editor.
+func (m model) compileSpeakers(speakers []string) error {
+	for _, speaker := range speakers {
+		items, selected := speakerItems(m.list.Items(), speaker)
+		if selected == 0 {
+			known := speakersOf(m.list.Items())
+			if len(known) == 0 {
+				return fmt.Errorf("the transcript doesn't say who's speaking")
+			}
+			return fmt.Errorf("no lines by %s, the speakers are %s", speaker, strings.Join(known, ", "))
+		}
+
+		opts := m.withAudioEdits(m.compileOptions)
+		opts.Speaker = speaker
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Compiling %d lines by %s with ffmpeg...", selected, speaker)))
+		switch msg := compileVideoCmd(m.inputFile, items, opts)().(type) {
+		case errorMsg:
+			return msg.err
+		case videoCompilationDoneMsg:
+			for _, warning := range msg.warnings {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Warning: "+warning))
+			}
+			for _, clip := range msg.clips {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Saved clip to "+clip))
+			}
+			if len(msg.clips) == 0 {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Saved output to "+msg.outputFile))
+			}
+		}
+	}
+	return nil
+}
+
+// speakerSample is a speaker's longest line, the one most likely to make it
+// clear who they are.
+func speakerSample(items []list.Item, speaker string) (item, bool) {
+	var sample item
+	found := false
+	for _, listItem := range items {
+		if i, ok := listItem.(item); ok && i.speaker == speaker && (!found || i.end-i.start > sample.end-sample.start) {
+			sample, found = i, true
+		}
+	}
+	return sample, found
+}
+
+// speakerLabels are the labels that were given a name, for showing where a
+// name came from.
+func speakerLabels(details transcriptDetails, name string) []string {
+	var labels []string
+	for label, given := range details.Speakers {
+		if given == name && label != name {
+			labels = append(labels, label)
+		}
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+func (m model) startNaming() model {
+	m.naming = true
+	m.namingCursor = 0
+	m.editingSpeaker = false
+	return m
+}
+
+// updateNaming moves between the speakers, plays a sample of each, and names
+// them, which is saved to the transcript's details right away.
+func (m model) updateNaming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	speakers := speakersOf(m.list.Items())
+
+	if m.editingSpeaker {
+		switch msg.String() {
+		case "esc":
+			m.editingSpeaker = false
+			return m, nil
+		case "enter":
+			m.editingSpeaker = false
+			return m.renameSpeaker(speakers[m.namingCursor], strings.TrimSpace(m.speakerInput.Value()))
+		}
+		var cmd tea.Cmd
+		m.speakerInput, cmd = m.speakerInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "N", "q":
+		m.naming = false
+
+	case "up", "k":
+		m.namingCursor = max(0, m.namingCursor-1)
+
+	case "down", "j":
+		m.namingCursor = min(len(speakers)-1, m.namingCursor+1)
+
+	case "p":
+		if sample, ok := speakerSample(m.list.Items(), speakers[m.namingCursor]); ok {
+			m = m.stopAudioPreview()
+			go previewVideo(m.previewFile(), formatTimestamp(sample.start), formatTimestamp(sample.end))
+		}
+
+	case "enter":
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = "name"
+		input.CharLimit = 64
+		input.SetValue(speakers[m.namingCursor])
+		input.CursorEnd()
+		input.Focus()
+		m.speakerInput = input
+		m.editingSpeaker = true
+	}
+	return m, nil
+}
+
+// renameSpeaker gives every line of a speaker a new name. The label the
+// transcriber gave them is kept in the transcript, so renaming them again or
+// re-reading the transcript always maps from it.
+func (m model) renameSpeaker(from, to string) (tea.Model, tea.Cmd) {
+	if to == "" || to == from {
+		return m, nil
+	}
+
+	if m.details.Speakers == nil {
+		m.details.Speakers = map[string]string{}
+	}
+	renamed := false
+	for label, name := range m.details.Speakers {
+		if name == from {
+			m.details.Speakers[label] = to
+			renamed = true
+		}
+	}
+	if !renamed {
+		m.details.Speakers[from] = to
+	}
+	if err := saveDetails(m.vttFile, m.details); err != nil {
+		m.statuses = append(m.statuses, trf("Could not save speaker names: %s", err.Error()))
+		return m, nil
+	}
+
+	transcriptItems := slices.Clone(m.transcriptItems)
+	for index := range transcriptItems {
+		if transcriptItems[index].Speaker == from {
+			transcriptItems[index].Speaker = to
+		}
+	}
+	m.transcriptItems = transcriptItems
+	m = m.syncStore(transcriptItems)
+
+	lines := 0
+	items := m.list.Items()
+	for index, listItem := range items {
+		if i, ok := listItem.(item); ok && i.speaker == from {
+			i.speaker = to
+			items[index] = i
+			lines++
+		}
+	}
+	m.statuses = append(m.statuses, trf("Renamed %s to %s on %d lines.", from, to, lines))
+	return m, m.list.SetItems(items)
+}
+
+func (m model) namingView() string {
+	var b strings.Builder
+	speakers := speakersOf(m.list.Items())
+	b.WriteString("  " + trf("Name the speakers (%d in this transcript)", len(speakers)) + "\n\n")
+
+	width := 0
+	for _, speaker := range speakers {
+		width = max(width, len([]rune(speaker)))
+	}
+
+	for index, speaker := range speakers {
+		cursor := "  "
+		if index == m.namingCursor {
+			cursor = SelectedItemStyle.Render("> ")
+		}
+
+		name := TextStyle.Render(speaker + strings.Repeat(" ", width-len([]rune(speaker))))
+		if index == m.namingCursor && m.editingSpeaker {
+			name = m.speakerInput.View()
+		} else if labels := speakerLabels(m.details, speaker); len(labels) > 0 {
+			name += DimTextStyle.Render("  " + trf("was %s", strings.Join(labels, ", ")))
+		}
+		b.WriteString(cursor + name + "\n")
+
+		if index == m.namingCursor {
+			if sample, ok := speakerSample(m.list.Items(), speaker); ok {
+				b.WriteString(DimTextStyle.Render(fmt.Sprintf("    %s  %s", formatDuration(sample.start), truncate(sample.title, 72))) + "\n")
+			}
+		}
+	}
+
+	help := "  " + tr("↑/↓ move • p play a sample • enter name • esc done")
+	if m.editingSpeaker {
+		help = "  " + tr("enter save • esc cancel")
+	}
+	b.WriteString("\n" + DimTextStyle.Render(help) + "\n")
+	return b.String()
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type rateBucket struct {
+	Minute         int     `json:"minute"`
+	Words          int     `json:"words"`
+	WordsPerMinute float64 `json:"words_per_minute"`
+}
+
+type speakerStat struct {
+	Speaker string  `json:"speaker"`
+	Seconds float64 `json:"seconds"`
+	Share   float64 `json:"share"`
+	Words   int     `json:"words"`
+}
+
+type silenceStat struct {
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Duration float64 `json:"duration"`
+}
+
+type recordingStats struct {
+	TotalWords     int            `json:"total_words"`
+	Duration       float64        `json:"duration"`
+	WordsPerMinute float64        `json:"words_per_minute"`
+	Rate           []rateBucket   `json:"rate"`
+	Speakers       []speakerStat  `json:"speakers"`
+	Silences       []silenceStat  `json:"longest_silences"`
+	Fillers        map[string]int `json:"fillers"`
+}
+
+const maxSilences = 5
+
+func computeStats(items []list.Item, fillers []string) recordingStats {
+	stats := recordingStats{Fillers: map[string]int{}}
+	speakers := map[string]*speakerStat{}
+	var speakerOrder []string
+	var first, last float64
+	var previousEnd float64
+	buckets := map[int]int{}
+
+	for index, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok {
+			continue
+		}
+		s := segmentFromItem(i)
+
+		if index == 0 {
+			first = s.start
+		} else if gap := s.start - previousEnd; gap > 0 {
+			stats.Silences = append(stats.Silences, silenceStat{Start: previousEnd, End: s.start, Duration: gap})
+		}
+		previousEnd = s.end
+		last = max(last, s.end)
+
+		words := normalizeWords(i.title)
+		stats.TotalWords += len(words)
+		buckets[int(s.start/60)] += len(words)
+
+		if i.speaker != "" {
+			if _, ok := speakers[i.speaker]; !ok {
+				speakers[i.speaker] = &speakerStat{Speaker: i.speaker}
+				speakerOrder = append(speakerOrder, i.speaker)
+			}
+			speakers[i.speaker].Seconds += s.end - s.start
+			speakers[i.speaker].Words += len(words)
+		}
+
+		for _, filler := range fillers {
+			if count := countPhrase(words, normalizeWords(filler)); count > 0 {
+				stats.Fillers[filler] += count
+			}
+		}
+	}
+
+	stats.Duration = last - first
+	if stats.Duration > 0 {
+		stats.WordsPerMinute = float64(stats.TotalWords) / (stats.Duration / 60)
+	}
+
+	if len(buckets) > 0 {
+		lastMinute := int(last / 60)
+		for minute := int(first / 60); minute <= lastMinute; minute++ {
+			stats.Rate = append(stats.Rate, rateBucket{Minute: minute, Words: buckets[minute], WordsPerMinute: float64(buckets[minute])})
+		}
+	}
+
+	talkTime := 0.0
+	for _, speaker := range speakers {
+		talkTime += speaker.Seconds
+	}
+	for _, name := range speakerOrder {
+		speaker := *speakers[name]
+		if talkTime > 0 {
+			speaker.Share = speaker.Seconds / talkTime
+		}
+		stats.Speakers = append(stats.Speakers, speaker)
+	}
+	sort.SliceStable(stats.Speakers, func(a, b int) bool { return stats.Speakers[a].Seconds > stats.Speakers[b].Seconds })
+
+	sort.SliceStable(stats.Silences, func(a, b int) bool { return stats.Silences[a].Duration > stats.Silences[b].Duration })
+	if len(stats.Silences) > maxSilences {
+		stats.Silences = stats.Silences[:maxSilences]
+	}
+
+	return stats
+}
+
+// countPhrase counts occurrences of a (possibly multi-word) phrase in words.
+func countPhrase(words, phrase []string) int {
+	if len(phrase) == 0 {
+		return 0
+	}
+
+	count := 0
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		matches := true
+		for j := range phrase {
+			if words[i+j] != phrase[j] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			count++
+		}
+	}
+	return count
+}
+
+func sparkline(buckets []rateBucket) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+	peak := 0
+	for _, bucket := range buckets {
+		peak = max(peak, bucket.Words)
+	}
+
+	var b strings.Builder
+	for _, bucket := range buckets {
+		index := 0
+		if peak > 0 {
+			index = bucket.Words * (len(bars) - 1) / peak
+		}
+		b.WriteRune(bars[index])
+	}
+	return b.String()
+}
+
+func (m model) statsView() string {
+	stats := computeStats(m.list.Items(), m.fillers)
+	var lines []string
+
+	row := func(label, value string) {
+		spaces := strings.Repeat(" ", max(2, 22-len([]rune(label))))
+		lines = append(lines, BulletStyle.Render("├────")+TextStyle.Render(label)+DimTextStyle.Render(spaces+value))
+	}
+
+	lines = append(lines, BulletStyle.Render("├")+TextStyle.Render(tr("Overview")))
+	row(tr("words"), fmt.Sprintf("%d", stats.TotalWords))
+	row(tr("duration"), formatDuration(stats.Duration))
+	row(tr("words per minute"), fmt.Sprintf("%.0f", stats.WordsPerMinute))
+	if len(stats.Rate) > 1 {
+		row(tr("rate over time"), sparkline(stats.Rate))
+	}
+
+	lines = append(lines, BulletStyle.Render("│"), BulletStyle.Render("├")+TextStyle.Render(tr("Speakers")))
+	if len(stats.Speakers) == 0 {
+		lines = append(lines, BulletStyle.Render("├────")+DimTextStyle.Render(tr("no speaker labels in this transcript")))
+	}
+	for _, speaker := range stats.Speakers {
+		row(speaker.Speaker, trf("%.0f%% (%s, %d words)", speaker.Share*100, formatDuration(speaker.Seconds), speaker.Words))
+	}
+
+	lines = append(lines, BulletStyle.Render("│"), BulletStyle.Render("├")+TextStyle.Render(tr("Longest silences")))
+	if len(stats.Silences) == 0 {
+		lines = append(lines, BulletStyle.Render("├────")+DimTextStyle.Render(tr("none")))
+	}
+	for _, silence := range stats.Silences {
+		row(formatTimestamp(silence.Start), fmt.Sprintf("%.1fs", silence.Duration))
+	}
+
+	lines = append(lines, BulletStyle.Render("│"), BulletStyle.Render("├")+TextStyle.Render(tr("Filler words")))
+	if len(stats.Fillers) == 0 {
+		lines = append(lines, BulletStyle.Render("├────")+DimTextStyle.Render(tr("none")))
+	}
+	for _, filler := range m.fillers {
+		if count := stats.Fillers[filler]; count > 0 {
+			row(filler, fmt.Sprintf("%d", count))
+		}
+	}
+
+	lines = append(lines, BulletStyle.Render("│"), BulletStyle.Render("└")+DimTextStyle.Render(tr("Press x to export as JSON/CSV, S or esc to go back")))
+	return strings.Join(lines, "\n") + "\n"
+}
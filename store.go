@@ -0,0 +1,459 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	_ "modernc.org/sqlite"
+)
+
+// storeSchema is bumped whenever the tables change, and a store from an older
+// version is rebuilt from the transcript.
+const storeSchema = 1
+
+// store keeps a video's transcript, words, selection, and tags in a SQLite
+// file next to the transcript. Lines are indexed for full-text search, and a
+// selection or tag is saved as it changes instead of rewriting a whole file.
+// The VTT stays the transcript's source of truth, and the store is rebuilt
+// from it whenever it's been changed.
+type store struct {
+	db *sql.DB
+}
+
+func storePath(vttFile string) string {
+	return strings.TrimSuffix(vttFile, ".vtt") + ".db"
+}
+
+func openStore(vttFile string) (*store, error) {
+	db, err := sql.Open("sqlite", storePath(vttFile)+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(wal)")
+	if err != nil {
+		return nil, err
+	}
+	// One connection, so a save is never waiting on another of our own
+	db.SetMaxOpenConns(1)
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not open %s: %w", storePath(vttFile), err)
+	}
+	if version != storeSchema {
+		for _, table := range []string{"lines_search", "line_tags", "words", "lines", "embeddings", "meta"} {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+	}
+
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS lines (id INTEGER PRIMARY KEY, start_time TEXT NOT NULL, start_seconds REAL NOT NULL, end_seconds REAL NOT NULL, text TEXT NOT NULL, speaker TEXT NOT NULL, selected INTEGER)`,
+		`CREATE TABLE IF NOT EXISTS line_tags (line INTEGER NOT NULL REFERENCES lines (id), tag TEXT NOT NULL, PRIMARY KEY (line, tag))`,
+		`CREATE TABLE IF NOT EXISTS words (start_seconds REAL NOT NULL, end_seconds REAL NOT NULL, text TEXT NOT NULL)`,
+		`CREATE INDEX IF NOT EXISTS words_start ON words (start_seconds)`,
+		// Keyed on the text rather than the line, so they outlive rebuilds
+		`CREATE TABLE IF NOT EXISTS embeddings (model TEXT NOT NULL, text TEXT NOT NULL, vector BLOB NOT NULL, PRIMARY KEY (model, text))`,
+		`CREATE VIRTUAL TABLE IF NOT EXISTS lines_search USING fts5(text, content='lines', content_rowid='id')`,
+		fmt.Sprintf(`PRAGMA user_version = %d`, storeSchema),
+	}
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("could not set up %s: %w", storePath(vttFile), err)
+		}
+	}
+	return &store{db: db}, nil
+}
+
+// transcriptVersion identifies the transcript and details the store was last
+// built from, so it's only rebuilt when one of them changes.
+func transcriptVersion(vttFile string) string {
+	var version []string
+	for _, file := range []string{vttFile, detailsPath(vttFile)} {
+		if info, err := os.Stat(file); err == nil {
+			version = append(version, fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano()))
+		}
+	}
+	return strings.Join(version, ",")
+}
+
+// sync rebuilds the lines and words from the transcript when it changed since
+// the last run. The selection and tags are carried over to lines starting at
+// the same time, so re-transcribing or resolving a diff keeps them.
+func (s *store) sync(inputFile, vttFile string, transcriptItems []TranscriptItem, details transcriptDetails) error {
+	if s == nil {
+		return nil
+	}
+
+	version := transcriptVersion(vttFile)
+	var current string
+	s.db.QueryRow(`SELECT value FROM meta WHERE key = 'transcript'`).Scan(&current)
+	if version != "" && current == version {
+		return nil
+	}
+
+	type lineState struct {
+		selected sql.NullBool
+		tags     []string
+	}
+	previous := map[string]*lineState{}
+	rows, err := s.db.Query(`SELECT l.start_time, l.selected, t.tag FROM lines l LEFT JOIN line_tags t ON t.line = l.id`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var startTime string
+		var selected sql.NullBool
+		var tag sql.NullString
+		if err := rows.Scan(&startTime, &selected, &tag); err != nil {
+			rows.Close()
+			return err
+		}
+		state, ok := previous[startTime]
+		if !ok {
+			state = &lineState{selected: selected}
+			previous[startTime] = state
+		}
+		if tag.Valid {
+			state.tags = append(state.tags, tag.String)
+		}
+	}
+	rows.Close()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range []string{`DELETE FROM line_tags`, `DELETE FROM lines`, `DELETE FROM words`} {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	for index, transcriptItem := range transcriptItems {
+		start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+		end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+		var selected sql.NullBool
+		var tags []string
+		if state, ok := previous[transcriptItem.StartTime]; ok {
+			selected, tags = state.selected, state.tags
+		}
+		if _, err := tx.Exec(`INSERT INTO lines (id, start_time, start_seconds, end_seconds, text, speaker, selected) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			index+1, transcriptItem.StartTime, start, end, transcriptItem.Text, transcriptItem.Speaker, selected); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if _, err := tx.Exec(`INSERT INTO line_tags (line, tag) VALUES (?, ?)`, index+1, tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	insertWord, err := tx.Prepare(`INSERT INTO words (start_seconds, end_seconds, text) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertWord.Close()
+	for _, word := range details.Words {
+		if _, err := insertWord.Exec(word.Start, word.End, word.Text); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO lines_search (lines_search) VALUES ('rebuild')`); err != nil {
+		return err
+	}
+
+	source, err := filepath.Abs(inputFile)
+	if err != nil {
+		source = inputFile
+	}
+	for key, value := range map[string]string{"transcript": version, "source": source} {
+		if _, err := tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// restore selects and tags the lines as they were left, on top of any
+// auto_select rules, which only decide the lines that were never changed.
+func (s *store) restore(items []list.Item) []list.Item {
+	if s == nil {
+		return items
+	}
+
+	rows, err := s.db.Query(`SELECT l.id, l.selected, t.tag FROM lines l LEFT JOIN line_tags t ON t.line = l.id WHERE l.selected IS NOT NULL OR t.tag IS NOT NULL ORDER BY l.id`)
+	if err != nil {
+		return items
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var selected sql.NullBool
+		var tag sql.NullString
+		if err := rows.Scan(&id, &selected, &tag); err != nil || id < 1 || id > len(items) {
+			continue
+		}
+		i, ok := items[id-1].(item)
+		if !ok {
+			continue
+		}
+		if selected.Valid {
+			i.selected = selected.Bool
+		}
+		if tag.Valid && !slices.Contains(i.tags, tag.String) {
+			i.tags = append(i.tags, tag.String)
+		}
+		items[id-1] = i
+	}
+	return items
+}
+
+// saveItem saves the selection and tags of the line at an index of the list.
+func (s *store) saveItem(index int, i item) error {
+	if s == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveLineState(tx, index, i); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveItems saves every line at once, after bulk changes and rules.
+func (s *store) saveItems(items []list.Item) error {
+	if s == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for index, listItem := range items {
+		if i, ok := listItem.(item); ok {
+			if err := saveLineState(tx, index, i); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func saveLineState(tx *sql.Tx, index int, i item) error {
+	if _, err := tx.Exec(`UPDATE lines SET selected = ? WHERE id = ?`, i.selected, index+1); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM line_tags WHERE line = ?`, index+1); err != nil {
+		return err
+	}
+	for _, tag := range i.tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO line_tags (line, tag) VALUES (?, ?)`, index+1, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *store) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// syncStore brings the store up to date with a transcript that was just
+// loaded or saved. The store only adds to what's in the VTT, so a failure is
+// reported without stopping anything.
+func (m model) syncStore(transcriptItems []TranscriptItem) model {
+	if err := m.store.sync(m.inputFile, m.vttFile, transcriptItems, m.details); err != nil {
+		m.statuses = append(m.statuses, trf("Could not update %s: %s", storePath(m.vttFile), err.Error()))
+	}
+	return m
+}
+
+// saveItem saves a line's selection and tags as soon as they're changed.
+func (m model) saveItem(index int, i item) model {
+	if err := m.store.saveItem(index, i); err != nil {
+		m.statuses = append(m.statuses, trf("Could not save the selection: %s", err.Error()))
+	}
+	return m
+}
+
+func (m model) saveItems(items []list.Item) model {
+	if err := m.store.saveItems(items); err != nil {
+		m.statuses = append(m.statuses, trf("Could not save the selection: %s", err.Error()))
+	}
+	return m
+}
+
+// errNotStore is returned for any other SQLite database found with stores.
+var errNotStore = errors.New("not a tsplice store")
+
+// openStoreReadOnly opens another video's store to search it, without
+// changing it. A store from another version of tsplice isn't read until that
+// video is opened again and it's rebuilt.
+func openStoreReadOnly(path string) (*store, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	// Like Windows' Thumbs.db, which isn't SQLite at all
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version == 0 {
+		db.Close()
+		return nil, errNotStore
+	}
+	if version != storeSchema {
+		db.Close()
+		return nil, fmt.Errorf("%s was saved by another version of tsplice, open the video to update it", path)
+	}
+	return &store{db: db}, nil
+}
+
+// source is the video the store was saved for.
+func (s *store) source() (string, error) {
+	var source string
+	if err := s.db.QueryRow(`SELECT value FROM meta WHERE key = 'source'`).Scan(&source); err != nil {
+		return "", err
+	}
+	return source, nil
+}
+
+// storeMatch is a line found by a search, with the matching words of its text
+// between \x02 and \x03, or how similar it is to a semantic search.
+type storeMatch struct {
+	start   float64
+	end     float64
+	text    string
+	speaker string
+	score   float64
+}
+
+// ftsQuery quotes every word of a query, so punctuation is searched for as is
+// instead of read as FTS5 syntax. Lines have to match every word.
+func ftsQuery(query string) string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		terms = append(terms, `"`+strings.ReplaceAll(field, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// search finds the lines matching a query, in the order they're said.
+func (s *store) search(query string) ([]storeMatch, error) {
+	rows, err := s.db.Query(`SELECT l.start_seconds, l.end_seconds, highlight(lines_search, 0, char(2), char(3)), l.speaker
+		FROM lines_search JOIN lines l ON l.id = lines_search.rowid
+		WHERE lines_search MATCH ? ORDER BY l.id`, ftsQuery(query))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []storeMatch
+	for rows.Next() {
+		var match storeMatch
+		if err := rows.Scan(&match.start, &match.end, &match.text, &match.speaker); err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// embed adds embeddings for every line that doesn't have one from the
+// embedder yet, returning how many were added.
+func (s *store) embed(embedder Embedder) (int, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT text FROM lines WHERE text NOT IN (SELECT text FROM embeddings WHERE model = ?)`, embedder.Name())
+	if err != nil {
+		return 0, err
+	}
+	var texts []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		texts = append(texts, text)
+	}
+	rows.Close()
+
+	embedded := 0
+	for start := 0; start < len(texts); start += embeddingBatch {
+		batch := texts[start:min(start+embeddingBatch, len(texts))]
+		vectors, err := embedder.Embed(batch)
+		if err != nil {
+			return embedded, err
+		}
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return embedded, err
+		}
+		for index, text := range batch {
+			if _, err := tx.Exec(`INSERT OR REPLACE INTO embeddings (model, text, vector) VALUES (?, ?, ?)`, embedder.Name(), text, encodeVector(vectors[index])); err != nil {
+				tx.Rollback()
+				return embedded, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return embedded, err
+		}
+		embedded += len(batch)
+	}
+	return embedded, nil
+}
+
+// errNoEmbeddings is returned by a semantic search of a store that was never
+// embedded with the model searched with.
+var errNoEmbeddings = errors.New("no embeddings")
+
+// semanticSearch scores every line by how similar it is to the query's
+// embedding, most similar first.
+func (s *store) semanticSearch(model string, query []float32) ([]storeMatch, error) {
+	rows, err := s.db.Query(`SELECT l.start_seconds, l.end_seconds, l.text, l.speaker, e.vector
+		FROM lines l JOIN embeddings e ON e.text = l.text AND e.model = ?`, model)
+	if err != nil {
+		// Stores from before embeddings were added don't have the table
+		return nil, errNoEmbeddings
+	}
+	defer rows.Close()
+
+	var matches []storeMatch
+	for rows.Next() {
+		var match storeMatch
+		var vector []byte
+		if err := rows.Scan(&match.start, &match.end, &match.text, &match.speaker, &vector); err != nil {
+			return nil, err
+		}
+		match.score = similarity(query, decodeVector(vector))
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, errNoEmbeddings
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].score > matches[b].score })
+	return matches, nil
+}
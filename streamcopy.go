@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// streamCopyConflicts are the options set on a compile that change its
+// picture or sound, which --copy can't do without encoding it again.
+func streamCopyConflicts(opts compileOptions) []string {
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{"--draft", opts.Draft},
+		{"--max-size", opts.MaxSize > 0},
+		{"--keep-streams", opts.KeepStreams},
+		{"--cache-segments", opts.CacheSegments},
+		{"--intro", opts.Intro != ""},
+		{"--outro", opts.Outro != ""},
+		{"--lower-thirds", opts.LowerThird != nil},
+		{"--burn-captions", opts.Captions != nil},
+		{"--remove-breaths", opts.RemoveBreaths},
+		{"--channels", opts.Channels != ""},
+		{"--speaker-channel", opts.SpeakerChannel != ""},
+		{"--sample-rate", opts.SampleRate > 0},
+		// Cuts land on keyframes, so the output always runs longer
+		{"--verify", opts.Verify},
+		{"a profile's loudness", opts.Loudness != 0},
+		{"bleeps", len(opts.Bleeps) > 0},
+		{"quiet lines", len(opts.Boosts) > 0},
+	}
+	var conflicts []string
+	for _, option := range options {
+		if option.set {
+			conflicts = append(conflicts, option.name)
+		}
+	}
+	return conflicts
+}
+
+// checkStreamCopy fails a --copy compile that asks for anything needing an
+// encode, rather than quietly leaving it out.
+func checkStreamCopy(opts compileOptions) error {
+	if !opts.StreamCopy {
+		return nil
+	}
+	if conflicts := streamCopyConflicts(opts); len(conflicts) > 0 {
+		return fmt.Errorf("--copy can't be used with %s, which need the video encoded again", strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+// compileStreamCopy cuts each segment out of the source without encoding it,
+// from the keyframe at or before its start, and joins them the same way,
+// returning the args of the join. It's much faster and loses no quality, but
+// every cut can start up to a keyframe interval early.
+func compileStreamCopy(source string, segments []segment, opts compileOptions, metadata []string, outputFile string) ([]string, error) {
+	dir, err := tempDir("tsplice-copy-")
+	if err != nil {
+		return nil, err
+	}
+	defer removeTemp(dir)
+
+	var list strings.Builder
+	for index, s := range segments {
+		segmentFile := filepath.Join(dir, fmt.Sprintf("segment_%03d.mp4", index))
+		args := []string{"-y", "-ss", fmt.Sprintf("%.3f", s.start), "-to", fmt.Sprintf("%.3f", s.end), "-i", source,
+			"-map", "0:v:0", "-map", "0:a:0?", "-c", "copy", "-avoid_negative_ts", "make_zero", segmentFile}
+		if err := runFFmpeg(opts.Progress, args...); err != nil {
+			return nil, fmt.Errorf("failed to cut segment at %s: %w", formatDuration(s.start), err)
+		}
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(segmentFile, "'", `'\''`))
+	}
+	listFile := filepath.Join(dir, "segments.txt")
+	if err := os.WriteFile(listFile, []byte(list.String()), 0644); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"-y", "-f", "concat", "-safe", "0", "-i", listFile, "-map", "0", "-c", "copy"}, metadata...)
+	args = append(args, outputFile)
+	if err := runFFmpeg(opts.Progress, args...); err != nil {
+		return nil, fmt.Errorf("failed to join segments: %w", err)
+	}
+	return args, nil
+}
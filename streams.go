@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type probedStream struct {
+	Index         int    `json:"index"`
+	CodecType     string `json:"codec_type"`
+	CodecName     string `json:"codec_name"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	RFrameRate    string `json:"r_frame_rate"`
+	AvgFrameRate  string `json:"avg_frame_rate"`
+	PixFmt        string `json:"pix_fmt"`
+	SampleRate    string `json:"sample_rate"`
+	Channels      int    `json:"channels"`
+	ChannelLayout string `json:"channel_layout"`
+	Tags          struct {
+		Language string `json:"language"`
+		Title    string `json:"title"`
+		Timecode string `json:"timecode"`
+	} `json:"tags"`
+}
+
+type probedChapter struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Tags      struct {
+		Title string `json:"title"`
+	} `json:"tags"`
+}
+
+type probeResult struct {
+	Streams  []probedStream  `json:"streams"`
+	Chapters []probedChapter `json:"chapters"`
+	Format   struct {
+		Tags struct {
+			Timecode string `json:"timecode"`
+		} `json:"tags"`
+	} `json:"format"`
+}
+
+func probeStreams(inputFile string) (probeResult, error) {
+	var result probeResult
+
+	out, err := execute.Output("ffprobe", "-v", "error", "-show_streams", "-show_chapters", "-show_format", "-of", "json", inputFile)
+	if err != nil {
+		return result, fmt.Errorf("failed to probe streams: %w", err)
+	}
+
+	if err := json.Unmarshal(out, &result); err != nil {
+		return result, fmt.Errorf("failed to parse stream info: %w", err)
+	}
+
+	return result, nil
+}
+
+// remapRange maps a range from the source onto the compiled timeline, returning
+// one piece for every selected segment it overlaps.
+func remapRange(start, end float64, segments []segment) []segment {
+	var pieces []segment
+	offset := 0.0
+
+	for _, s := range segments {
+		from, to := max(start, s.start), min(end, s.end)
+		if to > from {
+			pieces = append(pieces, segment{start: offset + from - s.start, end: offset + to - s.start})
+		}
+		offset += s.end - s.start
+	}
+
+	return pieces
+}
+
+// variableFrameRate reports whether the first video stream looks like it has
+// a variable frame rate, as screen recordings often do, and its average rate.
+// The real rate of a VFR stream is well below the rate its timebase allows.
+func variableFrameRate(probe probeResult) (string, bool) {
+	for _, stream := range probe.Streams {
+		if stream.CodecType != "video" {
+			continue
+		}
+
+		real, avg := parseFrameRate(stream.RFrameRate), parseFrameRate(stream.AvgFrameRate)
+		if real <= 0 || avg <= 0 {
+			return "", false
+		}
+		return stream.AvgFrameRate, math.Abs(real-avg)/real > 0.01
+	}
+	return "", false
+}
+
+// parseFrameRate reads ffprobe's rational frame rates like 30000/1001.
+func parseFrameRate(rate string) float64 {
+	numerator, denominator, found := strings.Cut(rate, "/")
+	n, err := strconv.ParseFloat(numerator, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return n
+	}
+	d, err := strconv.ParseFloat(denominator, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+func countStreams(probe probeResult, codecType string) int {
+	count := 0
+	for _, stream := range probe.Streams {
+		if stream.CodecType == codecType {
+			count++
+		}
+	}
+	return count
+}
+
+// keepStreamsArgs builds the extra ffmpeg inputs and output options needed to
+// carry every text subtitle track and chapter through a compile, trimmed to
+// the same segments as the main video and shifted by offset when something
+// plays before them. Audio tracks go through the filter graph.
+func keepStreamsArgs(inputFile string, probe probeResult, segments []segment, offset float64) ([]string, []string, func(), error) {
+	var tempFiles []string
+	cleanup := func() {
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+	}
+
+	var inputArgs, outputArgs []string
+	inputs := 1
+
+	audio, subtitles, keptSubtitles := 0, 0, 0
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "audio":
+			if stream.Tags.Language != "" {
+				outputArgs = append(outputArgs, fmt.Sprintf("-metadata:s:a:%d", audio), "language="+stream.Tags.Language)
+			}
+			audio++
+
+		case "subtitle":
+			index := subtitles
+			subtitles++
+
+			// Subtitles can't go through select, so rewrite their cues instead
+			subtitleFile, err := trimSubtitleStream(inputFile, index, segments)
+			if err != nil {
+				continue
+			}
+			tempFiles = append(tempFiles, subtitleFile)
+
+			if offset > 0 {
+				inputArgs = append(inputArgs, "-itsoffset", fmt.Sprintf("%.3f", offset))
+			}
+			inputArgs = append(inputArgs, "-i", subtitleFile)
+			outputArgs = append(outputArgs, "-map", fmt.Sprintf("%d:s:0", inputs))
+			inputs++
+			if stream.Tags.Language != "" {
+				outputArgs = append(outputArgs, fmt.Sprintf("-metadata:s:s:%d", keptSubtitles), "language="+stream.Tags.Language)
+			}
+			keptSubtitles++
+		}
+	}
+
+	if len(probe.Chapters) > 0 {
+		metadataFile, err := writeChapterMetadata(inputFile, probe.Chapters, segments, offset)
+		if err == nil {
+			tempFiles = append(tempFiles, metadataFile)
+			inputArgs = append(inputArgs, "-i", metadataFile)
+			outputArgs = append(outputArgs, "-map_chapters", fmt.Sprintf("%d", inputs))
+			inputs++
+		}
+	} else {
+		outputArgs = append(outputArgs, "-map_chapters", "-1")
+	}
+
+	if keptSubtitles > 0 {
+		outputArgs = append(outputArgs, "-c:s", "mov_text")
+	}
+
+	return inputArgs, outputArgs, cleanup, nil
+}
+
+func trimSubtitleStream(inputFile string, stream int, segments []segment) (string, error) {
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	subtitleFile := fmt.Sprintf("%s_sub%d.vtt", basename, stream)
+	if err := extractSubtitles(inputFile, stream, subtitleFile); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(subtitleFile)
+	if err != nil {
+		return "", err
+	}
+
+	cues, err := parseVTT(string(content))
+	if err != nil {
+		return "", err
+	}
+
+	var trimmed []TranscriptItem
+	for _, cue := range cues {
+		start, _ := parseTimeToSeconds(cue.StartTime)
+		end, _ := parseTimeToSeconds(cue.EndTime)
+		for _, piece := range remapRange(start, end, segments) {
+			trimmed = append(trimmed, TranscriptItem{
+				StartTime: formatTimestamp(piece.start),
+				EndTime:   formatTimestamp(piece.end),
+				Text:      cue.Text,
+			})
+		}
+	}
+
+	if err := os.WriteFile(subtitleFile, []byte(formatVTT(trimmed)), 0644); err != nil {
+		return "", err
+	}
+
+	return subtitleFile, nil
+}
+
+func writeChapterMetadata(inputFile string, chapters []probedChapter, segments []segment, offset float64) (string, error) {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+
+	for _, chapter := range chapters {
+		var start, end float64
+		fmt.Sscanf(chapter.StartTime, "%f", &start)
+		fmt.Sscanf(chapter.EndTime, "%f", &end)
+
+		pieces := remapRange(start, end, segments)
+		if len(pieces) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			int64((offset+pieces[0].start)*1000), int64((offset+pieces[len(pieces)-1].end)*1000), chapter.Tags.Title)
+	}
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	metadataFile := basename + "_chapters.txt"
+	if err := os.WriteFile(metadataFile, []byte(b.String()), 0644); err != nil {
+		return "", err
+	}
+
+	return metadataFile, nil
+}
+
+// exportStems writes every audio track of the selection to its own WAV file
+// next to the compiled video.
+func exportStems(inputFile, outputFile, selectFilter string, edits audioEdits, audioArgs []string) ([]string, error) {
+	probe, err := probeStreams(inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(outputFile, filepath.Ext(outputFile))
+	var stems []string
+	index := 0
+	for _, stream := range probe.Streams {
+		if stream.CodecType != "audio" {
+			continue
+		}
+
+		name := fmt.Sprintf("%s_stem%d", base, index+1)
+		if label := tagSlug(stream.Tags.Title); label != "" {
+			name += "_" + label
+		} else if label := tagSlug(stream.Tags.Language); label != "" {
+			name += "_" + label
+		}
+		stem := name + ".wav"
+
+		args := []string{"-y", "-i", inputFile,
+			"-filter_complex", strings.Join(audioFilters(index, selectFilter, edits), ";"),
+			"-map", fmt.Sprintf("[a%d]", index),
+			"-c:a", "pcm_s16le",
+		}
+		args = append(args, audioArgs...)
+		err := renderTo(stem, func(partial string) error {
+			return execute.Run("ffmpeg", append(args, partial)...)
+		})
+		if err != nil {
+			return stems, fmt.Errorf("failed to export audio stem %d: %w", index+1, err)
+		}
+
+		stems = append(stems, stem)
+		index++
+	}
+
+	return stems, nil
+}
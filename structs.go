@@ -2,7 +2,10 @@ package main
 
 import (
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type audioExtractedMsg struct {
@@ -12,6 +15,7 @@ type audioExtractedMsg struct {
 type transcriptionDoneMsg struct {
 	vttContent      string
 	transcriptItems []TranscriptItem
+	details         transcriptDetails
 }
 
 type errorMsg struct {
@@ -20,31 +24,193 @@ type errorMsg struct {
 
 type videoCompilationDoneMsg struct {
 	outputFile string
+	clips      []string // every clip saved, when the selection was split
+	warnings   []string
 }
 
 type TranscriptItem struct {
-	StartTime string
-	EndTime   string
-	Text      string
+	StartTime  string
+	EndTime    string
+	Text       string
+	Speaker    string
+	Confidence float64
 }
 
 type model struct {
-	spinner         spinner.Model
-	loading         bool
-	loadingMsg      string
-	list            list.Model
-	quitting        bool
-	inputFile       string
-	errorMsg        string
+	spinner     spinner.Model
+	loading     bool
+	loadingMsg  string
+	progress    *renderProgress
+	progressBar progress.Model
+	list        list.Model
+	quitting    bool
+	inputFile   string
+	// failure is shown on the error screen, which can run retryAudio's
+	// transcription or retryCompile's compile again
+	failure         *failure
+	enteringKey     bool
+	keyInput        textinput.Model
+	retryAudio      string
+	retryCompile    *compileOptions
+	readOnly        bool
 	gate            bool
+	resumeAudio     string
 	transcriptItems []TranscriptItem
 	statuses        []string
+	vttFile         string
+	previousItems   []TranscriptItem
+	diffing         bool
+	diffRows        []diffRow
+	diffCursor      int
+	compileOptions  compileOptions
+	rulesFile       string
+	tags            []string
+	colorRules      colorRules
+	autoSelect      autoSelectRules
+	zen             bool
+	showSummary     bool
+	showStats       bool
+	fillers         []string
+	recordingMacro  bool
+	macroKeys       []tea.KeyMsg
+	macro           []tea.KeyMsg
+	bulkPending     bool
+	ranging         bool // marking a range with V, from the line at rangeFrom
+	rangeFrom       float64
+	autoEditing     bool
+	autoEditBefore  []list.Item
+	// undoStack and redoStack are the lines from before each change
+	undoStack        []undoState
+	redoStack        []undoState
+	details          transcriptDetails
+	store            *store
+	embedder         Embedder
+	bleep            []string
+	redact           bool
+	redactNames      bool
+	redactions       []string
+	karaoke          *karaokeState
+	askTracks        int
+	audiogram        audiogramOptions
+	transcriber      Transcriber
+	tcOffset         float64
+	fps              float64
+	dropFrame        bool
+	sourceURL        string
+	language         string
+	choosingLanguage bool
+	languageInput    textinput.Model
+	naming           bool
+	namingCursor     int
+	editingSpeaker   bool
+	speakerInput     textinput.Model
+	editingText      bool
+	textInput        textinput.Model
+	splitting        bool // splitting the line at the cursor before its word at splitAt
+	splitAt          int
+	// clipsPending is set by X, so the compile the tracks are asked about is
+	// split into clips
+	clipsPending bool
+	// proxyFile is played by previews once makeProxy has finished it
+	makeProxy string
+	proxyFile string
 }
 
+type compileOptions struct {
+	KeepStreams       bool        `json:"keep_streams"`
+	XMPSidecar        bool        `json:"xmp_sidecar"`
+	Manifest          bool        `json:"manifest"`
+	Stems             bool        `json:"stems"`
+	Verify            bool        `json:"verify"`
+	TrimSilence       bool        `json:"trim_silence"`
+	SilenceThreshold  string      `json:"silence_threshold,omitempty"`
+	RemoveBreaths     bool        `json:"remove_breaths"`
+	Channels          string      `json:"channels,omitempty"`
+	SpeakerChannel    string      `json:"speaker_channel,omitempty"` // left, right, or auto
+	SampleRate        int         `json:"sample_rate,omitempty"`
+	MaxSize           int64       `json:"max_size,omitempty"`
+	Intro             string      `json:"intro,omitempty"`
+	Outro             string      `json:"outro,omitempty"`
+	ConstantFrameRate string      `json:"constant_frame_rate,omitempty"` // set for variable frame rate sources
+	Bleeps            []timeRange `json:"bleeps,omitempty"`
+	Breaths           []timeRange `json:"breaths,omitempty"`
+	Boosts            []timeRange `json:"boosts,omitempty"`
+	BoostGain         float64     `json:"boost_gain,omitempty"`
+	LeftChannel       []timeRange `json:"left_channel,omitempty"`
+	RightChannel      []timeRange `json:"right_channel,omitempty"`
+	Title             string      `json:"title,omitempty"` // meeting name from --calendar
+	Attendees         []string    `json:"attendees,omitempty"`
+	Speaker           string      `json:"speaker,omitempty"` // whose reel this is, from --speaker
+	// Loudness, in LUFS, and the codecs come from --profile
+	Loudness   float64 `json:"loudness,omitempty"`
+	VideoCodec string  `json:"video_codec,omitempty"`
+	AudioCodec string  `json:"audio_codec,omitempty"`
+	CRF        int     `json:"crf,omitempty"`
+	// LowerThird is set with --lower-thirds, LowerThirds are placed from it
+	// on each compile
+	LowerThird  *lowerThirdConfig `json:"lower_third,omitempty"`
+	LowerThirds []lowerThird      `json:"lower_thirds,omitempty"`
+	// Captions is set with --burn-captions, BurnedCaptions are placed from
+	// it on each compile with the Redactions taken out
+	Captions       *captionConfig  `json:"captions,omitempty"`
+	BurnedCaptions []burnedCaption `json:"burned_captions,omitempty"`
+	Redactions     []string        `json:"-"`
+	Draft          bool            `json:"draft,omitempty"`
+	Suffix         string          `json:"suffix,omitempty"` // output is named _<suffix> instead of _compiled
+	CacheSegments  bool            `json:"cache_segments,omitempty"`
+	StreamCopy     bool            `json:"stream_copy,omitempty"` // cut at keyframes without encoding, from --copy
+	// Split saves each segment as a clip of its own, numbered by Clip
+	Split     bool            `json:"split,omitempty"`
+	Clip      int             `json:"clip,omitempty"`
+	LocalCopy string          `json:"-"` // source copied off a network mount
+	Progress  *renderProgress `json:"-"` // followed by the editor's progress bar
+}
+
+type segment struct {
+	start, end float64
+}
+
+type timeRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// item is one transcript line in the list. Times are kept as seconds rather
+// than the VTT text, so long transcripts don't hold and reparse two strings
+// per line on every redraw.
 type item struct {
-	title     string
-	timestamp string
-	selected  bool
+	title      string
+	start      float64
+	end        float64
+	selected   bool
+	tags       []string
+	speaker    string
+	confidence float64
+	quiet      bool
+	// removal is why a previewed auto-edit would take the line out
+	removal string
 }
 
-type itemDelegate struct{}
+type itemDelegate struct {
+	colorRules colorRules
+	zen        bool
+	tcOffset   float64
+	ranging    bool
+	rangeFrom  float64
+	// editing is shown in place of the line at the cursor while its text is
+	// edited or it's split
+	editing string
+}
+
+// undoState is the lines as they were before a change, along with the
+// transcript, which merging and splitting lines change too.
+type undoState struct {
+	items      []list.Item
+	transcript []TranscriptItem
+}
+
+type diffRow struct {
+	old     *TranscriptItem
+	new     *TranscriptItem
+	keepOld bool
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const summaryWidth = 44
+
+func formatDuration(seconds float64) string {
+	total := int(seconds + 0.5)
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// summaryView lists the selected segments in output order with a running
+// total, so the shape of the final cut stays visible while browsing.
+func (m model) summaryView() string {
+	var lines []string
+	total := 0.0
+	count := 0
+
+	// Keep the pane the same height as the list, only rendering the lines
+	// that fit since the selection can run to thousands
+	height := max(1, m.list.Height()-2)
+	for _, listItem := range m.list.Items() {
+		i, ok := listItem.(item)
+		if !ok || !i.selected {
+			continue
+		}
+		s := segmentFromItem(i)
+
+		total += s.end - s.start
+		count++
+
+		if count <= height {
+			text := truncate(i.title, summaryWidth-14)
+			lines = append(lines, DimTextStyle.Render(fmt.Sprintf("%7s ", formatDuration(total)))+TextStyle.Render(text))
+		}
+	}
+	if count > height {
+		hidden := count - height + 1
+		lines = append(lines[:height-1], DimTextStyle.Render("        "+trf("… and %d more", hidden)))
+	}
+	if count == 0 {
+		lines = append(lines, DimTextStyle.Render(tr("Nothing selected yet")))
+	}
+
+	header := TitleStyle.Render(trf("Selected (%d)", count)) + DimTextStyle.Render(" "+trf("total %s", formatDuration(total)))
+	return SummaryPaneStyle.Width(summaryWidth).Render(header + "\n\n" + strings.Join(lines, "\n"))
+}
+
+func (m model) listView() string {
+	if !m.showSummary {
+		return m.list.View()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), m.summaryView())
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// supercutLoudness is what every clip is brought to, in LUFS, so sources
+// recorded at different levels don't jump in volume from one clip to the next.
+const supercutLoudness = -16
+
+// supercutClip is a piece of any source to put in a supercut.
+type supercutClip struct {
+	source string
+	start  float64
+	end    float64
+}
+
+// supercutFlags are the options of tsplice supercut.
+type supercutFlags struct {
+	pad    float64
+	output string
+}
+
+func (f *supercutFlags) define(fs *flag.FlagSet) {
+	fs.Float64Var(&f.pad, "pad", 0.15, "Seconds kept before and after every time it's said")
+	fs.StringVar(&f.output, "output", "", "File to save the supercut to, instead of supercut_<phrase>.mp4")
+}
+
+func runSupercut(args []string) error {
+	fs := newCommandFlagSet("supercut")
+	var flags supercutFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 || strings.TrimSpace(positional[0]) == "" {
+		return fmt.Errorf("usage: tsplice supercut [options] <phrase> <input-file>...")
+	}
+	if flags.pad < 0 {
+		return fmt.Errorf("--pad can't be negative")
+	}
+	phrase := positional[0]
+
+	var clips []supercutClip
+	for _, inputFile := range positional[1:] {
+		if err := validateInputFile(inputFile); err != nil {
+			return err
+		}
+		if _, err := os.Stat(vttPath(inputFile)); os.IsNotExist(err) && transcribesWithOpenAI() {
+			if err := setupAPIKey(); err != nil {
+				return err
+			}
+		}
+
+		transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+		if err != nil {
+			return err
+		}
+		details, err := loadDetails(vttPath(inputFile))
+		if err != nil {
+			return err
+		}
+		words := details.Words
+		if len(words) == 0 {
+			fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render(filepath.Base(inputFile)+" has no word timestamps, so its cuts are estimated from the lines."))
+			words = estimateWords(transcriptItems)
+		}
+
+		found := phraseClips(inputFile, words, phrase, flags.pad)
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Found %d clips of %q in %s", len(found), phrase, filepath.Base(inputFile))))
+		clips = append(clips, found...)
+	}
+	if len(clips) == 0 {
+		return fmt.Errorf("%q is never said", phrase)
+	}
+
+	outputFile := flags.output
+	if outputFile == "" {
+		outputFile = supercutPath(phrase)
+	}
+	if err := compileSupercut(clips, outputFile); err != nil {
+		return err
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Saved a supercut of %d clips to %s", len(clips), outputFile)))
+	return nil
+}
+
+// phraseClips finds every time a phrase is said, padded on both ends. Times
+// close enough together to overlap once padded are kept as one clip, so no
+// moment plays twice.
+func phraseClips(source string, words []Word, phrase string, pad float64) []supercutClip {
+	var clips []supercutClip
+	matchTerms(words, []string{phrase}, func(first, last int) {
+		start, end := max(0, words[first].Start-pad), words[last].End+pad
+		if n := len(clips); n > 0 && start <= clips[n-1].end {
+			clips[n-1].end = max(clips[n-1].end, end)
+			return
+		}
+		clips = append(clips, supercutClip{source: source, start: start, end: end})
+	})
+	return clips
+}
+
+// supercutPrefix starts the name of every supercut.
+const supercutPrefix = "supercut_"
+
+// supercutPath names a supercut after what it's made of, in the current folder.
+func supercutPath(name string) string {
+	slug := tagSlug(name)
+	if slug == "" {
+		slug = "matches"
+	}
+	return supercutPrefix + slug + ".mp4"
+}
+
+// compileSupercut cuts every clip out of its source and joins them in order.
+// Sources can differ in resolution, frame rate, and audio format, so each clip
+// is normalized to the format of the first clip's source, and to the same
+// loudness, as it's cut. The clips then all match, and are joined without
+// encoding them again.
+func compileSupercut(clips []supercutClip, outputFile string) error {
+	if len(clips) == 0 {
+		return fmt.Errorf("no clips to compile")
+	}
+
+	probes := map[string]probeResult{}
+	for _, clip := range clips {
+		if _, ok := probes[clip.source]; ok {
+			continue
+		}
+		probe, err := probeStreams(clip.source)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", clip.source, err)
+		}
+		if countStreams(probe, "video") == 0 {
+			return fmt.Errorf("%s has no video stream", clip.source)
+		}
+		probes[clip.source] = probe
+	}
+	format := formatOf(probes[clips[0].source])
+
+	dir, err := tempDir("tsplice-supercut-*")
+	if err != nil {
+		return err
+	}
+	defer removeTemp(dir)
+
+	var list strings.Builder
+	for index, clip := range clips {
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Cutting clip %d of %d from %s...", index+1, len(clips), filepath.Base(clip.source))))
+
+		duration := clip.end - clip.start
+		filters := []string{format.normalizeVideo("[0:v:0]", "[v]")}
+		if countStreams(probes[clip.source], "audio") > 0 {
+			filters = append(filters,
+				fmt.Sprintf("[0:a:0]loudnorm=I=%d:TP=-1.5:LRA=11[l]", supercutLoudness),
+				format.normalizeAudio("[l]", "[a]"))
+		} else {
+			filters = append(filters, format.silence(duration, "[a]"))
+		}
+
+		clipFile := filepath.Join(dir, fmt.Sprintf("%04d.mp4", index))
+		args := []string{"-y", "-ss", fmt.Sprintf("%.3f", clip.start), "-t", fmt.Sprintf("%.3f", duration), "-i", clip.source,
+			"-filter_complex", strings.Join(filters, ";"), "-map", "[v]", "-map", "[a]", "-t", fmt.Sprintf("%.3f", duration)}
+		args = append(args, videoEncoderArgs(false)...)
+		args = append(args, "-c:a", "aac", "-ar", strconv.Itoa(format.sampleRate), clipFile)
+		if err := execute.Run("ffmpeg", args...); err != nil {
+			return fmt.Errorf("failed to cut %s at %s: %w", filepath.Base(clip.source), formatDuration(clip.start), err)
+		}
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(clipFile, "'", `'\''`))
+	}
+
+	listFile := filepath.Join(dir, "clips.txt")
+	if err := os.WriteFile(listFile, []byte(list.String()), 0644); err != nil {
+		return err
+	}
+	return renderTo(outputFile, func(partial string) error {
+		if err := execute.Run("ffmpeg", "-y", "-f", "concat", "-safe", "0", "-i", listFile, "-map", "0", "-c", "copy", "-movflags", "+faststart", partial); err != nil {
+			return fmt.Errorf("failed to join the clips: %w", err)
+		}
+		return nil
+	})
+}
+
+// parsePicks reads a list of numbers and ranges like "1,3-5" from 1 to n,
+// returning them as indexes in the order given.
+func parsePicks(answer string, n int) ([]int, error) {
+	var picks []int
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to, isRange := strings.Cut(field, "-")
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("%q isn't a number or a range like 3-5", field)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(to); err != nil {
+				return nil, fmt.Errorf("%q isn't a number or a range like 3-5", field)
+			}
+		}
+		if first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("pick matches between 1 and %d", n)
+		}
+		for pick := first; pick <= last; pick++ {
+			picks = append(picks, pick-1)
+		}
+	}
+	return picks, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func toggleTag(tags []string, tag string) []string {
+	if index := slices.Index(tags, tag); index >= 0 {
+		return slices.Delete(slices.Clone(tags), index, index+1)
+	}
+	return append(slices.Clone(tags), tag)
+}
+
+func formatTags(tags []string) string {
+	var formatted []string
+	for _, tag := range tags {
+		formatted = append(formatted, "#"+tagSlug(tag))
+	}
+	return strings.Join(formatted, " ")
+}
+
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+func tagSlug(tag string) string {
+	return strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(tag), "-"), "-")
+}
+
+// tagFilter wraps the default fuzzy filter so that #tag terms only match items
+// carrying every one of those tags, e.g. "#hook #cut intro".
+func tagFilter(term string, targets []string) []list.Rank {
+	var tagTerms, textTerms []string
+	for _, field := range strings.Fields(term) {
+		if strings.HasPrefix(field, "#") && len(field) > 1 {
+			tagTerms = append(tagTerms, strings.ToLower(field))
+		} else {
+			textTerms = append(textTerms, field)
+		}
+	}
+
+	if len(tagTerms) == 0 {
+		return list.DefaultFilter(term, targets)
+	}
+
+	var indexes []int
+	var remaining []string
+	for index, target := range targets {
+		fields := strings.Fields(strings.ToLower(target))
+		matches := true
+		for _, tag := range tagTerms {
+			if !slices.Contains(fields, tag) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			indexes = append(indexes, index)
+			remaining = append(remaining, target)
+		}
+	}
+
+	if len(textTerms) == 0 {
+		ranks := make([]list.Rank, len(indexes))
+		for i, index := range indexes {
+			ranks[i] = list.Rank{Index: index}
+		}
+		return ranks
+	}
+
+	ranks := list.DefaultFilter(strings.Join(textTerms, " "), remaining)
+	for i := range ranks {
+		ranks[i].Index = indexes[ranks[i].Index]
+	}
+	return ranks
+}
+
+// exportTagCutLists writes a CSV of time ranges for every tag in use.
+func exportTagCutLists(inputFile string, items []list.Item, tcOffset float64) ([]string, error) {
+	rows := map[string][]string{}
+	var order []string
+
+	for _, listItem := range items {
+		i, ok := listItem.(item)
+		if !ok {
+			continue
+		}
+		timestamps := []string{formatTimestamp(i.start + tcOffset), formatTimestamp(i.end + tcOffset)}
+		for _, tag := range i.tags {
+			if _, ok := rows[tag]; !ok {
+				order = append(order, tag)
+			}
+			rows[tag] = append(rows[tag], fmt.Sprintf("%s,%s,\"%s\"", timestamps[0], timestamps[len(timestamps)-1], strings.ReplaceAll(i.title, "\"", "\"\"")))
+		}
+	}
+
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no segments have been tagged")
+	}
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	var files []string
+	for _, tag := range order {
+		file := filepath.Join(filepath.Dir(inputFile), fmt.Sprintf("%s_%s.csv", basename, tagSlug(tag)))
+		content := "start,end,text\n" + strings.Join(rows[tag], "\n") + "\n"
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tightenFillers are cut by default. The config's fillers are for counting,
+// and include words like "like" that are often meant, so only hesitations
+// are cut unless --fillers says otherwise.
+var tightenFillers = []string{"um", "uh", "uhm", "erm", "ah", "hmm", "mm"}
+
+// tightSpan is a run of kept words, by their indexes.
+type tightSpan struct {
+	first, last int
+}
+
+// tightenFlags are the options of tsplice tighten.
+type tightenFlags struct {
+	maxGap      float64
+	pad         float64
+	fillers     string
+	keepFillers bool
+	prompt      string
+	profileName string
+}
+
+func (f *tightenFlags) define(fs *flag.FlagSet) {
+	fs.Float64Var(&f.maxGap, "max-gap", 0.5, "Longest pause kept between words, in seconds")
+	fs.Float64Var(&f.pad, "pad", 0.1, "Seconds kept before and after speech at every cut")
+	fs.StringVar(&f.fillers, "fillers", strings.Join(tightenFillers, ","), "Comma-separated words and phrases to cut")
+	fs.BoolVar(&f.keepFillers, "keep-fillers", false, "Only cut pauses, leaving filler words in")
+	fs.StringVar(&f.prompt, "prompt", defaultPrompt, "Prompt used if the video has to be transcribed first")
+	fs.StringVar(&f.profileName, "profile", activeProfile, "Automatic editing settings to start from, which the flags above override")
+}
+
+func runTighten(args []string) error {
+	fs := newCommandFlagSet("tighten")
+	var flags tightenFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice tighten [options] <input-file>")
+	}
+
+	// The profile fills in whatever wasn't given on the command line
+	p, err := findProfile(flags.profileName)
+	if err != nil {
+		return err
+	}
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	if !given["max-gap"] && p.MaxGap > 0 {
+		flags.maxGap = p.MaxGap
+	}
+	if !given["pad"] && p.Pad > 0 {
+		flags.pad = p.Pad
+	}
+	if !given["fillers"] && len(p.Fillers) > 0 {
+		flags.fillers = strings.Join(p.Fillers, ",")
+	}
+	if flags.maxGap < 0 || flags.pad < 0 {
+		return fmt.Errorf("--max-gap and --pad can't be negative")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+	if _, err := os.Stat(vttPath(inputFile)); os.IsNotExist(err) && transcribesWithOpenAI() {
+		if err := setupAPIKey(); err != nil {
+			return err
+		}
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, newTranscriber(defaultLanguage, flags.prompt))
+	if err != nil {
+		return err
+	}
+	details, err := loadDetails(vttPath(inputFile))
+	if err != nil {
+		return err
+	}
+	words := details.Words
+	if len(words) == 0 {
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render("The transcript has no word timestamps, so only the pauses between lines can be cut."))
+		words = estimateWords(transcriptItems)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("nothing is said in %s", filepath.Base(inputFile))
+	}
+
+	cut := make([]bool, len(words))
+	cutFillers := 0
+	if !flags.keepFillers {
+		matchTerms(words, strings.Split(flags.fillers, ","), func(first, last int) {
+			for index := first; index <= last; index++ {
+				cut[index] = true
+			}
+			cutFillers++
+		})
+	}
+
+	spans := tightSpans(words, cut, flags.maxGap)
+	if len(spans) == 0 {
+		return fmt.Errorf("everything said in %s is a filler word", filepath.Base(inputFile))
+	}
+	segments := padSpans(words, cut, spans, flags.pad)
+
+	sourceDuration, err := probeDuration(inputFile)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", inputFile, err)
+	}
+	var kept float64
+	for index := range segments {
+		segments[index].end = min(segments[index].end, sourceDuration)
+		kept += segments[index].end - segments[index].start
+	}
+
+	opts := compileOptions{Suffix: "tight"}
+	p.apply(&opts)
+	if p.VideoCodec != "" || p.AudioCodec != "" {
+		if _, err := probeEncoders(); err != nil {
+			return err
+		}
+		if err := p.checkEncoders(); err != nil {
+			return err
+		}
+	}
+	if err := preflightCompile(inputFile, segments, opts); err != nil {
+		return err
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Cutting %d filler words and %d pauses with ffmpeg...", cutFillers, pausesCut(words, spans, flags.maxGap))))
+	outputFile, err := compileSegments(inputFile, segments, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Saved %s, %s down from %s", outputFile, formatDuration(kept), formatDuration(sourceDuration))))
+	return nil
+}
+
+// tightSpans groups the words that aren't cut into runs, starting a new run
+// at every pause longer than maxGap and wherever a word was cut.
+func tightSpans(words []Word, cut []bool, maxGap float64) []tightSpan {
+	var spans []tightSpan
+	for index, word := range words {
+		if cut[index] {
+			continue
+		}
+		if n := len(spans); n > 0 && spans[n-1].last == index-1 && word.Start-words[index-1].End <= maxGap {
+			spans[n-1].last = index
+			continue
+		}
+		spans = append(spans, tightSpan{first: index, last: index})
+	}
+	return spans
+}
+
+// padSpans turns runs of words into segments with a little room on either
+// side, never reaching into a cut word or past halfway to the next run.
+func padSpans(words []Word, cut []bool, spans []tightSpan, pad float64) []segment {
+	segments := make([]segment, len(spans))
+	for index, span := range spans {
+		start, end := words[span.first].Start, words[span.last].End
+
+		lo := 0.0
+		if before := span.first - 1; before >= 0 {
+			lo = words[before].End
+			if !cut[before] {
+				lo = (words[before].End + start) / 2
+			}
+		}
+		hi := end + pad
+		if after := span.last + 1; after < len(words) {
+			hi = words[after].Start
+			if !cut[after] {
+				hi = (end + words[after].Start) / 2
+			}
+		}
+
+		segments[index] = segment{start: max(start-pad, lo), end: min(end+pad, hi)}
+	}
+	return segments
+}
+
+// pausesCut counts the gaps between runs that were cut for being too long,
+// rather than for a filler word.
+func pausesCut(words []Word, spans []tightSpan, maxGap float64) int {
+	pauses := 0
+	for index := 1; index < len(spans); index++ {
+		if previous := spans[index-1].last; spans[index].first == previous+1 && words[spans[index].first].Start-words[previous].End > maxGap {
+			pauses++
+		}
+	}
+	return pauses
+}
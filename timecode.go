@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// parseTimecode reads an offset given as camera timecode (01:00:00:00, or
+// 01:00:00;00 for drop frame at 29.97 and 59.94 fps), a timestamp
+// (01:00:00.000), or plain seconds. Timecode counts frames, so at 23.976 and
+// 29.97 non-drop frame it runs behind the clock.
+func parseTimecode(tc string, fps float64) (float64, error) {
+	tc = strings.TrimSpace(tc)
+	if seconds, err := strconv.ParseFloat(tc, 64); err == nil {
+		return seconds, nil
+	}
+
+	parts := strings.FieldsFunc(tc, func(r rune) bool { return r == ':' || r == ';' })
+	if len(parts) == 4 {
+		var values [4]int
+		for index, part := range parts {
+			value, err := strconv.Atoi(part)
+			if err != nil || value < 0 {
+				return 0, fmt.Errorf("invalid timecode %q", tc)
+			}
+			values[index] = value
+		}
+		hours, minutes, secs, frames := values[0], values[1], values[2], values[3]
+
+		rate := int(math.Round(fps))
+		if minutes > 59 || secs > 59 || frames >= rate {
+			return 0, fmt.Errorf("invalid timecode %q at %.3f fps", tc, fps)
+		}
+		count := rate*(hours*3600+minutes*60+secs) + frames
+
+		if strings.Contains(tc, ";") {
+			if !isDropFrameRate(fps) {
+				return 0, fmt.Errorf("drop frame timecode %q needs a 29.97 or 59.94 fps video, this one is %.3f fps", tc, fps)
+			}
+			// The first frame numbers of every minute but each tenth are skipped
+			drop := rate / 15
+			if minutes%10 != 0 && secs == 0 && frames < drop {
+				return 0, fmt.Errorf("invalid timecode %q, drop frame skips it", tc)
+			}
+			totalMinutes := hours*60 + minutes
+			count -= drop * (totalMinutes - totalMinutes/10)
+		}
+		return float64(count) / fps, nil
+	}
+
+	seconds, err := parseTimeToSeconds(tc)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timecode %q", tc)
+	}
+	return seconds, nil
+}
+
+// isDropFrameRate is whether fps is 30000/1001 or 60000/1001, the rates drop
+// frame timecode exists for.
+func isDropFrameRate(fps float64) bool {
+	return math.Abs(fps-30000.0/1001) < 0.005 || math.Abs(fps-60000.0/1001) < 0.005
+}
+
+// usesDropFrame is whether timecode at fps is counted drop frame: always at
+// 29.97 and 59.94 fps, unless the camera's own timecode says otherwise.
+func usesDropFrame(tc string, fps float64) bool {
+	return isDropFrameRate(fps) && !(strings.Count(tc, ":") == 3 && !strings.Contains(tc, ";"))
+}
+
+// formatTimecode writes seconds as HH:MM:SS:FF, or HH:MM:SS;FF when counting
+// drop frame.
+func formatTimecode(seconds float64, fps float64, dropFrame bool) string {
+	rate := int(math.Round(fps))
+	frames := int(math.Round(seconds * fps))
+	separator := ":"
+	if dropFrame {
+		// Add back the frame numbers skipped so far, two (or four at 59.94)
+		// at each minute but every tenth
+		drop := rate / 15
+		perMinute := rate*60 - drop
+		perTenMinutes := perMinute*10 + drop
+		tens, rest := frames/perTenMinutes, frames%perTenMinutes
+		frames += 9 * drop * tens
+		if rest > drop {
+			frames += drop * ((rest - drop) / perMinute)
+		}
+		separator = ";"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", frames/(3600*rate), frames/(60*rate)%60, frames/rate%60, separator, frames%rate)
+}
+
+// timecodeInfo returns the frame rate of the first video stream and the start
+// timecode written by the camera, if there is one.
+func timecodeInfo(probe probeResult) (float64, string) {
+	fps := 30.0
+	timecode := probe.Format.Tags.Timecode
+
+	videoFound := false
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "video" && !videoFound {
+			videoFound = true
+			if rate := parseFrameRate(stream.AvgFrameRate); rate > 0 {
+				fps = rate
+			}
+		}
+		if timecode == "" {
+			timecode = stream.Tags.Timecode
+		}
+	}
+
+	return fps, timecode
+}
+
+// offsetTimestamp shifts a "start - end" or single timestamp by the offset.
+func offsetTimestamp(timestamp string, offset float64) string {
+	if offset == 0 {
+		return timestamp
+	}
+
+	parts := strings.Split(timestamp, " - ")
+	for index, part := range parts {
+		if seconds, err := parseTimeToSeconds(part); err == nil {
+			parts[index] = formatTimestamp(seconds + offset)
+		}
+	}
+	return strings.Join(parts, " - ")
+}
+
+// exportEDL writes the selected segments as a CMX 3600 edit decision list,
+// with source timecode matching the camera.
+func exportEDL(inputFile string, items []list.Item, offset float64, fps float64, dropFrame bool) (string, error) {
+	segments := selectedSegments(items)
+	if len(segments) == 0 {
+		return "", fmt.Errorf("no segments selected")
+	}
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+
+	var b strings.Builder
+	fcm := "NON-DROP FRAME"
+	if dropFrame {
+		fcm = "DROP FRAME"
+	}
+	fmt.Fprintf(&b, "TITLE: %s\nFCM: %s\n\n", basename, fcm)
+
+	record := 0.0
+	for index, s := range segments {
+		duration := s.end - s.start
+		fmt.Fprintf(&b, "%03d  AX       AA/V  C        %s %s %s %s\n",
+			index+1,
+			formatTimecode(s.start+offset, fps, dropFrame), formatTimecode(s.end+offset, fps, dropFrame),
+			formatTimecode(record, fps, dropFrame), formatTimecode(record+duration, fps, dropFrame),
+		)
+		fmt.Fprintf(&b, "* FROM CLIP NAME: %s\n\n", filepath.Base(inputFile))
+		record += duration
+	}
+
+	edlFile := filepath.Join(filepath.Dir(inputFile), basename+".edl")
+	if err := os.WriteFile(edlFile, []byte(b.String()), 0644); err != nil {
+		return "", err
+	}
+
+	return edlFile, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	fps23976 = 24000.0 / 1001
+	fps2997  = 30000.0 / 1001
+	fps5994  = 60000.0 / 1001
+)
+
+func TestFormatTimecodeCountsFrames(t *testing.T) {
+	tests := []struct {
+		frames    int
+		fps       float64
+		dropFrame bool
+		want      string
+	}{
+		{86400, fps23976, false, "01:00:00:00"},
+		{90000, 25, false, "01:00:00:00"},
+		{108000, 30, false, "01:00:00:00"},
+		{108000, fps2997, false, "01:00:00:00"},
+		{1799, fps2997, true, "00:00:59;29"},
+		{1800, fps2997, true, "00:01:00;02"},
+		{17982, fps2997, true, "00:10:00;00"},
+		{107892, fps2997, true, "01:00:00;00"},
+		{3600, fps5994, true, "00:01:00;04"},
+		{215784, fps5994, true, "01:00:00;00"},
+	}
+	for _, test := range tests {
+		if got := formatTimecode(float64(test.frames)/test.fps, test.fps, test.dropFrame); got != test.want {
+			t.Errorf("frame %d at %.3f fps is %s, want %s", test.frames, test.fps, got, test.want)
+		}
+	}
+}
+
+func TestTimecodeRoundTrips(t *testing.T) {
+	rates := []struct {
+		fps       float64
+		dropFrame bool
+	}{
+		{fps23976, false},
+		{25, false},
+		{fps2997, false},
+		{fps2997, true},
+		{fps5994, true},
+	}
+	frames := []int{0, 1, 29, 1799, 1800, 1801, 17981, 17982, 17983, 107892, 107893, 123456}
+	for _, rate := range rates {
+		for _, frame := range frames {
+			seconds := float64(frame) / rate.fps
+			tc := formatTimecode(seconds, rate.fps, rate.dropFrame)
+			parsed, err := parseTimecode(tc, rate.fps)
+			if err != nil {
+				t.Errorf("%s at %.3f fps: %v", tc, rate.fps, err)
+				continue
+			}
+			if math.Abs(parsed-seconds) > 1e-6 {
+				t.Errorf("%s at %.3f fps is %.6fs, want %.6fs", tc, rate.fps, parsed, seconds)
+			}
+			if again := formatTimecode(parsed, rate.fps, rate.dropFrame); again != tc {
+				t.Errorf("%s at %.3f fps came back as %s", tc, rate.fps, again)
+			}
+		}
+	}
+}
+
+func TestParseTimecodeRejectsWhatCantBe(t *testing.T) {
+	tests := []struct {
+		tc  string
+		fps float64
+	}{
+		// Drop frame skips the first two frame numbers of the minute
+		{"00:01:00;00", fps2997},
+		{"00:01:00;03", fps5994},
+		{"01:00:00;00", 25},
+		{"00:00:00:30", fps2997},
+		{"00:60:00:00", 25},
+	}
+	for _, test := range tests {
+		if seconds, err := parseTimecode(test.tc, test.fps); err == nil {
+			t.Errorf("%s at %.3f fps read as %.3fs, want an error", test.tc, test.fps, seconds)
+		}
+	}
+}
+
+func TestUsesDropFrame(t *testing.T) {
+	tests := []struct {
+		tc   string
+		fps  float64
+		want bool
+	}{
+		{"", fps2997, true},
+		{"01:00:00;00", fps2997, true},
+		{"01:00:00:00", fps2997, false},
+		{"", fps5994, true},
+		{"", 25, false},
+		{"", fps23976, false},
+	}
+	for _, test := range tests {
+		if got := usesDropFrame(test.tc, test.fps); got != test.want {
+			t.Errorf("usesDropFrame(%q, %.3f) = %v, want %v", test.tc, test.fps, got, test.want)
+		}
+	}
+}
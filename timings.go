@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timings is turned on with --timings, recording how long each stage took so
+// they can be broken down when tsplice exits. Stages that run more than once,
+// like a transcription per participant, are added up.
+var timings struct {
+	mu      sync.Mutex
+	enabled bool
+	stages  []string
+	elapsed map[string]time.Duration
+}
+
+// recordTiming adds the time since started to a stage, and is meant to be
+// deferred with time.Now() when the stage begins.
+func recordTiming(stage string, started time.Time) {
+	addTiming(stage, time.Since(started))
+}
+
+func addTiming(stage string, elapsed time.Duration) {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	if !timings.enabled {
+		return
+	}
+	if timings.elapsed == nil {
+		timings.elapsed = map[string]time.Duration{}
+	}
+	if _, ok := timings.elapsed[stage]; !ok {
+		timings.stages = append(timings.stages, stage)
+	}
+	timings.elapsed[stage] += elapsed
+}
+
+// printTimings shows every stage in the order they first ran, with their share
+// of the total.
+func printTimings() {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	if !timings.enabled {
+		return
+	}
+	if len(timings.stages) == 0 {
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Timings: no stages ran."))
+		return
+	}
+
+	var total time.Duration
+	for _, stage := range timings.stages {
+		total += timings.elapsed[stage]
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Timings:"))
+	for _, stage := range timings.stages {
+		elapsed := timings.elapsed[stage]
+		spaces := strings.Repeat(" ", max(2, 20-len(stage)))
+		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(stage) + DimTextStyle.Render(fmt.Sprintf("%s%8s  %3.0f%%", spaces, elapsed.Round(time.Millisecond), float64(elapsed)/float64(max(total, 1))*100)))
+	}
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Total: "+total.Round(time.Millisecond).String()))
+}
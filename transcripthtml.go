@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const transcriptPageScript = `
+const video = document.querySelector("video");
+const lines = [...document.querySelectorAll(".line")];
+for (const line of lines) {
+  line.addEventListener("click", (event) => {
+    if (!video) return;
+    event.preventDefault();
+    video.currentTime = parseFloat(line.dataset.start);
+    video.play();
+  });
+}
+if (video) {
+  video.addEventListener("timeupdate", () => {
+    for (const line of lines) {
+      const active = video.currentTime >= parseFloat(line.dataset.start) && video.currentTime < parseFloat(line.dataset.end);
+      line.classList.toggle("active", active);
+    }
+  });
+}
+document.querySelector("#search").addEventListener("input", (event) => {
+  const terms = event.target.value.toLowerCase().split(/\s+/).filter(Boolean);
+  for (const line of lines) {
+    const text = line.textContent.toLowerCase();
+    line.hidden = !terms.every((term) => text.includes(term));
+  }
+});
+`
+
+// transcriptPage builds a standalone page where every line seeks the video.
+// With a YouTube URL each line links to its timestamp there instead, since
+// the video can't be embedded and seeked directly.
+func transcriptPage(title string, transcriptItems []TranscriptItem, videoSrc string) string {
+	youtube := isYouTubeURL(videoSrc)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+video { width: 100%%; position: sticky; top: 0; background: #000; }
+#search { width: 100%%; padding: 0.5rem; margin: 1rem 0; font-size: 1rem; box-sizing: border-box; }
+.line { display: block; padding: 0.25rem 0.5rem; color: inherit; text-decoration: none; border-radius: 4px; cursor: pointer; }
+.line:hover { background: #f2f2f2; }
+.line.active { background: #fff3c4; }
+.time { color: #888; font-variant-numeric: tabular-nums; margin-right: 0.5rem; }
+.speaker { font-weight: bold; margin-right: 0.25rem; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+`, html.EscapeString(title), html.EscapeString(title))
+
+	if !youtube {
+		fmt.Fprintf(&b, "<video src=\"%s\" controls preload=\"metadata\"></video>\n", html.EscapeString(videoSrc))
+	}
+	b.WriteString("<input id=\"search\" type=\"search\" placeholder=\"Search the transcript\">\n")
+
+	for _, transcriptItem := range transcriptItems {
+		start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+		end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+
+		href := "#"
+		if youtube {
+			href = deepLink(videoSrc, start)
+		}
+
+		fmt.Fprintf(&b, "<a class=\"line\" href=\"%s\" data-start=\"%.3f\" data-end=\"%.3f\"><span class=\"time\">%s</span>",
+			html.EscapeString(href), start, end, formatDuration(start))
+		if transcriptItem.Speaker != "" {
+			fmt.Fprintf(&b, "<span class=\"speaker\">%s:</span>", html.EscapeString(transcriptItem.Speaker))
+		}
+		b.WriteString(html.EscapeString(transcriptItem.Text) + "</a>\n")
+	}
+
+	b.WriteString("<script>" + transcriptPageScript + "</script>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// htmlFlags are the options of tsplice html.
+type htmlFlags struct {
+	url string
+}
+
+func (f *htmlFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.url, "url", "", "Link to the published video instead of the local file")
+}
+
+func runHTML(args []string) error {
+	fs := newCommandFlagSet("html")
+	var flags htmlFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice html [options] <input-file>")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(vttPath(inputFile)); os.IsNotExist(err) && transcribesWithOpenAI() {
+		if err := setupAPIKey(); err != nil {
+			return err
+		}
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+	if err != nil {
+		return err
+	}
+
+	// The page sits next to the video, so a relative link plays the local copy
+	videoSrc := flags.url
+	if videoSrc == "" {
+		videoSrc = filepath.Base(inputFile)
+	}
+
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	pageFile := filepath.Join(filepath.Dir(inputFile), basename+".html")
+	if err := os.WriteFile(pageFile, []byte(transcriptPage(basename, transcriptItems, videoSrc)), 0644); err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Saved transcript page to "+pageFile))
+	return nil
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	translationModel = "gpt-4o-mini"
+	// translationBatch is how many lines are sent per request, small enough
+	// that the model reliably returns every one of them
+	translationBatch = 80
+)
+
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// chatCompletion sends a single system and user message to the chat API and
+// returns the reply, which is asked to be a JSON object.
+func chatCompletion(system string, user string) (string, error) {
+	if err := requireOnline("the transcript to OpenAI"); err != nil {
+		return "", err
+	}
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return "", fmt.Errorf("OPENAI_API_KEY environment variable is not set")
+	}
+
+	body, err := json.Marshal(chatRequest{
+		Model: translationModel,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var chat chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(chat.Choices) == 0 {
+		return "", fmt.Errorf("API returned no reply")
+	}
+
+	return chat.Choices[0].Message.Content, nil
+}
+
+// translateLines translates every line into the target language, keeping them
+// in the same order so they line up with the original cues.
+func translateLines(lines []string, language string) ([]string, error) {
+	system := fmt.Sprintf("You translate video captions into the language with code %q. "+
+		"You receive a JSON object with a \"lines\" array and reply with a JSON object with a \"lines\" array "+
+		"holding the translation of each line, in the same order and with exactly the same number of entries. "+
+		"Keep each translation about as short as the original so it fits on screen.", language)
+
+	var translated []string
+	for start := 0; start < len(lines); start += translationBatch {
+		batch := lines[start:min(start+translationBatch, len(lines))]
+
+		request, err := json.Marshal(map[string][]string{"lines": batch})
+		if err != nil {
+			return nil, err
+		}
+
+		reply, err := chatCompletion(system, string(request))
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Lines []string `json:"lines"`
+		}
+		if err := json.Unmarshal([]byte(reply), &result); err != nil {
+			return nil, fmt.Errorf("could not read translation: %w", err)
+		}
+		if len(result.Lines) != len(batch) {
+			return nil, fmt.Errorf("translation returned %d lines for %d captions", len(result.Lines), len(batch))
+		}
+
+		translated = append(translated, result.Lines...)
+	}
+
+	return translated, nil
+}
+
+// translateTranscript returns a copy of the transcript with translated text.
+func translateTranscript(transcriptItems []TranscriptItem, language string) ([]TranscriptItem, error) {
+	lines := make([]string, len(transcriptItems))
+	for index, transcriptItem := range transcriptItems {
+		lines[index] = transcriptItem.Text
+	}
+
+	translatedLines, err := translateLines(lines, language)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := make([]TranscriptItem, len(transcriptItems))
+	for index, transcriptItem := range transcriptItems {
+		transcriptItem.Text = strings.TrimSpace(translatedLines[index])
+		translated[index] = transcriptItem
+	}
+	return translated, nil
+}
+
+// stackCaptions puts each original line above its translation.
+func stackCaptions(original, translated []TranscriptItem) []TranscriptItem {
+	stacked := make([]TranscriptItem, len(original))
+	for index, transcriptItem := range original {
+		transcriptItem.Text += "\n" + translated[index].Text
+		stacked[index] = transcriptItem
+	}
+	return stacked
+}
+
+// burnCaptions renders a copy of the video with the captions drawn on it.
+func burnCaptions(inputFile string, captionsFile string, outputFile string) error {
+	return renderTo(outputFile, func(partial string) error {
+		err := execute.Run("ffmpeg", "-y", "-i", inputFile,
+			"-vf", "subtitles="+escapeFilterPath(captionsFile),
+			"-c:a", "copy",
+			partial,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to burn in captions: %w", err)
+		}
+		return nil
+	})
+}
+
+// translateFlags are the options of tsplice translate.
+type translateFlags struct {
+	language string
+	burn     bool
+}
+
+func (f *translateFlags) define(fs *flag.FlagSet) {
+	fs.StringVar(&f.language, "to", "", "Language code to translate the captions into (e.g. es, de, ja)")
+	fs.BoolVar(&f.burn, "burn", false, "Also render the video with both languages stacked in its captions")
+}
+
+func runTranslate(args []string) error {
+	fs := newCommandFlagSet("translate")
+	var flags translateFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || flags.language == "" {
+		return fmt.Errorf("usage: tsplice translate --to=<language> [options] <input-file>")
+	}
+
+	inputFile := positional[0]
+	if err := validateInputFile(inputFile); err != nil {
+		return err
+	}
+
+	if err := setupAPIKey(); err != nil {
+		return err
+	}
+
+	transcriptItems, err := transcriptFor(inputFile, defaultTranscriber())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Translating %d captions...", len(transcriptItems))))
+	translated, err := translateTranscript(transcriptItems, flags.language)
+	if err != nil {
+		return err
+	}
+
+	// Keep the translations next to the original transcript
+	base := strings.TrimSuffix(vttPath(inputFile), ".vtt")
+	translatedFile := base + "." + flags.language + ".vtt"
+	bilingualFile := base + ".bilingual.vtt"
+	if err := os.WriteFile(translatedFile, []byte(formatVTT(translated)), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(bilingualFile, []byte(formatVTT(stackCaptions(transcriptItems, translated))), 0644); err != nil {
+		return err
+	}
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Saved captions to "+vttPath(inputFile)+", "+translatedFile+", and "+bilingualFile))
+
+	if flags.burn {
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Burning in bilingual captions with ffmpeg..."))
+		outputFile := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "_bilingual.mp4"
+		if err := burnCaptions(inputFile, bilingualFile, outputFile); err != nil {
+			return err
+		}
+		fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Saved output to "+outputFile))
+	}
+
+	fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Done."))
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+// translationsDE is the editor's text in German, by the English text.
+var translationsDE = map[string]string{
+	"Auto-edit found no filler or silent lines to remove.":                      "Die automatische Bearbeitung hat keine Füll- oder stillen Zeilen zum Entfernen gefunden.",
+	"Auto-edit would remove %d lines, marked ✂. Press enter on any to keep it.": "Die automatische Bearbeitung würde %d Zeilen entfernen, markiert mit ✂. Drücke enter auf einer, um sie zu behalten.",
+	"Auto-edit removed %d lines, %d kept.":                                      "Die automatische Bearbeitung hat %d Zeilen entfernt, %d behalten.",
+	"Auto-edit discarded.":                                                      "Automatische Bearbeitung verworfen.",
+	"Selected %d visible segments.":                                             "%d sichtbare Segmente ausgewählt.",
+	"Deselected %d visible segments.":                                           "Auswahl von %d sichtbaren Segmenten aufgehoben.",
+	"Padded %d visible segments.":                                               "%d sichtbare Segmente verlängert.",
+	"Trimmed %d visible segments.":                                              "%d sichtbare Segmente gekürzt.",
+	"Tagged #%s on %d visible segments.":                                        "Tag #%s zu %d sichtbaren Segmenten hinzugefügt.",
+	"Transcript changes saved locally.":                                         "Änderungen am Transkript lokal gespeichert.",
+	"Comparing with previous transcript (%d of %d segments changed)":            "Vergleich mit dem vorherigen Transkript (%d von %d Segmenten geändert)",
+	"↑/↓ move • o/n keep old/new • tab toggle • O/N keep all • enter save":      "↑/↓ bewegen • o/n alt/neu behalten • tab umschalten • O/N alle behalten • enter speichern",
+	"(no segment)":                                               "(kein Segment)",
+	"Could not save the transcript: %s":                          "Das Transkript konnte nicht gespeichert werden: %s",
+	"Could not save the word timings: %s":                        "Die Wortzeiten konnten nicht gespeichert werden: %s",
+	"Transcribing locally with %s...":                            "Lokale Transkription mit %s...",
+	"Transcribing with %s...":                                    "Transkription mit %s...",
+	"Could not start audio preview: %s":                          "Die Audiovorschau konnte nicht gestartet werden: %s",
+	"No API key saved, run tsplice with --retranscribe instead.": "Kein API-Schlüssel gespeichert, starte tsplice stattdessen mit --retranscribe.",
+	"Extracting audio with ffmpeg...":                            "Audio wird mit ffmpeg extrahiert...",
+	"Re-transcribe in language (e.g. en, es, auto): ":            "Neu transkribieren in Sprache (z. B. en, es, auto): ",
+	"Language: %s":                                               "Sprache: %s",
+	"Filter: ":                                                   "Filter: ",
+	"Macro recorded, press @ to replay it.":                      "Makro aufgezeichnet, drücke @, um es abzuspielen.",
+	"boost":                                                      "verstärkt",
+	"kept, %s":                                                   "behalten, %s",
+	"tag":                                                        "taggen",
+	"export tags":                                                "Tags exportieren",
+	"apply rules":                                                "Regeln anwenden",
+	"preview":                                                    "Vorschau",
+	"audio preview":                                              "Audiovorschau",
+	"compile":                                                    "kompilieren",
+	"export edl":                                                 "EDL exportieren",
+	"export captions":                                            "Untertitel exportieren",
+	"export links":                                               "Links exportieren",
+	"re-transcribe":                                              "neu transkribieren",
+	"audiograms":                                                 "Audiogramme",
+	"zen":                                                        "Zen",
+	"summary":                                                    "Übersicht",
+	"stats":                                                      "Statistik",
+	"name speakers":                                              "Sprecher benennen",
+	"pad":                                                        "verlängern",
+	"boost quiet":                                                "Leises verstärken",
+	"bulk":                                                       "Sammelaktion",
+	"edit text":                                                  "Text bearbeiten",
+	"select range":                                               "Bereich auswählen",
+	"select all/none/invert":                                     "alle/keine/umkehren",
+	"auto-edit":                                                  "automatisch bearbeiten",
+	"undo/redo":                                                  "rückgängig/wiederholen",
+	"record/replay macro":                                        "Makro aufnehmen/abspielen",
+	"Compiling video segments with ffmpeg...": "Videosegmente werden mit ffmpeg kompiliert...",
+	"Exported analytics to %s":                "Statistik exportiert nach %s",
+	" and ":                                   " und ",
+	"Read-only, another tsplice is editing this video.":                                           "Schreibgeschützt, ein anderes tsplice bearbeitet dieses Video.",
+	"Speakers can't be renamed while the video is open read-only.":                                "Sprecher können nicht umbenannt werden, solange das Video schreibgeschützt geöffnet ist.",
+	"No speaker labels in this transcript to name.":                                               "Dieses Transkript hat keine Sprecherlabels zum Benennen.",
+	"Exported %d tag cut lists.":                                                                  "%d Schnittlisten nach Tag exportiert.",
+	"Exported EDL to %s":                                                                          "EDL exportiert nach %s",
+	"Exported selected captions to %s":                                                            "Ausgewählte Untertitel exportiert nach %s",
+	"Exported links to %s":                                                                        "Links exportiert nach %s",
+	"Select the segments to export as audiograms first.":                                          "Wähle zuerst die Segmente aus, die als Audiogramme exportiert werden sollen.",
+	"Rendering audiograms with ffmpeg...":                                                         "Audiogramme werden mit ffmpeg gerendert...",
+	"Rules applied, %d segments changed.":                                                         "Regeln angewendet, %d Segmente geändert.",
+	"Audio extracted from ffmpeg.":                                                                "Audio mit ffmpeg extrahiert.",
+	"Transcription finished.":                                                                     "Transkription abgeschlossen.",
+	"Transcription finished and saved locally.":                                                   "Transkription abgeschlossen und lokal gespeichert.",
+	"Previews will play the source, %s":                                                           "Vorschauen spielen das Original ab, %s",
+	"Preview proxy ready, previews now play a low-res copy.":                                      "Vorschau-Proxy bereit, Vorschauen spielen jetzt eine Kopie in niedriger Auflösung.",
+	"Embedded %d lines before failing, the rest are embedded next time: %s":                       "%d Zeilen eingebettet, bevor es fehlschlug, der Rest wird beim nächsten Mal eingebettet: %s",
+	"Embedded %d lines for tsplice search --semantic.":                                            "%d Zeilen für tsplice search --semantic eingebettet.",
+	"Could not look for names to redact: %s":                                                      "Die Suche nach zu schwärzenden Namen ist fehlgeschlagen: %s",
+	"Redacting %d names, email addresses, and phone numbers from exports and the compiled audio.": "%d Namen, E-Mail-Adressen und Telefonnummern werden in Exporten und im kompilierten Audio geschwärzt.",
+	"Video compiled successfully.":                                                                "Video erfolgreich kompiliert.",
+	"Saved output to %s":                                                                          "Ausgabe gespeichert unter %s",
+	"Saved manifest to %s":                                                                        "Manifest gespeichert unter %s",
+	"Once the draft looks right, run tsplice --final %s to render it at full quality.":            "Wenn der Entwurf passt, starte tsplice --final %s, um ihn in voller Qualität zu rendern.",
+	"Output verified, audio and video are in sync.":                                               "Ausgabe geprüft, Audio und Video sind synchron.",
+	"Warning: %s":                          "Warnung: %s",
+	"Exported %d audiograms.":              "%d Audiogramme exportiert.",
+	"No transcript items found":            "Keine Zeilen im Transkript gefunden",
+	"Start: %s | End: %s":                  "Anfang: %s | Ende: %s",
+	"read-only":                            "schreibgeschützt",
+	"offline, nothing leaves this machine": "offline, nichts verlässt diesen Rechner",
+	"recording macro":                      "Makro wird aufgezeichnet",
+	"%d audio tracks: k keep all • s export stems • enter first only • esc cancel": "%d Audiospuren: k alle behalten • s Stems exportieren • enter nur die erste • esc abbrechen",
+	"auto-edit preview: enter keep/remove • F apply • esc discard":                 "Vorschau der automatischen Bearbeitung: enter behalten/entfernen • F anwenden • esc verwerfen",
+	"editing: enter save • esc cancel":                                             "Bearbeiten: enter speichern • esc abbrechen",
+	"range of %d: enter select • A deselect • i invert • esc cancel":               "Bereich von %d: enter auswählen • A abwählen • i umkehren • esc abbrechen",
+	"all %d visible: s select • d deselect • 1-9 tag • +/- pad":                    "alle %d sichtbaren: s auswählen • d abwählen • 1-9 taggen • +/- verlängern",
+	"%s left":                 "noch %s",
+	"Selected %d segments.":   "%d Segmente ausgewählt.",
+	"Deselected %d segments.": "Auswahl von %d Segmenten aufgehoben.",
+	"Inverted the selection of %d visible segments.":                                            "Auswahl von %d sichtbaren Segmenten umgekehrt.",
+	"Inverted the selection of %d segments.":                                                    "Auswahl von %d Segmenten umgekehrt.",
+	"Found %d email addresses or phone numbers, run with --redact to remove them from exports.": "%d E-Mail-Adressen oder Telefonnummern gefunden, starte mit --redact, um sie aus Exporten zu entfernen.",
+	"Redacting %d email addresses and phone numbers from exports and the compiled audio.":       "%d E-Mail-Adressen und Telefonnummern werden in Exporten und im kompilierten Audio geschwärzt.",
+	"Could not save speaker names: %s":                                                          "Die Sprechernamen konnten nicht gespeichert werden: %s",
+	"Renamed %s to %s on %d lines.":                                                             "%s in %s umbenannt, in %d Zeilen.",
+	"Name the speakers (%d in this transcript)":                                                 "Sprecher benennen (%d in diesem Transkript)",
+	"was %s": "vorher %s",
+	"↑/↓ move • p play a sample • enter name • esc done": "↑/↓ bewegen • p Hörprobe abspielen • enter benennen • esc fertig",
+	"enter save • esc cancel":                            "enter speichern • esc abbrechen",
+	"Overview":                                           "Überblick",
+	"words":                                              "Wörter",
+	"duration":                                           "Dauer",
+	"words per minute":                                   "Wörter pro Minute",
+	"rate over time":                                     "Tempo im Verlauf",
+	"Speakers":                                           "Sprecher",
+	"no speaker labels in this transcript":               "keine Sprecherlabels in diesem Transkript",
+	"%.0f%% (%s, %d words)":                              "%.0f%% (%s, %d Wörter)",
+	"Longest silences":                                   "Längste Pausen",
+	"none":                                               "keine",
+	"Filler words":                                       "Füllwörter",
+	"Press x to export as JSON/CSV, S or esc to go back": "Drücke x, um als JSON/CSV zu exportieren, S oder esc, um zurückzugehen",
+	"Could not update %s: %s":                            "%s konnte nicht aktualisiert werden: %s",
+	"Could not save the selection: %s":                   "Die Auswahl konnte nicht gespeichert werden: %s",
+	"… and %d more":                                      "… und %d weitere",
+	"Nothing selected yet":                               "Noch nichts ausgewählt",
+	"Selected (%d)":                                      "Ausgewählt (%d)",
+	"total %s":                                           "gesamt %s",
+	"Nothing to undo.":                                   "Nichts rückgängig zu machen.",
+	"Undone, press ctrl+y to redo.":                      "Rückgängig gemacht, drücke ctrl+y zum Wiederholen.",
+	"Nothing to redo.":                                   "Nichts zu wiederholen.",
+	"Redone.":                                            "Wiederholt.",
+	"up":                                                 "hoch",
+	"down":                                               "runter",
+	"prev page":                                          "vorige Seite",
+	"next page":                                          "nächste Seite",
+	"go to start":                                        "zum Anfang",
+	"go to end":                                          "zum Ende",
+	"filter":                                             "filtern",
+	"clear filter":                                       "Filter löschen",
+	"cancel":                                             "abbrechen",
+	"apply filter":                                       "Filter anwenden",
+	"more":                                               "mehr",
+	"close help":                                         "Hilfe schließen",
+	"quit":                                               "beenden",
+	"no speech":                                          "keine Sprache",
+	"filler":                                             "Füllwort",
+	"old":                                                "alt",
+	"new":                                                "neu",
+	"There's no line after this one to merge with.":                  "Nach dieser Zeile gibt es keine, mit der sie zusammengeführt werden kann.",
+	"The next line is hidden by the filter, clear it to merge them.": "Die nächste Zeile ist vom Filter ausgeblendet, lösche ihn, um sie zusammenzuführen.",
+	"Lines from different speakers can't be merged.":                 "Zeilen verschiedener Sprecher können nicht zusammengeführt werden.",
+	"A line needs at least two words to be split.":                   "Eine Zeile braucht mindestens zwei Wörter, um geteilt zu werden.",
+	"This line is too short to split there.":                         "Diese Zeile ist zu kurz, um sie dort zu teilen.",
+	"split at %s: ←/→ move • enter split • esc cancel":               "teilen bei %s: ←/→ bewegen • enter teilen • esc abbrechen",
+	"merge with next": "mit der nächsten zusammenführen",
+	"split":           "teilen",
+	"A previous compile didn't finish, restored its %d selected lines. Press c to compile again.": "Ein vorheriges Kompilieren wurde nicht beendet, seine %d ausgewählten Zeilen wurden wiederhergestellt. Drücke c, um erneut zu kompilieren.",
+	"Compiling may fail, %s":                                                         "Das Kompilieren könnte fehlschlagen, %s",
+	"Could not read the meeting chat: %s":                                            "Der Meeting-Chat konnte nicht gelesen werden: %s",
+	"Editing with the %s profile.":                                                   "Bearbeitung mit dem Profil %s.",
+	"Encrypted %d existing project files.":                                           "%d vorhandene Projektdateien verschlüsselt.",
+	"Loaded %d lines selected in a shared session.":                                  "%d in einer geteilten Sitzung ausgewählte Zeilen geladen.",
+	"Making a low-res preview proxy in the background.":                              "Vorschau-Proxy in niedriger Auflösung wird im Hintergrund erstellt.",
+	"No meeting in the calendar matches when this was recorded":                      "Kein Meeting im Kalender passt zur Aufnahmezeit",
+	"Reading the source from the network drive, %s":                                  "Das Original wird vom Netzlaufwerk gelesen, %s",
+	"Recorded during %s with %d attendees":                                           "Aufgenommen während %s mit %d Teilnehmern",
+	"Resuming with the audio extracted by a previous run.":                           "Es geht weiter mit dem Audio, das ein vorheriger Lauf extrahiert hat.",
+	"Saved %d chat messages to %s":                                                   "%d Chatnachrichten gespeichert unter %s",
+	"Selections won't be saved, %s":                                                  "Auswahlen werden nicht gespeichert, %s",
+	"Source is on a network drive, working from a local copy.":                       "Das Original liegt auf einem Netzlaufwerk, es wird mit einer lokalen Kopie gearbeitet.",
+	"Start timecode %s read from metadata.":                                          "Start-Timecode %s aus den Metadaten gelesen.",
+	"Transcript already exists locally":                                              "Transkript ist lokal bereits vorhanden",
+	"Transcript already exists locally, re-transcribing":                             "Transkript ist lokal bereits vorhanden, wird neu transkribiert",
+	"Transcript created from %d participants' audio":                                 "Transkript aus dem Audio von %d Teilnehmern erstellt",
+	"Transcript created from YouTube captions":                                       "Transkript aus YouTube-Untertiteln erstellt",
+	"Transcript extracted from subtitle track":                                       "Transkript aus der Untertitelspur extrahiert",
+	"Variable frame rate detected, output will be converted to a constant %.2f fps.": "Variable Bildrate erkannt, die Ausgabe wird in konstante %.2f fps umgewandelt.",
+	"Compiling with --copy, each cut starts at the keyframe before its line.":        "Kompilieren mit --copy, jeder Schnitt beginnt am Keyframe vor seiner Zeile.",
+	"Compiling each segment as a clip with ffmpeg...":                                "Jedes Segment wird mit ffmpeg als Clip kompiliert...",
+	"Saved %d clips to %s":                                                           "%d Clips in %s gespeichert",
+	"export clips":                                                                   "Clips exportieren",
+	"%s isn't installed":                                                             "%s ist nicht installiert",
+	"A tool tsplice ran failed":                                                      "Ein von tsplice gestartetes Werkzeug ist fehlgeschlagen",
+	"API key":                                                                        "API-Schlüssel",
+	"Add credit to the account, or transcribe with --local.":                         "Lade Guthaben auf das Konto oder transkribiere mit --local.",
+	"Check that %s is running, then try again.":                                      "Prüfe, ob %s läuft, und versuche es erneut.",
+	"Check that the key is right and still active, or enter a new one.":              "Prüfe, ob der Schlüssel stimmt und noch aktiv ist, oder gib einen neuen ein.",
+	"Check the internet connection, then try again.":                                 "Prüfe die Internetverbindung und versuche es erneut.",
+	"Check the key api_key_cmd prints, it's read again on the next run.":             "Prüfe den Schlüssel, den api_key_cmd ausgibt, er wird beim nächsten Start neu gelesen.",
+	"Could not reach the transcription service":                                      "Der Transkriptionsdienst war nicht erreichbar",
+	"Could not save the key: %s":                                                     "Der Schlüssel konnte nicht gespeichert werden: %s",
+	"Free up some space, then try again.":                                            "Gib etwas Speicherplatz frei und versuche es erneut.",
+	"Install %s, or put it on the PATH, then run tsplice again.":                     "Installiere %s oder nimm es in den PATH auf und starte tsplice erneut.",
+	"It can be sent in smaller chunks, which are joined back into one transcript.":   "Es kann in kleineren Stücken gesendet werden, die wieder zu einem Transkript zusammengefügt werden.",
+	"It's usually brief, try again in a moment.":                                     "Das ist meist kurz, versuche es gleich noch einmal.",
+	"Log: %s":                               "Protokoll: %s",
+	"Offline mode kept this on the machine": "Der Offline-Modus hat das auf diesem Rechner gehalten",
+	"Something went wrong":                  "Etwas ist schiefgelaufen",
+	"The audio is too big for the transcription service": "Das Audio ist zu groß für den Transkriptionsdienst",
+	"The disk is full":                                                                              "Der Datenträger ist voll",
+	"The transcription account is out of credit":                                                    "Das Transkriptionskonto hat kein Guthaben mehr",
+	"The transcription service had a problem":                                                       "Der Transkriptionsdienst hatte ein Problem",
+	"The transcription service is limiting requests":                                                "Der Transkriptionsdienst begrenzt die Anfragen",
+	"The transcription service turned down the API key":                                             "Der Transkriptionsdienst hat den API-Schlüssel abgelehnt",
+	"Transcribe with --local or --stt-command, or run without --offline.":                           "Transkribiere mit --local oder --stt-command oder starte ohne --offline.",
+	"Transcribe with --local, or a provider that takes bigger files.":                               "Transkribiere mit --local oder einem Anbieter, der größere Dateien annimmt.",
+	"Upgrade to a recent full build of ffmpeg, like the ones linked from ffmpeg.org/download.html.": "Aktualisiere auf einen aktuellen vollständigen ffmpeg-Build, etwa einen der auf ffmpeg.org/download.html verlinkten.",
+	"Wait a moment, then try again.":                                                                "Warte einen Moment und versuche es erneut.",
+	"What it printed is in the log, try again once it's sorted out.":                                "Seine Ausgabe steht im Protokoll, versuche es erneut, sobald das behoben ist.",
+	"c send in smaller chunks":                                                                      "c in kleineren Stücken senden",
+	"enter save and transcribe again • esc cancel":                                                  "enter speichern und neu transkribieren • esc abbrechen",
+	"esc back to the editor":                                                                        "esc zurück zum Editor",
+	"k enter a new key":                                                                             "k neuen Schlüssel eingeben",
+	"q quit":                                                                                        "q beenden",
+	"r try again":                                                                                   "r erneut versuchen",
+	"This ffmpeg doesn't have the %s filter":                                                        "Diesem ffmpeg fehlt der Filter %s",
+	"This ffmpeg doesn't have the %s encoder":                                                       "Diesem ffmpeg fehlt der Encoder %s",
+	"This ffmpeg doesn't know the %s option":                                                        "Dieses ffmpeg kennt die Option %s nicht",
+}
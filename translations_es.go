@@ -0,0 +1,216 @@
+package main
+
+// translationsES is the editor's text in Spanish, by the English text.
+var translationsES = map[string]string{
+	"Auto-edit found no filler or silent lines to remove.":                      "La edición automática no encontró líneas de relleno ni silencios que quitar.",
+	"Auto-edit would remove %d lines, marked ✂. Press enter on any to keep it.": "La edición automática quitaría %d líneas, marcadas con ✂. Pulsa enter en cualquiera para conservarla.",
+	"Auto-edit removed %d lines, %d kept.":                                      "La edición automática quitó %d líneas, %d conservadas.",
+	"Auto-edit discarded.":                                                      "Edición automática descartada.",
+	"Selected %d visible segments.":                                             "Seleccionados %d segmentos visibles.",
+	"Deselected %d visible segments.":                                           "Deseleccionados %d segmentos visibles.",
+	"Padded %d visible segments.":                                               "Ampliados %d segmentos visibles.",
+	"Trimmed %d visible segments.":                                              "Recortados %d segmentos visibles.",
+	"Tagged #%s on %d visible segments.":                                        "Etiqueta #%s añadida a %d segmentos visibles.",
+	"Transcript changes saved locally.":                                         "Cambios de la transcripción guardados localmente.",
+	"Comparing with previous transcript (%d of %d segments changed)":            "Comparando con la transcripción anterior (%d de %d segmentos cambiados)",
+	"↑/↓ move • o/n keep old/new • tab toggle • O/N keep all • enter save":      "↑/↓ mover • o/n conservar anterior/nuevo • tab alternar • O/N conservar todos • enter guardar",
+	"(no segment)":                                               "(sin segmento)",
+	"Could not save the transcript: %s":                          "No se pudo guardar la transcripción: %s",
+	"Could not save the word timings: %s":                        "No se pudieron guardar los tiempos de las palabras: %s",
+	"Transcribing locally with %s...":                            "Transcribiendo localmente con %s...",
+	"Transcribing with %s...":                                    "Transcribiendo con %s...",
+	"Could not start audio preview: %s":                          "No se pudo iniciar la vista previa de audio: %s",
+	"No API key saved, run tsplice with --retranscribe instead.": "No hay ninguna clave de API guardada, ejecuta tsplice con --retranscribe.",
+	"Extracting audio with ffmpeg...":                            "Extrayendo el audio con ffmpeg...",
+	"Re-transcribe in language (e.g. en, es, auto): ":            "Volver a transcribir en el idioma (p. ej. en, es, auto): ",
+	"Language: %s":                                               "Idioma: %s",
+	"Filter: ":                                                   "Filtrar: ",
+	"Macro recorded, press @ to replay it.":                      "Macro grabada, pulsa @ para repetirla.",
+	"boost":                                                      "realce",
+	"kept, %s":                                                   "conservada, %s",
+	"tag":                                                        "etiquetar",
+	"export tags":                                                "exportar etiquetas",
+	"apply rules":                                                "aplicar reglas",
+	"preview":                                                    "vista previa",
+	"audio preview":                                              "vista previa de audio",
+	"compile":                                                    "compilar",
+	"export edl":                                                 "exportar edl",
+	"export captions":                                            "exportar subtítulos",
+	"export links":                                               "exportar enlaces",
+	"re-transcribe":                                              "volver a transcribir",
+	"audiograms":                                                 "audiogramas",
+	"zen":                                                        "zen",
+	"summary":                                                    "resumen",
+	"stats":                                                      "estadísticas",
+	"name speakers":                                              "nombrar hablantes",
+	"pad":                                                        "ampliar",
+	"boost quiet":                                                "realzar voz baja",
+	"bulk":                                                       "en bloque",
+	"edit text":                                                  "editar texto",
+	"select range":                                               "seleccionar rango",
+	"select all/none/invert":                                     "seleccionar todo/nada/invertir",
+	"auto-edit":                                                  "edición automática",
+	"undo/redo":                                                  "deshacer/rehacer",
+	"record/replay macro":                                        "grabar/repetir macro",
+	"Compiling video segments with ffmpeg...": "Compilando los segmentos de vídeo con ffmpeg...",
+	"Exported analytics to %s":                "Estadísticas exportadas a %s",
+	" and ":                                   " y ",
+	"Read-only, another tsplice is editing this video.":                                           "Solo lectura, otro tsplice está editando este vídeo.",
+	"Speakers can't be renamed while the video is open read-only.":                                "No se puede cambiar el nombre de los hablantes mientras el vídeo está abierto en solo lectura.",
+	"No speaker labels in this transcript to name.":                                               "Esta transcripción no tiene etiquetas de hablante que nombrar.",
+	"Exported %d tag cut lists.":                                                                  "Exportadas %d listas de cortes por etiqueta.",
+	"Exported EDL to %s":                                                                          "EDL exportada a %s",
+	"Exported selected captions to %s":                                                            "Subtítulos seleccionados exportados a %s",
+	"Exported links to %s":                                                                        "Enlaces exportados a %s",
+	"Select the segments to export as audiograms first.":                                          "Primero selecciona los segmentos que quieres exportar como audiogramas.",
+	"Rendering audiograms with ffmpeg...":                                                         "Renderizando audiogramas con ffmpeg...",
+	"Rules applied, %d segments changed.":                                                         "Reglas aplicadas, %d segmentos cambiados.",
+	"Audio extracted from ffmpeg.":                                                                "Audio extraído con ffmpeg.",
+	"Transcription finished.":                                                                     "Transcripción terminada.",
+	"Transcription finished and saved locally.":                                                   "Transcripción terminada y guardada localmente.",
+	"Previews will play the source, %s":                                                           "Las vistas previas reproducirán el original, %s",
+	"Preview proxy ready, previews now play a low-res copy.":                                      "Proxy de vista previa listo, las vistas previas usan ahora una copia de baja resolución.",
+	"Embedded %d lines before failing, the rest are embedded next time: %s":                       "Se generaron embeddings de %d líneas antes del fallo, el resto se generará la próxima vez: %s",
+	"Embedded %d lines for tsplice search --semantic.":                                            "Embeddings generados para %d líneas para tsplice search --semantic.",
+	"Could not look for names to redact: %s":                                                      "No se pudieron buscar nombres que ocultar: %s",
+	"Redacting %d names, email addresses, and phone numbers from exports and the compiled audio.": "Ocultando %d nombres, direcciones de correo y números de teléfono en las exportaciones y el audio compilado.",
+	"Video compiled successfully.":                                                                "Vídeo compilado correctamente.",
+	"Saved output to %s":                                                                          "Resultado guardado en %s",
+	"Saved manifest to %s":                                                                        "Manifiesto guardado en %s",
+	"Once the draft looks right, run tsplice --final %s to render it at full quality.":            "Cuando el borrador esté bien, ejecuta tsplice --final %s para renderizarlo con la calidad completa.",
+	"Output verified, audio and video are in sync.":                                               "Resultado verificado, el audio y el vídeo están sincronizados.",
+	"Warning: %s":                          "Aviso: %s",
+	"Exported %d audiograms.":              "Exportados %d audiogramas.",
+	"No transcript items found":            "No se encontraron líneas en la transcripción",
+	"Start: %s | End: %s":                  "Inicio: %s | Fin: %s",
+	"read-only":                            "solo lectura",
+	"offline, nothing leaves this machine": "sin conexión, nada sale de este equipo",
+	"recording macro":                      "grabando macro",
+	"%d audio tracks: k keep all • s export stems • enter first only • esc cancel": "%d pistas de audio: k conservar todas • s exportar stems • enter solo la primera • esc cancelar",
+	"auto-edit preview: enter keep/remove • F apply • esc discard":                 "vista previa de la edición automática: enter conservar/quitar • F aplicar • esc descartar",
+	"editing: enter save • esc cancel":                                             "editando: enter guardar • esc cancelar",
+	"range of %d: enter select • A deselect • i invert • esc cancel":               "rango de %d: enter seleccionar • A deseleccionar • i invertir • esc cancelar",
+	"all %d visible: s select • d deselect • 1-9 tag • +/- pad":                    "los %d visibles: s seleccionar • d deseleccionar • 1-9 etiquetar • +/- ampliar",
+	"%s left":                 "quedan %s",
+	"Selected %d segments.":   "Seleccionados %d segmentos.",
+	"Deselected %d segments.": "Deseleccionados %d segmentos.",
+	"Inverted the selection of %d visible segments.":                                            "Invertida la selección de %d segmentos visibles.",
+	"Inverted the selection of %d segments.":                                                    "Invertida la selección de %d segmentos.",
+	"Found %d email addresses or phone numbers, run with --redact to remove them from exports.": "Se encontraron %d direcciones de correo o números de teléfono, ejecuta con --redact para quitarlos de las exportaciones.",
+	"Redacting %d email addresses and phone numbers from exports and the compiled audio.":       "Ocultando %d direcciones de correo y números de teléfono en las exportaciones y el audio compilado.",
+	"Could not save speaker names: %s":                                                          "No se pudieron guardar los nombres de los hablantes: %s",
+	"Renamed %s to %s on %d lines.":                                                             "%s renombrado a %s en %d líneas.",
+	"Name the speakers (%d in this transcript)":                                                 "Nombra a los hablantes (%d en esta transcripción)",
+	"was %s": "antes %s",
+	"↑/↓ move • p play a sample • enter name • esc done": "↑/↓ mover • p escuchar una muestra • enter nombrar • esc terminar",
+	"enter save • esc cancel":                            "enter guardar • esc cancelar",
+	"Overview":                                           "General",
+	"words":                                              "palabras",
+	"duration":                                           "duración",
+	"words per minute":                                   "palabras por minuto",
+	"rate over time":                                     "ritmo en el tiempo",
+	"Speakers":                                           "Hablantes",
+	"no speaker labels in this transcript":               "esta transcripción no tiene etiquetas de hablante",
+	"%.0f%% (%s, %d words)":                              "%.0f%% (%s, %d palabras)",
+	"Longest silences":                                   "Silencios más largos",
+	"none":                                               "ninguno",
+	"Filler words":                                       "Muletillas",
+	"Press x to export as JSON/CSV, S or esc to go back": "Pulsa x para exportar como JSON/CSV, S o esc para volver",
+	"Could not update %s: %s":                            "No se pudo actualizar %s: %s",
+	"Could not save the selection: %s":                   "No se pudo guardar la selección: %s",
+	"… and %d more":                                      "… y %d más",
+	"Nothing selected yet":                               "Nada seleccionado todavía",
+	"Selected (%d)":                                      "Seleccionado (%d)",
+	"total %s":                                           "total %s",
+	"Nothing to undo.":                                   "Nada que deshacer.",
+	"Undone, press ctrl+y to redo.":                      "Deshecho, pulsa ctrl+y para rehacer.",
+	"Nothing to redo.":                                   "Nada que rehacer.",
+	"Redone.":                                            "Rehecho.",
+	"up":                                                 "arriba",
+	"down":                                               "abajo",
+	"prev page":                                          "página anterior",
+	"next page":                                          "página siguiente",
+	"go to start":                                        "ir al inicio",
+	"go to end":                                          "ir al final",
+	"filter":                                             "filtrar",
+	"clear filter":                                       "quitar filtro",
+	"cancel":                                             "cancelar",
+	"apply filter":                                       "aplicar filtro",
+	"more":                                               "más",
+	"close help":                                         "cerrar ayuda",
+	"quit":                                               "salir",
+	"no speech":                                          "sin voz",
+	"filler":                                             "relleno",
+	"old":                                                "anterior",
+	"new":                                                "nuevo",
+	"There's no line after this one to merge with.":                  "No hay ninguna línea después de esta con la que unirla.",
+	"The next line is hidden by the filter, clear it to merge them.": "El filtro oculta la siguiente línea, quítalo para unirlas.",
+	"Lines from different speakers can't be merged.":                 "No se pueden unir líneas de hablantes distintos.",
+	"A line needs at least two words to be split.":                   "Una línea necesita al menos dos palabras para dividirla.",
+	"This line is too short to split there.":                         "Esta línea es demasiado corta para dividirla ahí.",
+	"split at %s: ←/→ move • enter split • esc cancel":               "dividir en %s: ←/→ mover • enter dividir • esc cancelar",
+	"merge with next": "unir con la siguiente",
+	"split":           "dividir",
+	"A previous compile didn't finish, restored its %d selected lines. Press c to compile again.": "Una compilación anterior no terminó, se restauraron sus %d líneas seleccionadas. Pulsa c para compilar de nuevo.",
+	"Compiling may fail, %s":                                                         "La compilación puede fallar, %s",
+	"Could not read the meeting chat: %s":                                            "No se pudo leer el chat de la reunión: %s",
+	"Editing with the %s profile.":                                                   "Editando con el perfil %s.",
+	"Encrypted %d existing project files.":                                           "Cifrados %d archivos de proyecto existentes.",
+	"Loaded %d lines selected in a shared session.":                                  "Cargadas %d líneas seleccionadas en una sesión compartida.",
+	"Making a low-res preview proxy in the background.":                              "Creando un proxy de vista previa de baja resolución en segundo plano.",
+	"No meeting in the calendar matches when this was recorded":                      "Ninguna reunión del calendario coincide con cuándo se grabó esto",
+	"Reading the source from the network drive, %s":                                  "Leyendo el original desde la unidad de red, %s",
+	"Recorded during %s with %d attendees":                                           "Grabado durante %s con %d asistentes",
+	"Resuming with the audio extracted by a previous run.":                           "Continuando con el audio extraído en una ejecución anterior.",
+	"Saved %d chat messages to %s":                                                   "Guardados %d mensajes del chat en %s",
+	"Selections won't be saved, %s":                                                  "Las selecciones no se guardarán, %s",
+	"Source is on a network drive, working from a local copy.":                       "El original está en una unidad de red, se trabaja con una copia local.",
+	"Start timecode %s read from metadata.":                                          "Código de tiempo inicial %s leído de los metadatos.",
+	"Transcript already exists locally":                                              "La transcripción ya existe localmente",
+	"Transcript already exists locally, re-transcribing":                             "La transcripción ya existe localmente, volviendo a transcribir",
+	"Transcript created from %d participants' audio":                                 "Transcripción creada a partir del audio de %d participantes",
+	"Transcript created from YouTube captions":                                       "Transcripción creada a partir de los subtítulos de YouTube",
+	"Transcript extracted from subtitle track":                                       "Transcripción extraída de la pista de subtítulos",
+	"Variable frame rate detected, output will be converted to a constant %.2f fps.": "Se detectó una velocidad de fotogramas variable, el resultado se convertirá a %.2f fps constantes.",
+	"Compiling with --copy, each cut starts at the keyframe before its line.":        "Compilando con --copy, cada corte empieza en el fotograma clave anterior a su línea.",
+	"Compiling each segment as a clip with ffmpeg...":                                "Compilando cada segmento como un clip con ffmpeg...",
+	"Saved %d clips to %s":                                                           "Se guardaron %d clips en %s",
+	"export clips":                                                                   "exportar clips",
+	"%s isn't installed":                                                             "%s no está instalado",
+	"A tool tsplice ran failed":                                                      "Falló una herramienta que ejecutó tsplice",
+	"API key":                                                                        "clave de API",
+	"Add credit to the account, or transcribe with --local.":                         "Añade crédito a la cuenta o transcribe con --local.",
+	"Check that %s is running, then try again.":                                      "Comprueba que %s esté en marcha y vuelve a intentarlo.",
+	"Check that the key is right and still active, or enter a new one.":              "Comprueba que la clave sea correcta y siga activa, o introduce una nueva.",
+	"Check the internet connection, then try again.":                                 "Comprueba la conexión a internet y vuelve a intentarlo.",
+	"Check the key api_key_cmd prints, it's read again on the next run.":             "Comprueba la clave que imprime api_key_cmd, se vuelve a leer en la próxima ejecución.",
+	"Could not reach the transcription service":                                      "No se pudo conectar con el servicio de transcripción",
+	"Could not save the key: %s":                                                     "No se pudo guardar la clave: %s",
+	"Free up some space, then try again.":                                            "Libera algo de espacio y vuelve a intentarlo.",
+	"Install %s, or put it on the PATH, then run tsplice again.":                     "Instala %s o añádelo al PATH, y vuelve a ejecutar tsplice.",
+	"It can be sent in smaller chunks, which are joined back into one transcript.":   "Se puede enviar en fragmentos más pequeños, que se vuelven a unir en una sola transcripción.",
+	"It's usually brief, try again in a moment.":                                     "Suele ser breve, vuelve a intentarlo en un momento.",
+	"Log: %s":                               "Registro: %s",
+	"Offline mode kept this on the machine": "El modo sin conexión lo mantuvo en este equipo",
+	"Something went wrong":                  "Algo salió mal",
+	"The audio is too big for the transcription service": "El audio es demasiado grande para el servicio de transcripción",
+	"The disk is full":                                                                              "El disco está lleno",
+	"The transcription account is out of credit":                                                    "La cuenta de transcripción se ha quedado sin crédito",
+	"The transcription service had a problem":                                                       "El servicio de transcripción tuvo un problema",
+	"The transcription service is limiting requests":                                                "El servicio de transcripción está limitando las solicitudes",
+	"The transcription service turned down the API key":                                             "El servicio de transcripción rechazó la clave de API",
+	"Transcribe with --local or --stt-command, or run without --offline.":                           "Transcribe con --local o --stt-command, o ejecuta sin --offline.",
+	"Transcribe with --local, or a provider that takes bigger files.":                               "Transcribe con --local o con un proveedor que acepte archivos más grandes.",
+	"Upgrade to a recent full build of ffmpeg, like the ones linked from ffmpeg.org/download.html.": "Actualiza a una versión completa y reciente de ffmpeg, como las enlazadas en ffmpeg.org/download.html.",
+	"Wait a moment, then try again.":                                                                "Espera un momento y vuelve a intentarlo.",
+	"What it printed is in the log, try again once it's sorted out.":                                "Lo que imprimió está en el registro, vuelve a intentarlo cuando esté resuelto.",
+	"c send in smaller chunks":                                                                      "c enviar en fragmentos más pequeños",
+	"enter save and transcribe again • esc cancel":                                                  "enter guardar y volver a transcribir • esc cancelar",
+	"esc back to the editor":                                                                        "esc volver al editor",
+	"k enter a new key":                                                                             "k introducir una clave nueva",
+	"q quit":                                                                                        "q salir",
+	"r try again":                                                                                   "r reintentar",
+	"This ffmpeg doesn't have the %s filter":                                                        "Este ffmpeg no tiene el filtro %s",
+	"This ffmpeg doesn't have the %s encoder":                                                       "Este ffmpeg no tiene el codificador %s",
+	"This ffmpeg doesn't know the %s option":                                                        "Este ffmpeg no conoce la opción %s",
+}
@@ -0,0 +1,216 @@
+package main
+
+// translationsFR is the editor's text in French, by the English text.
+var translationsFR = map[string]string{
+	"Auto-edit found no filler or silent lines to remove.":                      "Le montage automatique n'a trouvé aucune ligne de remplissage ou silencieuse à retirer.",
+	"Auto-edit would remove %d lines, marked ✂. Press enter on any to keep it.": "Le montage automatique retirerait %d lignes, marquées ✂. Appuyez sur enter sur l'une d'elles pour la garder.",
+	"Auto-edit removed %d lines, %d kept.":                                      "Le montage automatique a retiré %d lignes, %d gardées.",
+	"Auto-edit discarded.":                                                      "Montage automatique abandonné.",
+	"Selected %d visible segments.":                                             "%d segments visibles sélectionnés.",
+	"Deselected %d visible segments.":                                           "%d segments visibles désélectionnés.",
+	"Padded %d visible segments.":                                               "%d segments visibles allongés.",
+	"Trimmed %d visible segments.":                                              "%d segments visibles raccourcis.",
+	"Tagged #%s on %d visible segments.":                                        "Étiquette #%s ajoutée à %d segments visibles.",
+	"Transcript changes saved locally.":                                         "Modifications de la transcription enregistrées localement.",
+	"Comparing with previous transcript (%d of %d segments changed)":            "Comparaison avec la transcription précédente (%d segments sur %d modifiés)",
+	"↑/↓ move • o/n keep old/new • tab toggle • O/N keep all • enter save":      "↑/↓ déplacer • o/n garder ancien/nouveau • tab basculer • O/N tout garder • enter enregistrer",
+	"(no segment)":                                               "(aucun segment)",
+	"Could not save the transcript: %s":                          "Impossible d'enregistrer la transcription : %s",
+	"Could not save the word timings: %s":                        "Impossible d'enregistrer le minutage des mots : %s",
+	"Transcribing locally with %s...":                            "Transcription locale avec %s...",
+	"Transcribing with %s...":                                    "Transcription avec %s...",
+	"Could not start audio preview: %s":                          "Impossible de lancer l'aperçu audio : %s",
+	"No API key saved, run tsplice with --retranscribe instead.": "Aucune clé d'API enregistrée, lancez plutôt tsplice avec --retranscribe.",
+	"Extracting audio with ffmpeg...":                            "Extraction de l'audio avec ffmpeg...",
+	"Re-transcribe in language (e.g. en, es, auto): ":            "Retranscrire dans la langue (ex. en, es, auto) : ",
+	"Language: %s":                                               "Langue : %s",
+	"Filter: ":                                                   "Filtre : ",
+	"Macro recorded, press @ to replay it.":                      "Macro enregistrée, appuyez sur @ pour la rejouer.",
+	"boost":                                                      "gain",
+	"kept, %s":                                                   "gardée, %s",
+	"tag":                                                        "étiqueter",
+	"export tags":                                                "exporter les étiquettes",
+	"apply rules":                                                "appliquer les règles",
+	"preview":                                                    "aperçu",
+	"audio preview":                                              "aperçu audio",
+	"compile":                                                    "compiler",
+	"export edl":                                                 "exporter l'edl",
+	"export captions":                                            "exporter les sous-titres",
+	"export links":                                               "exporter les liens",
+	"re-transcribe":                                              "retranscrire",
+	"audiograms":                                                 "audiogrammes",
+	"zen":                                                        "zen",
+	"summary":                                                    "résumé",
+	"stats":                                                      "statistiques",
+	"name speakers":                                              "nommer les intervenants",
+	"pad":                                                        "allonger",
+	"boost quiet":                                                "gain sur les passages faibles",
+	"bulk":                                                       "en masse",
+	"edit text":                                                  "modifier le texte",
+	"select range":                                               "sélectionner une plage",
+	"select all/none/invert":                                     "tout/rien/inverser la sélection",
+	"auto-edit":                                                  "montage automatique",
+	"undo/redo":                                                  "annuler/rétablir",
+	"record/replay macro":                                        "enregistrer/rejouer une macro",
+	"Compiling video segments with ffmpeg...": "Compilation des segments vidéo avec ffmpeg...",
+	"Exported analytics to %s":                "Statistiques exportées vers %s",
+	" and ":                                   " et ",
+	"Read-only, another tsplice is editing this video.":                                           "Lecture seule, un autre tsplice modifie cette vidéo.",
+	"Speakers can't be renamed while the video is open read-only.":                                "Impossible de renommer les intervenants tant que la vidéo est ouverte en lecture seule.",
+	"No speaker labels in this transcript to name.":                                               "Cette transcription n'a aucune étiquette d'intervenant à nommer.",
+	"Exported %d tag cut lists.":                                                                  "%d listes de coupes par étiquette exportées.",
+	"Exported EDL to %s":                                                                          "EDL exportée vers %s",
+	"Exported selected captions to %s":                                                            "Sous-titres sélectionnés exportés vers %s",
+	"Exported links to %s":                                                                        "Liens exportés vers %s",
+	"Select the segments to export as audiograms first.":                                          "Sélectionnez d'abord les segments à exporter en audiogrammes.",
+	"Rendering audiograms with ffmpeg...":                                                         "Rendu des audiogrammes avec ffmpeg...",
+	"Rules applied, %d segments changed.":                                                         "Règles appliquées, %d segments modifiés.",
+	"Audio extracted from ffmpeg.":                                                                "Audio extrait avec ffmpeg.",
+	"Transcription finished.":                                                                     "Transcription terminée.",
+	"Transcription finished and saved locally.":                                                   "Transcription terminée et enregistrée localement.",
+	"Previews will play the source, %s":                                                           "Les aperçus liront l'original, %s",
+	"Preview proxy ready, previews now play a low-res copy.":                                      "Proxy d'aperçu prêt, les aperçus lisent maintenant une copie basse résolution.",
+	"Embedded %d lines before failing, the rest are embedded next time: %s":                       "Embeddings calculés pour %d lignes avant l'échec, le reste le sera la prochaine fois : %s",
+	"Embedded %d lines for tsplice search --semantic.":                                            "Embeddings calculés pour %d lignes pour tsplice search --semantic.",
+	"Could not look for names to redact: %s":                                                      "Impossible de chercher les noms à masquer : %s",
+	"Redacting %d names, email addresses, and phone numbers from exports and the compiled audio.": "Masquage de %d noms, adresses e-mail et numéros de téléphone dans les exports et l'audio compilé.",
+	"Video compiled successfully.":                                                                "Vidéo compilée avec succès.",
+	"Saved output to %s":                                                                          "Résultat enregistré dans %s",
+	"Saved manifest to %s":                                                                        "Manifeste enregistré dans %s",
+	"Once the draft looks right, run tsplice --final %s to render it at full quality.":            "Quand le brouillon vous convient, lancez tsplice --final %s pour le rendre en pleine qualité.",
+	"Output verified, audio and video are in sync.":                                               "Résultat vérifié, l'audio et la vidéo sont synchronisés.",
+	"Warning: %s":                          "Attention : %s",
+	"Exported %d audiograms.":              "%d audiogrammes exportés.",
+	"No transcript items found":            "Aucune ligne trouvée dans la transcription",
+	"Start: %s | End: %s":                  "Début : %s | Fin : %s",
+	"read-only":                            "lecture seule",
+	"offline, nothing leaves this machine": "hors ligne, rien ne quitte cette machine",
+	"recording macro":                      "enregistrement de la macro",
+	"%d audio tracks: k keep all • s export stems • enter first only • esc cancel": "%d pistes audio : k tout garder • s exporter les stems • enter la première seulement • esc annuler",
+	"auto-edit preview: enter keep/remove • F apply • esc discard":                 "aperçu du montage automatique : enter garder/retirer • F appliquer • esc abandonner",
+	"editing: enter save • esc cancel":                                             "modification : enter enregistrer • esc annuler",
+	"range of %d: enter select • A deselect • i invert • esc cancel":               "plage de %d : enter sélectionner • A désélectionner • i inverser • esc annuler",
+	"all %d visible: s select • d deselect • 1-9 tag • +/- pad":                    "les %d visibles : s sélectionner • d désélectionner • 1-9 étiqueter • +/- allonger",
+	"%s left":                 "encore %s",
+	"Selected %d segments.":   "%d segments sélectionnés.",
+	"Deselected %d segments.": "%d segments désélectionnés.",
+	"Inverted the selection of %d visible segments.":                                            "Sélection inversée sur %d segments visibles.",
+	"Inverted the selection of %d segments.":                                                    "Sélection inversée sur %d segments.",
+	"Found %d email addresses or phone numbers, run with --redact to remove them from exports.": "%d adresses e-mail ou numéros de téléphone trouvés, lancez avec --redact pour les retirer des exports.",
+	"Redacting %d email addresses and phone numbers from exports and the compiled audio.":       "Masquage de %d adresses e-mail et numéros de téléphone dans les exports et l'audio compilé.",
+	"Could not save speaker names: %s":                                                          "Impossible d'enregistrer les noms des intervenants : %s",
+	"Renamed %s to %s on %d lines.":                                                             "%s renommé en %s sur %d lignes.",
+	"Name the speakers (%d in this transcript)":                                                 "Nommez les intervenants (%d dans cette transcription)",
+	"was %s": "avant %s",
+	"↑/↓ move • p play a sample • enter name • esc done": "↑/↓ déplacer • p écouter un extrait • enter nommer • esc terminer",
+	"enter save • esc cancel":                            "enter enregistrer • esc annuler",
+	"Overview":                                           "Vue d'ensemble",
+	"words":                                              "mots",
+	"duration":                                           "durée",
+	"words per minute":                                   "mots par minute",
+	"rate over time":                                     "débit au fil du temps",
+	"Speakers":                                           "Intervenants",
+	"no speaker labels in this transcript":               "aucune étiquette d'intervenant dans cette transcription",
+	"%.0f%% (%s, %d words)":                              "%.0f%% (%s, %d mots)",
+	"Longest silences":                                   "Silences les plus longs",
+	"none":                                               "aucun",
+	"Filler words":                                       "Mots de remplissage",
+	"Press x to export as JSON/CSV, S or esc to go back": "Appuyez sur x pour exporter en JSON/CSV, S ou esc pour revenir",
+	"Could not update %s: %s":                            "Impossible de mettre à jour %s : %s",
+	"Could not save the selection: %s":                   "Impossible d'enregistrer la sélection : %s",
+	"… and %d more":                                      "… et %d de plus",
+	"Nothing selected yet":                               "Rien de sélectionné pour l'instant",
+	"Selected (%d)":                                      "Sélection (%d)",
+	"total %s":                                           "total %s",
+	"Nothing to undo.":                                   "Rien à annuler.",
+	"Undone, press ctrl+y to redo.":                      "Annulé, appuyez sur ctrl+y pour rétablir.",
+	"Nothing to redo.":                                   "Rien à rétablir.",
+	"Redone.":                                            "Rétabli.",
+	"up":                                                 "haut",
+	"down":                                               "bas",
+	"prev page":                                          "page précédente",
+	"next page":                                          "page suivante",
+	"go to start":                                        "aller au début",
+	"go to end":                                          "aller à la fin",
+	"filter":                                             "filtrer",
+	"clear filter":                                       "effacer le filtre",
+	"cancel":                                             "annuler",
+	"apply filter":                                       "appliquer le filtre",
+	"more":                                               "plus",
+	"close help":                                         "fermer l'aide",
+	"quit":                                               "quitter",
+	"no speech":                                          "sans parole",
+	"filler":                                             "remplissage",
+	"old":                                                "ancien",
+	"new":                                                "nouveau",
+	"There's no line after this one to merge with.":                  "Il n'y a aucune ligne après celle-ci avec laquelle la fusionner.",
+	"The next line is hidden by the filter, clear it to merge them.": "La ligne suivante est masquée par le filtre, effacez-le pour les fusionner.",
+	"Lines from different speakers can't be merged.":                 "Impossible de fusionner des lignes d'intervenants différents.",
+	"A line needs at least two words to be split.":                   "Une ligne doit avoir au moins deux mots pour être coupée.",
+	"This line is too short to split there.":                         "Cette ligne est trop courte pour être coupée à cet endroit.",
+	"split at %s: ←/→ move • enter split • esc cancel":               "couper à %s : ←/→ déplacer • enter couper • esc annuler",
+	"merge with next": "fusionner avec la suivante",
+	"split":           "couper",
+	"A previous compile didn't finish, restored its %d selected lines. Press c to compile again.": "Une compilation précédente n'a pas abouti, ses %d lignes sélectionnées ont été restaurées. Appuyez sur c pour compiler à nouveau.",
+	"Compiling may fail, %s":                                                         "La compilation risque d'échouer, %s",
+	"Could not read the meeting chat: %s":                                            "Impossible de lire le chat de la réunion : %s",
+	"Editing with the %s profile.":                                                   "Montage avec le profil %s.",
+	"Encrypted %d existing project files.":                                           "%d fichiers de projet existants chiffrés.",
+	"Loaded %d lines selected in a shared session.":                                  "%d lignes sélectionnées dans une session partagée chargées.",
+	"Making a low-res preview proxy in the background.":                              "Création d'un proxy d'aperçu basse résolution en arrière-plan.",
+	"No meeting in the calendar matches when this was recorded":                      "Aucune réunion du calendrier ne correspond au moment de l'enregistrement",
+	"Reading the source from the network drive, %s":                                  "Lecture de l'original depuis le lecteur réseau, %s",
+	"Recorded during %s with %d attendees":                                           "Enregistré pendant %s avec %d participants",
+	"Resuming with the audio extracted by a previous run.":                           "Reprise avec l'audio extrait lors d'une exécution précédente.",
+	"Saved %d chat messages to %s":                                                   "%d messages du chat enregistrés dans %s",
+	"Selections won't be saved, %s":                                                  "Les sélections ne seront pas enregistrées, %s",
+	"Source is on a network drive, working from a local copy.":                       "L'original est sur un lecteur réseau, le travail se fait sur une copie locale.",
+	"Start timecode %s read from metadata.":                                          "Timecode de début %s lu dans les métadonnées.",
+	"Transcript already exists locally":                                              "La transcription existe déjà localement",
+	"Transcript already exists locally, re-transcribing":                             "La transcription existe déjà localement, nouvelle transcription",
+	"Transcript created from %d participants' audio":                                 "Transcription créée à partir de l'audio de %d participants",
+	"Transcript created from YouTube captions":                                       "Transcription créée à partir des sous-titres YouTube",
+	"Transcript extracted from subtitle track":                                       "Transcription extraite de la piste de sous-titres",
+	"Variable frame rate detected, output will be converted to a constant %.2f fps.": "Fréquence d'images variable détectée, le résultat sera converti à %.2f ips constantes.",
+	"Compiling with --copy, each cut starts at the keyframe before its line.":        "Compilation avec --copy, chaque coupe commence à l'image clé précédant sa ligne.",
+	"Compiling each segment as a clip with ffmpeg...":                                "Compilation de chaque segment en clip avec ffmpeg...",
+	"Saved %d clips to %s":                                                           "%d clips enregistrés dans %s",
+	"export clips":                                                                   "exporter les clips",
+	"%s isn't installed":                                                             "%s n'est pas installé",
+	"A tool tsplice ran failed":                                                      "Un outil lancé par tsplice a échoué",
+	"API key":                                                                        "clé d'API",
+	"Add credit to the account, or transcribe with --local.":                         "Ajoutez du crédit au compte, ou transcrivez avec --local.",
+	"Check that %s is running, then try again.":                                      "Vérifiez que %s est lancé, puis réessayez.",
+	"Check that the key is right and still active, or enter a new one.":              "Vérifiez que la clé est correcte et toujours active, ou saisissez-en une nouvelle.",
+	"Check the internet connection, then try again.":                                 "Vérifiez la connexion internet, puis réessayez.",
+	"Check the key api_key_cmd prints, it's read again on the next run.":             "Vérifiez la clé affichée par api_key_cmd, elle est relue au prochain lancement.",
+	"Could not reach the transcription service":                                      "Impossible de joindre le service de transcription",
+	"Could not save the key: %s":                                                     "Impossible d'enregistrer la clé : %s",
+	"Free up some space, then try again.":                                            "Libérez de l'espace, puis réessayez.",
+	"Install %s, or put it on the PATH, then run tsplice again.":                     "Installez %s ou ajoutez-le au PATH, puis relancez tsplice.",
+	"It can be sent in smaller chunks, which are joined back into one transcript.":   "Il peut être envoyé en morceaux plus petits, réunis ensuite en une seule transcription.",
+	"It's usually brief, try again in a moment.":                                     "C'est généralement bref, réessayez dans un instant.",
+	"Log: %s":                               "Journal : %s",
+	"Offline mode kept this on the machine": "Le mode hors ligne a gardé cela sur cette machine",
+	"Something went wrong":                  "Une erreur s'est produite",
+	"The audio is too big for the transcription service": "L'audio est trop volumineux pour le service de transcription",
+	"The disk is full":                                                                              "Le disque est plein",
+	"The transcription account is out of credit":                                                    "Le compte de transcription n'a plus de crédit",
+	"The transcription service had a problem":                                                       "Le service de transcription a rencontré un problème",
+	"The transcription service is limiting requests":                                                "Le service de transcription limite les requêtes",
+	"The transcription service turned down the API key":                                             "Le service de transcription a refusé la clé d'API",
+	"Transcribe with --local or --stt-command, or run without --offline.":                           "Transcrivez avec --local ou --stt-command, ou lancez sans --offline.",
+	"Transcribe with --local, or a provider that takes bigger files.":                               "Transcrivez avec --local, ou un fournisseur qui accepte des fichiers plus gros.",
+	"Upgrade to a recent full build of ffmpeg, like the ones linked from ffmpeg.org/download.html.": "Passez à une version complète et récente de ffmpeg, comme celles proposées sur ffmpeg.org/download.html.",
+	"Wait a moment, then try again.":                                                                "Patientez un instant, puis réessayez.",
+	"What it printed is in the log, try again once it's sorted out.":                                "Ce qu'il a affiché est dans le journal, réessayez une fois le problème réglé.",
+	"c send in smaller chunks":                                                                      "c envoyer en morceaux plus petits",
+	"enter save and transcribe again • esc cancel":                                                  "enter enregistrer et retranscrire • esc annuler",
+	"esc back to the editor":                                                                        "esc retour à l'éditeur",
+	"k enter a new key":                                                                             "k saisir une nouvelle clé",
+	"q quit":                                                                                        "q quitter",
+	"r try again":                                                                                   "r réessayer",
+	"This ffmpeg doesn't have the %s filter":                                                        "Ce ffmpeg n'a pas le filtre %s",
+	"This ffmpeg doesn't have the %s encoder":                                                       "Ce ffmpeg n'a pas l'encodeur %s",
+	"This ffmpeg doesn't know the %s option":                                                        "Ce ffmpeg ne connaît pas l'option %s",
+}
@@ -0,0 +1,216 @@
+package main
+
+// translationsPT is the editor's text in Portuguese, by the English text.
+var translationsPT = map[string]string{
+	"Auto-edit found no filler or silent lines to remove.":                      "A edição automática não encontrou linhas de preenchimento ou silenciosas para remover.",
+	"Auto-edit would remove %d lines, marked ✂. Press enter on any to keep it.": "A edição automática removeria %d linhas, marcadas com ✂. Pressione enter em qualquer uma para mantê-la.",
+	"Auto-edit removed %d lines, %d kept.":                                      "A edição automática removeu %d linhas, %d mantidas.",
+	"Auto-edit discarded.":                                                      "Edição automática descartada.",
+	"Selected %d visible segments.":                                             "%d segmentos visíveis selecionados.",
+	"Deselected %d visible segments.":                                           "%d segmentos visíveis desmarcados.",
+	"Padded %d visible segments.":                                               "%d segmentos visíveis estendidos.",
+	"Trimmed %d visible segments.":                                              "%d segmentos visíveis encurtados.",
+	"Tagged #%s on %d visible segments.":                                        "Tag #%s adicionada a %d segmentos visíveis.",
+	"Transcript changes saved locally.":                                         "Alterações na transcrição salvas localmente.",
+	"Comparing with previous transcript (%d of %d segments changed)":            "Comparando com a transcrição anterior (%d de %d segmentos alterados)",
+	"↑/↓ move • o/n keep old/new • tab toggle • O/N keep all • enter save":      "↑/↓ mover • o/n manter antigo/novo • tab alternar • O/N manter todos • enter salvar",
+	"(no segment)":                                               "(sem segmento)",
+	"Could not save the transcript: %s":                          "Não foi possível salvar a transcrição: %s",
+	"Could not save the word timings: %s":                        "Não foi possível salvar os tempos das palavras: %s",
+	"Transcribing locally with %s...":                            "Transcrevendo localmente com %s...",
+	"Transcribing with %s...":                                    "Transcrevendo com %s...",
+	"Could not start audio preview: %s":                          "Não foi possível iniciar a prévia de áudio: %s",
+	"No API key saved, run tsplice with --retranscribe instead.": "Nenhuma chave de API salva, execute o tsplice com --retranscribe.",
+	"Extracting audio with ffmpeg...":                            "Extraindo o áudio com o ffmpeg...",
+	"Re-transcribe in language (e.g. en, es, auto): ":            "Transcrever de novo no idioma (ex.: en, es, auto): ",
+	"Language: %s":                                               "Idioma: %s",
+	"Filter: ":                                                   "Filtro: ",
+	"Macro recorded, press @ to replay it.":                      "Macro gravada, pressione @ para repeti-la.",
+	"boost":                                                      "reforço",
+	"kept, %s":                                                   "mantida, %s",
+	"tag":                                                        "marcar",
+	"export tags":                                                "exportar tags",
+	"apply rules":                                                "aplicar regras",
+	"preview":                                                    "prévia",
+	"audio preview":                                              "prévia de áudio",
+	"compile":                                                    "compilar",
+	"export edl":                                                 "exportar edl",
+	"export captions":                                            "exportar legendas",
+	"export links":                                               "exportar links",
+	"re-transcribe":                                              "transcrever de novo",
+	"audiograms":                                                 "audiogramas",
+	"zen":                                                        "zen",
+	"summary":                                                    "resumo",
+	"stats":                                                      "estatísticas",
+	"name speakers":                                              "nomear falantes",
+	"pad":                                                        "estender",
+	"boost quiet":                                                "reforçar voz baixa",
+	"bulk":                                                       "em massa",
+	"edit text":                                                  "editar texto",
+	"select range":                                               "selecionar intervalo",
+	"select all/none/invert":                                     "selecionar tudo/nada/inverter",
+	"auto-edit":                                                  "edição automática",
+	"undo/redo":                                                  "desfazer/refazer",
+	"record/replay macro":                                        "gravar/repetir macro",
+	"Compiling video segments with ffmpeg...": "Compilando os segmentos de vídeo com o ffmpeg...",
+	"Exported analytics to %s":                "Estatísticas exportadas para %s",
+	" and ":                                   " e ",
+	"Read-only, another tsplice is editing this video.":                                           "Somente leitura, outro tsplice está editando este vídeo.",
+	"Speakers can't be renamed while the video is open read-only.":                                "Não é possível renomear os falantes com o vídeo aberto em somente leitura.",
+	"No speaker labels in this transcript to name.":                                               "Esta transcrição não tem rótulos de falante para nomear.",
+	"Exported %d tag cut lists.":                                                                  "%d listas de cortes por tag exportadas.",
+	"Exported EDL to %s":                                                                          "EDL exportada para %s",
+	"Exported selected captions to %s":                                                            "Legendas selecionadas exportadas para %s",
+	"Exported links to %s":                                                                        "Links exportados para %s",
+	"Select the segments to export as audiograms first.":                                          "Selecione primeiro os segmentos a exportar como audiogramas.",
+	"Rendering audiograms with ffmpeg...":                                                         "Renderizando audiogramas com o ffmpeg...",
+	"Rules applied, %d segments changed.":                                                         "Regras aplicadas, %d segmentos alterados.",
+	"Audio extracted from ffmpeg.":                                                                "Áudio extraído com o ffmpeg.",
+	"Transcription finished.":                                                                     "Transcrição concluída.",
+	"Transcription finished and saved locally.":                                                   "Transcrição concluída e salva localmente.",
+	"Previews will play the source, %s":                                                           "As prévias vão reproduzir o original, %s",
+	"Preview proxy ready, previews now play a low-res copy.":                                      "Proxy de prévia pronto, as prévias agora reproduzem uma cópia em baixa resolução.",
+	"Embedded %d lines before failing, the rest are embedded next time: %s":                       "Embeddings gerados para %d linhas antes da falha, o resto será gerado na próxima vez: %s",
+	"Embedded %d lines for tsplice search --semantic.":                                            "Embeddings gerados para %d linhas para tsplice search --semantic.",
+	"Could not look for names to redact: %s":                                                      "Não foi possível procurar nomes para ocultar: %s",
+	"Redacting %d names, email addresses, and phone numbers from exports and the compiled audio.": "Ocultando %d nomes, endereços de e-mail e números de telefone nas exportações e no áudio compilado.",
+	"Video compiled successfully.":                                                                "Vídeo compilado com sucesso.",
+	"Saved output to %s":                                                                          "Resultado salvo em %s",
+	"Saved manifest to %s":                                                                        "Manifesto salvo em %s",
+	"Once the draft looks right, run tsplice --final %s to render it at full quality.":            "Quando o rascunho estiver bom, execute tsplice --final %s para renderizá-lo em qualidade total.",
+	"Output verified, audio and video are in sync.":                                               "Resultado verificado, áudio e vídeo estão sincronizados.",
+	"Warning: %s":                          "Aviso: %s",
+	"Exported %d audiograms.":              "%d audiogramas exportados.",
+	"No transcript items found":            "Nenhuma linha encontrada na transcrição",
+	"Start: %s | End: %s":                  "Início: %s | Fim: %s",
+	"read-only":                            "somente leitura",
+	"offline, nothing leaves this machine": "offline, nada sai desta máquina",
+	"recording macro":                      "gravando macro",
+	"%d audio tracks: k keep all • s export stems • enter first only • esc cancel": "%d faixas de áudio: k manter todas • s exportar stems • enter só a primeira • esc cancelar",
+	"auto-edit preview: enter keep/remove • F apply • esc discard":                 "prévia da edição automática: enter manter/remover • F aplicar • esc descartar",
+	"editing: enter save • esc cancel":                                             "editando: enter salvar • esc cancelar",
+	"range of %d: enter select • A deselect • i invert • esc cancel":               "intervalo de %d: enter selecionar • A desmarcar • i inverter • esc cancelar",
+	"all %d visible: s select • d deselect • 1-9 tag • +/- pad":                    "todos os %d visíveis: s selecionar • d desmarcar • 1-9 marcar • +/- estender",
+	"%s left":                 "faltam %s",
+	"Selected %d segments.":   "%d segmentos selecionados.",
+	"Deselected %d segments.": "%d segmentos desmarcados.",
+	"Inverted the selection of %d visible segments.":                                            "Seleção invertida em %d segmentos visíveis.",
+	"Inverted the selection of %d segments.":                                                    "Seleção invertida em %d segmentos.",
+	"Found %d email addresses or phone numbers, run with --redact to remove them from exports.": "%d endereços de e-mail ou números de telefone encontrados, execute com --redact para removê-los das exportações.",
+	"Redacting %d email addresses and phone numbers from exports and the compiled audio.":       "Ocultando %d endereços de e-mail e números de telefone nas exportações e no áudio compilado.",
+	"Could not save speaker names: %s":                                                          "Não foi possível salvar os nomes dos falantes: %s",
+	"Renamed %s to %s on %d lines.":                                                             "%s renomeado para %s em %d linhas.",
+	"Name the speakers (%d in this transcript)":                                                 "Nomeie os falantes (%d nesta transcrição)",
+	"was %s": "antes %s",
+	"↑/↓ move • p play a sample • enter name • esc done": "↑/↓ mover • p ouvir uma amostra • enter nomear • esc concluir",
+	"enter save • esc cancel":                            "enter salvar • esc cancelar",
+	"Overview":                                           "Visão geral",
+	"words":                                              "palavras",
+	"duration":                                           "duração",
+	"words per minute":                                   "palavras por minuto",
+	"rate over time":                                     "ritmo ao longo do tempo",
+	"Speakers":                                           "Falantes",
+	"no speaker labels in this transcript":               "nenhum rótulo de falante nesta transcrição",
+	"%.0f%% (%s, %d words)":                              "%.0f%% (%s, %d palavras)",
+	"Longest silences":                                   "Silêncios mais longos",
+	"none":                                               "nenhum",
+	"Filler words":                                       "Palavras de preenchimento",
+	"Press x to export as JSON/CSV, S or esc to go back": "Pressione x para exportar como JSON/CSV, S ou esc para voltar",
+	"Could not update %s: %s":                            "Não foi possível atualizar %s: %s",
+	"Could not save the selection: %s":                   "Não foi possível salvar a seleção: %s",
+	"… and %d more":                                      "… e mais %d",
+	"Nothing selected yet":                               "Nada selecionado ainda",
+	"Selected (%d)":                                      "Selecionados (%d)",
+	"total %s":                                           "total %s",
+	"Nothing to undo.":                                   "Nada para desfazer.",
+	"Undone, press ctrl+y to redo.":                      "Desfeito, pressione ctrl+y para refazer.",
+	"Nothing to redo.":                                   "Nada para refazer.",
+	"Redone.":                                            "Refeito.",
+	"up":                                                 "cima",
+	"down":                                               "baixo",
+	"prev page":                                          "página anterior",
+	"next page":                                          "próxima página",
+	"go to start":                                        "ir ao início",
+	"go to end":                                          "ir ao fim",
+	"filter":                                             "filtrar",
+	"clear filter":                                       "limpar filtro",
+	"cancel":                                             "cancelar",
+	"apply filter":                                       "aplicar filtro",
+	"more":                                               "mais",
+	"close help":                                         "fechar ajuda",
+	"quit":                                               "sair",
+	"no speech":                                          "sem fala",
+	"filler":                                             "preenchimento",
+	"old":                                                "antigo",
+	"new":                                                "novo",
+	"There's no line after this one to merge with.":                  "Não há nenhuma linha depois desta para juntar.",
+	"The next line is hidden by the filter, clear it to merge them.": "A próxima linha está oculta pelo filtro, limpe-o para juntá-las.",
+	"Lines from different speakers can't be merged.":                 "Não é possível juntar linhas de falantes diferentes.",
+	"A line needs at least two words to be split.":                   "Uma linha precisa de pelo menos duas palavras para ser dividida.",
+	"This line is too short to split there.":                         "Esta linha é curta demais para ser dividida aí.",
+	"split at %s: ←/→ move • enter split • esc cancel":               "dividir em %s: ←/→ mover • enter dividir • esc cancelar",
+	"merge with next": "juntar com a próxima",
+	"split":           "dividir",
+	"A previous compile didn't finish, restored its %d selected lines. Press c to compile again.": "Uma compilação anterior não terminou, suas %d linhas selecionadas foram restauradas. Pressione c para compilar de novo.",
+	"Compiling may fail, %s":                                                         "A compilação pode falhar, %s",
+	"Could not read the meeting chat: %s":                                            "Não foi possível ler o chat da reunião: %s",
+	"Editing with the %s profile.":                                                   "Editando com o perfil %s.",
+	"Encrypted %d existing project files.":                                           "%d arquivos de projeto existentes criptografados.",
+	"Loaded %d lines selected in a shared session.":                                  "%d linhas selecionadas em uma sessão compartilhada carregadas.",
+	"Making a low-res preview proxy in the background.":                              "Criando um proxy de prévia em baixa resolução em segundo plano.",
+	"No meeting in the calendar matches when this was recorded":                      "Nenhuma reunião do calendário corresponde ao momento da gravação",
+	"Reading the source from the network drive, %s":                                  "Lendo o original da unidade de rede, %s",
+	"Recorded during %s with %d attendees":                                           "Gravado durante %s com %d participantes",
+	"Resuming with the audio extracted by a previous run.":                           "Continuando com o áudio extraído por uma execução anterior.",
+	"Saved %d chat messages to %s":                                                   "%d mensagens do chat salvas em %s",
+	"Selections won't be saved, %s":                                                  "As seleções não serão salvas, %s",
+	"Source is on a network drive, working from a local copy.":                       "O original está em uma unidade de rede, trabalhando com uma cópia local.",
+	"Start timecode %s read from metadata.":                                          "Timecode inicial %s lido dos metadados.",
+	"Transcript already exists locally":                                              "A transcrição já existe localmente",
+	"Transcript already exists locally, re-transcribing":                             "A transcrição já existe localmente, transcrevendo de novo",
+	"Transcript created from %d participants' audio":                                 "Transcrição criada a partir do áudio de %d participantes",
+	"Transcript created from YouTube captions":                                       "Transcrição criada a partir das legendas do YouTube",
+	"Transcript extracted from subtitle track":                                       "Transcrição extraída da faixa de legendas",
+	"Variable frame rate detected, output will be converted to a constant %.2f fps.": "Taxa de quadros variável detectada, o resultado será convertido para %.2f fps constantes.",
+	"Compiling with --copy, each cut starts at the keyframe before its line.":        "Compilando com --copy, cada corte começa no quadro-chave antes da sua linha.",
+	"Compiling each segment as a clip with ffmpeg...":                                "Compilando cada segmento como um clipe com ffmpeg...",
+	"Saved %d clips to %s":                                                           "%d clipes salvos em %s",
+	"export clips":                                                                   "exportar clipes",
+	"%s isn't installed":                                                             "%s não está instalado",
+	"A tool tsplice ran failed":                                                      "Uma ferramenta executada pelo tsplice falhou",
+	"API key":                                                                        "chave de API",
+	"Add credit to the account, or transcribe with --local.":                         "Adicione crédito à conta ou transcreva com --local.",
+	"Check that %s is running, then try again.":                                      "Verifique se %s está em execução e tente novamente.",
+	"Check that the key is right and still active, or enter a new one.":              "Verifique se a chave está correta e ainda ativa, ou digite uma nova.",
+	"Check the internet connection, then try again.":                                 "Verifique a conexão com a internet e tente novamente.",
+	"Check the key api_key_cmd prints, it's read again on the next run.":             "Verifique a chave que o api_key_cmd imprime, ela é lida novamente na próxima execução.",
+	"Could not reach the transcription service":                                      "Não foi possível acessar o serviço de transcrição",
+	"Could not save the key: %s":                                                     "Não foi possível salvar a chave: %s",
+	"Free up some space, then try again.":                                            "Libere algum espaço e tente novamente.",
+	"Install %s, or put it on the PATH, then run tsplice again.":                     "Instale %s ou coloque-o no PATH e execute o tsplice novamente.",
+	"It can be sent in smaller chunks, which are joined back into one transcript.":   "Ele pode ser enviado em partes menores, que são unidas de volta em uma só transcrição.",
+	"It's usually brief, try again in a moment.":                                     "Costuma ser rápido, tente novamente em instantes.",
+	"Log: %s":                               "Log: %s",
+	"Offline mode kept this on the machine": "O modo offline manteve isso nesta máquina",
+	"Something went wrong":                  "Algo deu errado",
+	"The audio is too big for the transcription service": "O áudio é grande demais para o serviço de transcrição",
+	"The disk is full":                                                                              "O disco está cheio",
+	"The transcription account is out of credit":                                                    "A conta de transcrição está sem crédito",
+	"The transcription service had a problem":                                                       "O serviço de transcrição teve um problema",
+	"The transcription service is limiting requests":                                                "O serviço de transcrição está limitando as solicitações",
+	"The transcription service turned down the API key":                                             "O serviço de transcrição recusou a chave de API",
+	"Transcribe with --local or --stt-command, or run without --offline.":                           "Transcreva com --local ou --stt-command, ou execute sem --offline.",
+	"Transcribe with --local, or a provider that takes bigger files.":                               "Transcreva com --local ou com um provedor que aceite arquivos maiores.",
+	"Upgrade to a recent full build of ffmpeg, like the ones linked from ffmpeg.org/download.html.": "Atualize para uma versão completa e recente do ffmpeg, como as indicadas em ffmpeg.org/download.html.",
+	"Wait a moment, then try again.":                                                                "Aguarde um momento e tente novamente.",
+	"What it printed is in the log, try again once it's sorted out.":                                "O que ela imprimiu está no log, tente novamente quando estiver resolvido.",
+	"c send in smaller chunks":                                                                      "c enviar em partes menores",
+	"enter save and transcribe again • esc cancel":                                                  "enter salvar e transcrever novamente • esc cancelar",
+	"esc back to the editor":                                                                        "esc voltar ao editor",
+	"k enter a new key":                                                                             "k digitar uma nova chave",
+	"q quit":                                                                                        "q sair",
+	"r try again":                                                                                   "r tentar novamente",
+	"This ffmpeg doesn't have the %s filter":                                                        "Este ffmpeg não tem o filtro %s",
+	"This ffmpeg doesn't have the %s encoder":                                                       "Este ffmpeg não tem o codificador %s",
+	"This ffmpeg doesn't know the %s option":                                                        "Este ffmpeg não conhece a opção %s",
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// undoLimit is how many changes back ctrl+z can go.
+const undoLimit = 200
+
+// pushUndo records the lines as they are before a change, so ctrl+z can put
+// them back. Whatever was undone before can't be redone after it.
+func (m model) pushUndo() model {
+	return m.pushUndoItems(m.list.Items())
+}
+
+// pushUndoItems records lines other than the ones showing as the state to
+// go back to, like those from before an auto-edit was previewed.
+func (m model) pushUndoItems(items []list.Item) model {
+	// Changes are made to the list's own slice, so it's copied
+	m.undoStack = append(m.undoStack, undoState{items: slices.Clone(items), transcript: m.transcriptItems})
+	if len(m.undoStack) > undoLimit {
+		m.undoStack = slices.Clone(m.undoStack[len(m.undoStack)-undoLimit:])
+	}
+	m.redoStack = nil
+	return m
+}
+
+// undo puts back the lines from before the last change, keeping them as they
+// are now for ctrl+y.
+func (m model) undo() (model, tea.Cmd) {
+	if len(m.undoStack) == 0 {
+		m.statuses = append(m.statuses, tr("Nothing to undo."))
+		return m, nil
+	}
+	state := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = append(m.redoStack, m.currentState())
+	return m.restoreState(state, tr("Undone, press ctrl+y to redo."))
+}
+
+// redo makes a change that was undone again.
+func (m model) redo() (model, tea.Cmd) {
+	if len(m.redoStack) == 0 {
+		m.statuses = append(m.statuses, tr("Nothing to redo."))
+		return m, nil
+	}
+	state := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = append(m.undoStack, m.currentState())
+	return m.restoreState(state, tr("Redone."))
+}
+
+func (m model) currentState() undoState {
+	return undoState{items: slices.Clone(m.list.Items()), transcript: m.transcriptItems}
+}
+
+// restoreState puts the transcript back before the lines, since the store
+// only has as many lines to save the selection of as the transcript does.
+// Speakers keep the names they were given since.
+func (m model) restoreState(state undoState, status string) (model, tea.Cmd) {
+	speakers := map[string]string{}
+	for _, transcriptItem := range m.transcriptItems {
+		speakers[transcriptItem.StartTime] = transcriptItem.Speaker
+	}
+	transcriptItems := slices.Clone(state.transcript)
+	for index, transcriptItem := range transcriptItems {
+		if speaker, ok := speakers[transcriptItem.StartTime]; ok {
+			transcriptItems[index].Speaker = speaker
+		}
+	}
+
+	m, _ = m.saveTranscript(transcriptItems)
+	m = m.saveItems(state.items)
+	m.statuses = append(m.statuses, status)
+	return m, tea.Batch(m.list.SetItems(state.items), m.embedCmd())
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// speechThreshold is the level below which audio counts as non-speech,
+	// unless a profile sets another
+	speechThreshold = "-35dB"
+	// minNonSpeech is the shortest gap, in seconds, worth trimming
+	minNonSpeech = 0.2
+	// speechPadding is left around the trimmed speech so soft consonants at
+	// either end aren't clipped
+	speechPadding = 0.08
+)
+
+// trimSegments narrows every segment to where speech starts and stops inside
+// it, going by threshold when it's set. Segments that can't be analyzed are
+// kept as they are.
+func trimSegments(inputFile string, segments []segment, threshold string) []segment {
+	if threshold == "" {
+		threshold = speechThreshold
+	}
+
+	dir, err := tempDir("tsplice-vad-")
+	if err != nil {
+		return segments
+	}
+	defer removeTemp(dir)
+
+	trimmed := make([]segment, len(segments))
+	for index, s := range segments {
+		trimmed[index] = trimToSpeech(inputFile, s, threshold, filepath.Join(dir, fmt.Sprintf("segment_%d.txt", index)))
+	}
+	return trimmed
+}
+
+// trimToSpeech runs silencedetect over just the segment and drops any
+// non-speech touching its start or end.
+func trimToSpeech(inputFile string, s segment, threshold, metadataFile string) segment {
+	duration := s.end - s.start
+	err := execute.Run("ffmpeg", "-y",
+		"-ss", fmt.Sprintf("%.3f", s.start), "-t", fmt.Sprintf("%.3f", duration),
+		"-i", inputFile,
+		"-map", "0:a:0",
+		"-af", fmt.Sprintf("silencedetect=noise=%s:d=%.2f,ametadata=mode=print:file=%s", threshold, minNonSpeech, escapeFilterPath(metadataFile)),
+		"-f", "null", "-",
+	)
+	if err != nil {
+		return s
+	}
+
+	silences, err := readSilences(metadataFile, duration)
+	if err != nil || len(silences) == 0 {
+		return s
+	}
+
+	start, end := 0.0, duration
+	if first := silences[0]; first.Start <= speechPadding {
+		start = max(0, first.End-speechPadding)
+	}
+	if last := silences[len(silences)-1]; last.End >= duration-speechPadding {
+		end = min(duration, last.Start+speechPadding)
+	}
+
+	// All silence, better to leave it alone than cut it down to nothing
+	if end-start < minNonSpeech {
+		return s
+	}
+	return segment{start: s.start + start, end: s.start + end}
+}
+
+// readSilences parses the silence_start and silence_end lines ametadata
+// printed, closing a silence still open at the end of the segment.
+func readSilences(metadataFile string, duration float64) ([]timeRange, error) {
+	file, err := os.Open(metadataFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var silences []timeRange
+	open := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+
+		switch key {
+		case "lavfi.silence_start":
+			silences = append(silences, timeRange{Start: max(0, seconds), End: duration})
+			open = true
+		case "lavfi.silence_end":
+			if open {
+				silences[len(silences)-1].End = seconds
+				open = false
+			}
+		}
+	}
+
+	return silences, scanner.Err()
+}
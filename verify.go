@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// syncTolerance is how far apart two lengths can be, in seconds, before an
+// output is reported as out of sync or truncated.
+const syncTolerance = 0.5
+
+type outputProbe struct {
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		StartTime string `json:"start_time"`
+		Duration  string `json:"duration"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+// expectedDuration is how long a compile of the segments should come out.
+func expectedDuration(segments []segment, opts compileOptions) float64 {
+	var duration float64
+	for _, segment := range segments {
+		duration += segment.end - segment.start
+	}
+	for _, clip := range []string{opts.Intro, opts.Outro} {
+		if clip == "" {
+			continue
+		}
+		if clipDuration, err := probeDuration(clip); err == nil {
+			duration += clipDuration
+		}
+	}
+	return duration
+}
+
+// verifyOutput probes a compiled video and describes anything that suggests it
+// came out desynchronized or truncated. No warnings means it looks fine.
+func verifyOutput(outputFile string, expected float64) ([]string, error) {
+	out, err := execute.Output("ffprobe", "-v", "error",
+		"-show_entries", "stream=codec_type,start_time,duration:format=duration",
+		"-of", "json", outputFile,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe output: %w", err)
+	}
+
+	var probe outputProbe
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse output info: %w", err)
+	}
+
+	var warnings []string
+	tolerance := max(syncTolerance, expected*0.01)
+
+	if duration, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil && math.Abs(duration-expected) > tolerance {
+		warnings = append(warnings, fmt.Sprintf("Output is %s long, expected %s.", formatDuration(duration), formatDuration(expected)))
+	}
+
+	var videoStart, videoDuration float64
+	hasVideo := false
+	for _, stream := range probe.Streams {
+		start, _ := strconv.ParseFloat(stream.StartTime, 64)
+		duration, err := strconv.ParseFloat(stream.Duration, 64)
+		if err != nil {
+			continue
+		}
+
+		switch stream.CodecType {
+		case "video":
+			if !hasVideo {
+				hasVideo = true
+				videoStart, videoDuration = start, duration
+			}
+		case "audio":
+			if !hasVideo {
+				continue
+			}
+			if gap := math.Abs(duration - videoDuration); gap > tolerance {
+				warnings = append(warnings, fmt.Sprintf("Audio and video lengths differ by %.2fs.", gap))
+			}
+			if offset := start - videoStart; math.Abs(offset) > 0.1 {
+				warnings = append(warnings, fmt.Sprintf("Audio starts %dms away from the video.", int(offset*1000)))
+			}
+		}
+	}
+
+	return warnings, nil
+}
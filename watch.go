@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dayPrefix starts the name of every day's compilation, which is also how
+// they're told apart from new replays in the same folder.
+const dayPrefix = "replays_"
+
+// dayProject is every replay saved on one day, in the order they came in.
+type dayProject struct {
+	Date  string   `json:"date"`
+	Clips []string `json:"clips"`
+}
+
+func dayProjectPath(dir string, date string) string {
+	return filepath.Join(dir, dayPrefix+date+".project.json")
+}
+
+func loadDayProject(dir string, date string) dayProject {
+	project := dayProject{Date: date}
+	if content, err := readProjectFile(dayProjectPath(dir, date)); err == nil {
+		json.Unmarshal(content, &project)
+	}
+	return project
+}
+
+// buildDay joins the day's replays into one video with one transcript, so it
+// opens in tsplice like any other recording with every stream moment in it.
+func buildDay(dir string, project dayProject) (string, error) {
+	content, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := writeProjectFile(dayProjectPath(dir, project.Date), content); err != nil {
+		return "", err
+	}
+
+	listFile, err := os.CreateTemp("", "tsplice-concat-*.txt")
+	if err != nil {
+		return "", err
+	}
+	removeOnExit(listFile.Name())
+	defer removeTemp(listFile.Name())
+
+	var transcriptItems []TranscriptItem
+	offset := 0.0
+	for _, clip := range project.Clips {
+		absolute, err := filepath.Abs(clip)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(listFile, "file '%s'\n", strings.ReplaceAll(absolute, "'", `'\''`))
+
+		clipItems, _ := loadTranscript(vttPath(clip))
+		for _, transcriptItem := range clipItems {
+			start, _ := parseTimeToSeconds(transcriptItem.StartTime)
+			end, _ := parseTimeToSeconds(transcriptItem.EndTime)
+			transcriptItem.StartTime = formatTimestamp(offset + start)
+			transcriptItem.EndTime = formatTimestamp(offset + end)
+			transcriptItems = append(transcriptItems, transcriptItem)
+		}
+
+		duration, err := probeDuration(clip)
+		if err != nil {
+			return "", err
+		}
+		offset += duration
+	}
+	listFile.Close()
+
+	// Replays from one OBS setup share their codecs, so they join without re-encoding
+	outputFile := filepath.Join(dir, dayPrefix+project.Date+".mp4")
+	err = renderTo(outputFile, func(partial string) error {
+		if err := execute.Run("ffmpeg", "-y", "-f", "concat", "-safe", "0", "-i", listFile.Name(), "-c", "copy", partial); err != nil {
+			return fmt.Errorf("failed to join replays: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return outputFile, writeProjectFile(vttPath(outputFile), []byte(formatVTT(transcriptItems)))
+}
+
+// newReplays returns videos in dir that weren't there before and have stopped
+// growing since the last look, updating sizes for the next one.
+func newReplays(dir string, seen map[string]bool, sizes map[string]int64) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var ready []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, dayPrefix) || !slices.Contains(validExtensions, strings.ToLower(filepath.Ext(name))) {
+			continue
+		}
+		file := filepath.Join(dir, name)
+		if seen[file] {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if previous, ok := sizes[file]; ok && previous == info.Size() && info.Size() > 0 {
+			seen[file] = true
+			delete(sizes, file)
+			ready = append(ready, file)
+			continue
+		}
+		sizes[file] = info.Size()
+	}
+	return ready
+}
+
+// watchFlags are the options of tsplice watch.
+type watchFlags struct {
+	interval time.Duration
+	existing bool
+}
+
+func (f *watchFlags) define(fs *flag.FlagSet) {
+	fs.DurationVar(&f.interval, "interval", 2*time.Second, "How often to check the folder for new replays")
+	fs.BoolVar(&f.existing, "existing", false, "Also process replays already in the folder")
+}
+
+func runWatch(args []string) error {
+	fs := newCommandFlagSet("watch")
+	var flags watchFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: tsplice watch [options] <obs-output-folder>")
+	}
+
+	dir := positional[0]
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("'%s' is not a folder", dir)
+	}
+
+	if err := setupAPIKey(); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	sizes := map[string]int64{}
+	if !flags.existing {
+		newReplays(dir, seen, sizes)
+		for file := range sizes {
+			seen[file] = true
+		}
+	}
+
+	fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Watching "+dir+" for replays, press ctrl+c to stop"))
+	for {
+		for _, replay := range newReplays(dir, seen, sizes) {
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render("New replay "+filepath.Base(replay)))
+			if _, err := transcriptFor(replay, defaultTranscriber()); err != nil {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Could not transcribe "+filepath.Base(replay)+": "+err.Error()))
+				continue
+			}
+
+			date := time.Now().Format("2006-01-02")
+			if info, err := os.Stat(replay); err == nil {
+				date = info.ModTime().Format("2006-01-02")
+			}
+
+			project := loadDayProject(dir, date)
+			project.Clips = append(project.Clips, replay)
+			outputFile, err := buildDay(dir, project)
+			if err != nil {
+				fmt.Println(BulletStyle.Render("├") + TextStyle.Render("Could not update "+date+": "+err.Error()))
+				continue
+			}
+			fmt.Println(BulletStyle.Render("├") + TextStyle.Render(fmt.Sprintf("Added to %s, %d replays so far", outputFile, len(project.Clips))))
+		}
+		time.Sleep(flags.interval)
+	}
+}
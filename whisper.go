@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// localWhisper is set with --local or local in the config, transcribing
+	// with whisper.cpp everywhere instead of OpenAI
+	localWhisper bool
+	// whisperModel is the ggml model file whisper.cpp loads
+	whisperModel string
+	// whisperBinary is the whisper.cpp program, found on the PATH when unset
+	whisperBinary string
+)
+
+// whisperBinaries are the names whisper.cpp's CLI is installed under, the
+// second being the Homebrew one.
+var whisperBinaries = []string{"whisper-cli", "whisper-cpp"}
+
+// whisperTranscriber transcribes with a local whisper.cpp, so the audio never
+// leaves the machine.
+type whisperTranscriber struct {
+	language string
+	prompt   string
+}
+
+// whisperOutput is the part of whisper.cpp's full JSON output that's used.
+type whisperOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets whisperOffsets `json:"offsets"`
+		Text    string         `json:"text"`
+		Tokens  []struct {
+			Text    string         `json:"text"`
+			Offsets whisperOffsets `json:"offsets"`
+			P       float64        `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
+}
+
+// whisperOffsets are in milliseconds.
+type whisperOffsets struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func findWhisper() (string, error) {
+	if whisperBinary != "" {
+		path, err := exec.LookPath(whisperBinary)
+		if err != nil {
+			return "", fmt.Errorf("could not find whisper.cpp at %s, set by whisper_bin in %s", whisperBinary, configPath())
+		}
+		return path, nil
+	}
+	for _, name := range whisperBinaries {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("could not find whisper.cpp, install it or set whisper_bin in %s", configPath())
+}
+
+// checkWhisper fails early when whisper.cpp or its model is missing, rather
+// than after the audio has been extracted.
+func checkWhisper() error {
+	if _, err := findWhisper(); err != nil {
+		return err
+	}
+	if whisperModel == "" {
+		return fmt.Errorf("--local needs a whisper.cpp model, set --whisper-model or whisper_model in %s", configPath())
+	}
+	if _, err := os.Stat(whisperModel); err != nil {
+		return fmt.Errorf("could not find the whisper.cpp model %s", whisperModel)
+	}
+	return nil
+}
+
+func (t whisperTranscriber) Transcribe(audioFile string) (string, transcriptDetails, error) {
+	defer recordTiming("local transcription", time.Now())
+
+	var details transcriptDetails
+	if err := checkWhisper(); err != nil {
+		return "", details, err
+	}
+	binary, _ := findWhisper()
+
+	dir, err := tempDir("tsplice-whisper-*")
+	if err != nil {
+		return "", details, err
+	}
+	defer removeTemp(dir)
+
+	// whisper.cpp only reads 16kHz WAV
+	wavFile := filepath.Join(dir, "audio.wav")
+	if err := execute.Run("ffmpeg", "-y", "-i", audioFile, "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", wavFile); err != nil {
+		return "", details, fmt.Errorf("failed to convert audio for whisper.cpp: %w", err)
+	}
+
+	language := t.language
+	if language == "" {
+		language = "auto"
+	}
+	output := filepath.Join(dir, "transcript")
+	args := []string{"-m", whisperModel, "-f", wavFile, "-l", language, "-ojf", "-of", output, "-np"}
+	if t.prompt != "" {
+		args = append(args, "--prompt", t.prompt)
+	}
+
+	cmd := exec.Command(binary, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := runChild(cmd); err != nil {
+		return "", details, fmt.Errorf("%s failed: %w: %s", filepath.Base(binary), err, strings.TrimSpace(stderr.String()))
+	}
+
+	outputBytes, err := os.ReadFile(output + ".json")
+	if err != nil {
+		return "", details, fmt.Errorf("whisper.cpp didn't write a transcript: %w", err)
+	}
+	var transcription whisperOutput
+	if err := json.Unmarshal(outputBytes, &transcription); err != nil {
+		return "", details, fmt.Errorf("failed to read whisper.cpp's transcript: %w", err)
+	}
+
+	details.Language = transcription.Result.Language
+	details.Confidence = map[string]float64{}
+
+	var transcriptItems []TranscriptItem
+	for _, s := range transcription.Transcription {
+		text := strings.TrimSpace(s.Text)
+		if text == "" {
+			continue
+		}
+		transcriptItem := TranscriptItem{
+			StartTime: formatTimestamp(float64(s.Offsets.From) / 1000),
+			EndTime:   formatTimestamp(float64(s.Offsets.To) / 1000),
+			Text:      text,
+		}
+		transcriptItems = append(transcriptItems, transcriptItem)
+
+		// Tokens are pieces of words, a new word starts at each space
+		first := len(details.Words)
+		var probability float64
+		tokens := 0
+		for _, token := range s.Tokens {
+			if strings.HasPrefix(token.Text, "[_") || strings.HasPrefix(token.Text, "<|") {
+				continue
+			}
+			probability += math.Log(max(token.P, 1e-6))
+			tokens++
+
+			start, end := float64(token.Offsets.From)/1000, float64(token.Offsets.To)/1000
+			piece := strings.TrimSpace(token.Text)
+			if n := len(details.Words); n > first && !strings.HasPrefix(token.Text, " ") && piece != "" {
+				details.Words[n-1].Text += piece
+				details.Words[n-1].End = end
+			} else if piece != "" {
+				details.Words = append(details.Words, Word{Start: start, End: end, Text: piece})
+			}
+		}
+		if tokens > 0 {
+			details.Confidence[transcriptItem.StartTime] = math.Exp(probability / float64(tokens))
+		}
+	}
+
+	return formatVTT(transcriptItems), details, nil
+}
+
+func (t whisperTranscriber) WithLanguage(language string) Transcriber {
+	t.language = language
+	return t
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Word struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// transcriptDetails holds what a VTT file can't, and is saved alongside it.
+type transcriptDetails struct {
+	Language   string             `json:"language,omitempty"`
+	Words      []Word             `json:"words,omitempty"`
+	Confidence map[string]float64 `json:"confidence,omitempty"`
+	// Speakers maps labels like SPEAKER_00 to the names they were given
+	Speakers map[string]string `json:"speakers,omitempty"`
+}
+
+func detailsPath(vttFile string) string {
+	return strings.TrimSuffix(vttFile, ".vtt") + ".json"
+}
+
+func saveDetails(vttFile string, details transcriptDetails) error {
+	content, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeProjectFile(detailsPath(vttFile), content)
+}
+
+// loadDetails returns empty details when there is no sidecar for the transcript.
+func loadDetails(vttFile string) (transcriptDetails, error) {
+	var details transcriptDetails
+
+	content, err := readProjectFile(detailsPath(vttFile))
+	if os.IsNotExist(err) {
+		return details, nil
+	} else if err != nil {
+		return details, err
+	}
+
+	if err := json.Unmarshal(content, &details); err != nil {
+		return details, fmt.Errorf("could not parse %s: %w", detailsPath(vttFile), err)
+	}
+	return details, nil
+}
+
+func applyDetails(transcriptItems []TranscriptItem, details transcriptDetails) []TranscriptItem {
+	for i := range transcriptItems {
+		if confidence, ok := details.Confidence[transcriptItems[i].StartTime]; ok {
+			transcriptItems[i].Confidence = confidence
+		}
+		if name := details.Speakers[transcriptItems[i].Speaker]; name != "" {
+			transcriptItems[i].Speaker = name
+		}
+	}
+	return transcriptItems
+}
+
+// estimateWords spreads each segment's duration over its words by character
+// count, for transcripts that came without word-level timestamps.
+func estimateWords(transcriptItems []TranscriptItem) []Word {
+	var words []Word
+
+	for _, transcriptItem := range transcriptItems {
+		start, err := parseTimeToSeconds(transcriptItem.StartTime)
+		if err != nil {
+			continue
+		}
+		end, err := parseTimeToSeconds(transcriptItem.EndTime)
+		if err != nil {
+			continue
+		}
+
+		fields := strings.Fields(transcriptItem.Text)
+		characters := 0
+		for _, field := range fields {
+			characters += len([]rune(field)) + 1
+		}
+		if characters == 0 {
+			continue
+		}
+
+		position := start
+		perCharacter := (end - start) / float64(characters)
+		for _, field := range fields {
+			length := float64(len([]rune(field))+1) * perCharacter
+			words = append(words, Word{Start: position, End: position + length, Text: field})
+			position += length
+		}
+	}
+
+	return words
+}
+
+// wordsBetween returns the words overlapping start to end. Words are kept in
+// time order, so they're found without scanning a multi-hour transcript's worth.
+func wordsBetween(words []Word, start, end float64) []Word {
+	from := sort.Search(len(words), func(i int) bool { return words[i].End > start })
+	to := from
+	for to < len(words) && words[to].Start < end {
+		to++
+	}
+	return words[from:to]
+}
+
+// wordsFor returns the real word timings if there are any, otherwise an estimate.
+func (m model) wordsFor() []Word {
+	if len(m.details.Words) > 0 {
+		return m.details.Words
+	}
+	return estimateWords(m.transcriptItems)
+}
+
+// matchesWordList reports whether a word is on the list, where entries ending
+// in * match any word with that prefix.
+func matchesWordList(word string, list []string) bool {
+	for _, normalized := range normalizeWords(word) {
+		for _, entry := range list {
+			entry = strings.ToLower(strings.TrimSpace(entry))
+			if prefix, ok := strings.CutSuffix(entry, "*"); ok && prefix != "" {
+				if strings.HasPrefix(normalized, prefix) {
+					return true
+				}
+			} else if normalized == entry {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bleepRanges finds the source time ranges of every word on the bleep list.
+func bleepRanges(words []Word, bleep []string) []timeRange {
+	var ranges []timeRange
+	if len(bleep) == 0 {
+		return ranges
+	}
+
+	for _, word := range words {
+		if matchesWordList(word.Text, bleep) {
+			ranges = append(ranges, timeRange{Start: word.Start, End: word.End})
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// workspaceLogName is the log in each workspace of the tools a run used and
+// what they printed.
+const workspaceLogName = "tools.log"
+
+// toolLogTailSize is how much of the end of the tool log is read for why a
+// tool failed.
+const toolLogTailSize = 16 << 10
+
+// workspace is this run's own folder for what it makes along the way: the
+// extracted audio, transcription chunks, and every other intermediate. It's
+// made the first time one is, named after the first video opened, and held
+// with a lock so tsplice clean leaves it alone while the run lasts. The
+// transcript and project files aren't in it, they're kept next to the video
+// so later runs find them.
+var workspace struct {
+	mu    sync.Mutex
+	input string
+	dir   string
+	lock  *os.File
+	log   *os.File
+}
+
+func workspacesDir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "workspaces"), nil
+}
+
+// setWorkspaceInput names this run's workspace after a video, unless it was
+// named after another one already.
+func setWorkspaceInput(inputFile string) {
+	workspace.mu.Lock()
+	defer workspace.mu.Unlock()
+	if workspace.input == "" {
+		workspace.input = inputFile
+	}
+}
+
+// workspaceFolderName tells apart the workspaces of videos with the same
+// name in different folders.
+func workspaceFolderName(inputFile string) string {
+	if inputFile == "" {
+		return "tsplice"
+	}
+	absolute, err := filepath.Abs(inputFile)
+	if err != nil {
+		absolute = inputFile
+	}
+	sum := sha256.Sum256([]byte(absolute))
+	name := tagSlug(strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile)))
+	if name == "" {
+		name = "video"
+	}
+	return name + "-" + hex.EncodeToString(sum[:4])
+}
+
+// workspaceDir is this run's workspace, made and locked on first use.
+func workspaceDir() (string, error) {
+	workspace.mu.Lock()
+	defer workspace.mu.Unlock()
+	if workspace.dir != "" {
+		return workspace.dir, nil
+	}
+
+	root, err := workspacesDir()
+	if err != nil {
+		return "", err
+	}
+	run := fmt.Sprintf("%s-%d", time.Now().Format("20060102-150405"), os.Getpid())
+	dir := filepath.Join(root, workspaceFolderName(workspace.input), run)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// Held until tsplice exits, when the system lets go of it
+	lock, err := os.OpenFile(filepath.Join(dir, ".lock"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return "", err
+	}
+	if err := lockFile(lock); err != nil {
+		lock.Close()
+		return "", err
+	}
+	workspace.dir, workspace.lock = dir, lock
+
+	// Nothing in it is encrypted, so it goes with the run while encrypting
+	atShutdown(func() {
+		if encryptAtRest {
+			os.RemoveAll(dir)
+		}
+	})
+	return dir, nil
+}
+
+// intermediatePath is where a file that's only needed during this run goes,
+// in the workspace, or the current folder when there can't be one.
+func intermediatePath(name string) string {
+	dir, err := workspaceDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// toolLog is where tools tsplice runs write their errors, for looking into
+// a run that went wrong. Nothing is kept when there's no workspace.
+func toolLog(name string, args []string) io.Writer {
+	rememberCommand(name, args)
+	dir, err := workspaceDir()
+	if err != nil {
+		return nil
+	}
+	workspace.mu.Lock()
+	defer workspace.mu.Unlock()
+	if workspace.log == nil {
+		if workspace.log, err = os.OpenFile(filepath.Join(dir, workspaceLogName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644); err != nil {
+			return nil
+		}
+	}
+	fmt.Fprintf(workspace.log, "\n%s $ %s %s\n", time.Now().Format(time.TimeOnly), name, strings.Join(args, " "))
+	return workspace.log
+}
+
+// toolLogTail is the end of the tool log, where the tool that just failed
+// said why, along with the log's path.
+func toolLogTail() (string, string) {
+	workspace.mu.Lock()
+	defer workspace.mu.Unlock()
+	if workspace.log == nil {
+		return "", ""
+	}
+	file := workspace.log.Name()
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return file, ""
+	}
+	return file, string(content[max(0, len(content)-toolLogTailSize):])
+}
+
+// workspaceRun is one run's workspace found by tsplice clean.
+type workspaceRun struct {
+	dir      string
+	modified time.Time
+	size     int64
+}
+
+// findWorkspaceRuns lists every run's workspace, with when it was last
+// written to and how much it holds.
+func findWorkspaceRuns() ([]workspaceRun, error) {
+	root, err := workspacesDir()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := filepath.Glob(filepath.Join(root, "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []workspaceRun
+	for _, dir := range dirs {
+		run := workspaceRun{dir: dir}
+		filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info, err := entry.Info(); err == nil {
+				run.size += info.Size()
+				if info.ModTime().After(run.modified) {
+					run.modified = info.ModTime()
+				}
+			}
+			return nil
+		})
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// inUse reports whether a tsplice that's still running holds the workspace.
+func (r workspaceRun) inUse() bool {
+	lock, err := os.OpenFile(filepath.Join(r.dir, ".lock"), os.O_RDWR, 0644)
+	if err != nil {
+		return false
+	}
+	defer lock.Close()
+	return lockFile(lock) != nil
+}
+
+// cleanFlags are the options of tsplice clean.
+type cleanFlags struct {
+	olderThan time.Duration
+	all       bool
+	dryRun    bool
+}
+
+func (f *cleanFlags) define(fs *flag.FlagSet) {
+	fs.DurationVar(&f.olderThan, "older-than", 7*24*time.Hour, "Only remove workspaces last used longer ago than this")
+	fs.BoolVar(&f.all, "all", false, "Remove every workspace that isn't in use, however recent")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "List the workspaces that would be removed without removing them")
+}
+
+func runClean(args []string) error {
+	fs := newCommandFlagSet("clean")
+	var flags cleanFlags
+	flags.define(fs)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("usage: tsplice clean [options]")
+	}
+
+	runs, err := findWorkspaceRuns()
+	if err != nil {
+		return err
+	}
+	root, _ := workspacesDir()
+
+	removed, inUse := 0, 0
+	var freed int64
+	for _, run := range runs {
+		if !flags.all && time.Since(run.modified) < flags.olderThan {
+			continue
+		}
+		if run.inUse() {
+			inUse++
+			continue
+		}
+		name, _ := filepath.Rel(root, run.dir)
+		fmt.Println(BulletStyle.Render("├────") + TextStyle.Render(name) + DimTextStyle.Render("  "+formatBytes(run.size)+", last used "+run.modified.Format("Jan 2 15:04")))
+		if !flags.dryRun {
+			if err := os.RemoveAll(run.dir); err != nil {
+				fmt.Println(BulletStyle.Render("├") + ErrorStyle.Render("Could not remove it: "+err.Error()))
+				continue
+			}
+			// The video's folder goes once its last run has
+			os.Remove(filepath.Dir(run.dir))
+		}
+		removed++
+		freed += run.size
+	}
+
+	if inUse > 0 {
+		fmt.Println(BulletStyle.Render("├") + DimTextStyle.Render(fmt.Sprintf("Left %d workspaces that a running tsplice is using.", inUse)))
+	}
+	switch {
+	case removed == 0:
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render("Nothing to clean up in "+root+"."))
+	case flags.dryRun:
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Would remove %d workspaces, freeing %s.", removed, formatBytes(freed))))
+	default:
+		fmt.Println(BulletStyle.Render("└") + TextStyle.Render(fmt.Sprintf("Removed %d workspaces, freeing %s.", removed, formatBytes(freed))))
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
+
+func isYouTubeURL(input string) bool {
+	return isURL(input) && (strings.Contains(input, "youtube.com/") || strings.Contains(input, "youtu.be/"))
+}
+
+// downloadVideo fetches a remote video with yt-dlp and returns the local path.
+func downloadVideo(url string) (string, error) {
+	out, err := execute.Output(
+		"yt-dlp",
+		"-f", "bv*[ext=mp4]+ba[ext=m4a]/b[ext=mp4]/b",
+		"--merge-output-format", "mp4",
+		"-o", "%(title)s [%(id)s].%(ext)s",
+		"--print", "after_move:filepath",
+		url,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to download video: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// downloadAutoCaptions pulls YouTube's captions for a video and converts them
+// into the transcript model, preferring srv3 since its cues don't overlap.
+func downloadAutoCaptions(url string, lang string, basename string) ([]TranscriptItem, error) {
+	if lang == "" || lang == "auto" {
+		lang = "en"
+	}
+
+	err := execute.Run(
+		"yt-dlp",
+		"--skip-download",
+		"--write-subs",
+		"--write-auto-subs",
+		"--sub-format", "srv3/vtt",
+		"--sub-langs", lang+".*",
+		"-o", basename+".%(ext)s",
+		url,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download captions: %w", err)
+	}
+
+	matches, _ := filepath.Glob(basename + ".*.srv3")
+	vttMatches, _ := filepath.Glob(basename + ".*.vtt")
+	matches = append(matches, vttMatches...)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no captions available for this video")
+	}
+	defer func() {
+		for _, match := range matches {
+			os.Remove(match)
+		}
+	}()
+
+	content, err := os.ReadFile(matches[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read captions: %w", err)
+	}
+
+	if strings.HasSuffix(matches[0], ".srv3") {
+		return parseSRV3(content)
+	}
+	return parseVTT(string(content))
+}
+
+type srv3Document struct {
+	Paragraphs []struct {
+		Start    int    `xml:"t,attr"`
+		Duration int    `xml:"d,attr"`
+		Text     string `xml:",chardata"`
+		Words    []struct {
+			Text string `xml:",chardata"`
+		} `xml:"s"`
+	} `xml:"body>p"`
+}
+
+func parseSRV3(content []byte) ([]TranscriptItem, error) {
+	var doc srv3Document
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse srv3 captions: %w", err)
+	}
+
+	var transcriptItems []TranscriptItem
+	for _, p := range doc.Paragraphs {
+		text := p.Text
+		if len(p.Words) > 0 {
+			text = ""
+			for _, word := range p.Words {
+				text += word.Text
+			}
+		}
+
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" {
+			continue
+		}
+
+		transcriptItems = append(transcriptItems, TranscriptItem{
+			StartTime: formatTimestamp(float64(p.Start) / 1000),
+			EndTime:   formatTimestamp(float64(p.Start+p.Duration) / 1000),
+			Text:      text,
+		})
+	}
+
+	return transcriptItems, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const zenLines = 3
+
+// renderZen draws an item as wrapped text with only its selection marker, for
+// reading through a talk without the timestamps getting in the way.
+func (d itemDelegate) renderZen(w io.Writer, m list.Model, index int, i item, checkbox string) {
+	width := max(20, m.Width()-4)
+	wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(i.title), "\n")
+	if len(wrapped) > zenLines {
+		wrapped = wrapped[:zenLines]
+		wrapped[zenLines-1] = strings.TrimRight(wrapped[zenLines-1], " ") + "…"
+	}
+
+	style := ZenTextStyle
+	if ruleStyle, ok := d.colorRules.match(i); ok {
+		style = style.Inherit(ruleStyle)
+	}
+	marker := "  "
+	if index == m.Index() {
+		style = ZenSelectedTextStyle
+		marker = "> "
+	} else if d.inRange(m, i) {
+		marker = RangeItemStyle.Render("┃ ")
+	}
+
+	var lines []string
+	if index == m.Index() && d.editing != "" {
+		wrapped = nil
+		lines = append(lines, marker+checkbox+" "+d.editing)
+	}
+	for n, line := range wrapped {
+		prefix := "  "
+		if n == 0 {
+			prefix = checkbox + " "
+		}
+		lines = append(lines, marker+style.Render(prefix+strings.TrimRight(line, " ")))
+		marker = "  "
+	}
+	for len(lines) < zenLines {
+		lines = append(lines, "")
+	}
+
+	fmt.Fprint(w, strings.Join(lines, "\n"))
+}